@@ -0,0 +1,77 @@
+// Package api holds the models of the cadeft server described by openapi.yaml, the server writes them and the client reads them.
+package api
+
+import (
+	"time"
+
+	"github.com/moov-io/cadeft"
+)
+
+// StoredFile is a file kept by the server under its ID
+type StoredFile struct {
+	ID        string      `json:"id"`
+	CreatedAt time.Time   `json:"created_at"`
+	File      cadeft.File `json:"file"`
+}
+
+// FileSummary describes a stored file in the list of files
+type FileSummary struct {
+	ID                 string    `json:"id"`
+	CreatedAt          time.Time `json:"created_at"`
+	OriginatorID       string    `json:"originator_id,omitempty"`
+	FileCreationNumber int64     `json:"file_creation_number,omitempty"`
+	TxnCount           int       `json:"txn_count"`
+}
+
+// NewFileSummary summarizes a stored file, the originator ID and file creation number are left out when it has no header
+func NewFileSummary(f StoredFile) FileSummary {
+	summary := FileSummary{ID: f.ID, CreatedAt: f.CreatedAt, TxnCount: len(f.File.Txns)}
+	if f.File.Header != nil {
+		summary.OriginatorID, summary.FileCreationNumber = f.File.Header.OriginatorID, f.File.Header.FileCreationNum
+	}
+	return summary
+}
+
+// ValidationResult is the outcome of validating a file, Errors holds every field that failed validation
+type ValidationResult struct {
+	Valid  bool              `json:"valid"`
+	Errors []ValidationError `json:"errors,omitempty"`
+}
+
+// ValidationError is a single field that failed validation, Message is the English message of the cadeft.ValidationError it reports
+type ValidationError struct {
+	// TxnIndex is the position of the transaction in File.Txns, nil for the file header and file level errors
+	TxnIndex    *int              `json:"txn_index,omitempty"`
+	RecordType  cadeft.RecordType `json:"record_type,omitempty"`
+	Field       string            `json:"field,omitempty"`
+	JSONField   string            `json:"json_field,omitempty"`
+	FieldNumber int               `json:"field_number,omitempty"`
+	Rule        string            `json:"rule,omitempty"`
+	Param       string            `json:"param,omitempty"`
+	Value       any               `json:"value,omitempty"`
+	Message     string            `json:"message"`
+}
+
+// NewValidationError reports v, its Err is only kept as the message
+func NewValidationError(v *cadeft.ValidationError) ValidationError {
+	return ValidationError{
+		TxnIndex:    v.TxnIndex,
+		RecordType:  v.RecordType,
+		Field:       v.Field,
+		JSONField:   v.JSONField,
+		FieldNumber: v.FieldNumber,
+		Rule:        v.Rule,
+		Param:       v.Param,
+		Value:       v.Value,
+		Message:     v.Error(),
+	}
+}
+
+func (v ValidationError) Error() string {
+	return v.Message
+}
+
+// ErrorResponse is the body of every failed request
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
+)
+
+// TestOpenAPI checks the OpenAPI document against the models so that neither drifts from the other
+func TestOpenAPI(t *testing.T) {
+	r := require.New(t)
+	raw, err := os.ReadFile("openapi.yaml")
+	r.NoError(err)
+	var doc struct {
+		OpenAPI    string                    `yaml:"openapi"`
+		Paths      map[string]map[string]any `yaml:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Required   []string       `yaml:"required"`
+				Properties map[string]any `yaml:"properties"`
+			} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	r.NoError(yaml.Unmarshal(raw, &doc))
+	r.True(strings.HasPrefix(doc.OpenAPI, "3."))
+
+	operations := map[string][]string{
+		"/health":              {"get"},
+		"/files":               {"get", "post"},
+		"/files/parse":         {"post"},
+		"/files/validate":      {"post"},
+		"/files/{id}":          {"get", "delete"},
+		"/files/{id}/contents": {"get"},
+		"/files/{id}/validate": {"get"},
+	}
+	r.Len(doc.Paths, len(operations))
+	for path, methods := range operations {
+		r.Contains(doc.Paths, path)
+		for _, method := range methods {
+			r.Contains(doc.Paths[path], method, path)
+		}
+	}
+
+	for name, model := range map[string]any{
+		"StoredFile":       StoredFile{},
+		"FileSummary":      FileSummary{},
+		"ValidationResult": ValidationResult{},
+		"ValidationError":  ValidationError{},
+		"Error":            ErrorResponse{},
+	} {
+		schema, ok := doc.Components.Schemas[name]
+		r.True(ok, name)
+		typ := reflect.TypeOf(model)
+		var fields, required []string
+		for i := 0; i < typ.NumField(); i++ {
+			tag := strings.Split(typ.Field(i).Tag.Get("json"), ",")
+			fields = append(fields, tag[0])
+			if len(tag) == 1 {
+				required = append(required, tag[0])
+			}
+		}
+		var properties []string
+		for p := range schema.Properties {
+			properties = append(properties, p)
+		}
+		r.ElementsMatch(fields, properties, name)
+		r.ElementsMatch(required, schema.Required, name)
+	}
+}
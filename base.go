@@ -1,20 +1,24 @@
 package cadeft
 
+import "strings"
+
 // BaseTxn represents the common fields of every Transaction record D, C, E, F, I and J
 type BaseTxn struct {
-	TxnType               TransactionType `json:"txn_type" validate:"required,numeric,max=3"`
-	Amount                int64           `json:"amount" validate:"required,max=9999999999"`
-	ItemTraceNo           string          `json:"item_trace_no" validate:"eft_num,max=22"`
-	InstitutionID         string          `json:"institution_id" validate:"required,eft_num,max=9"`
-	StoredTransactionType TransactionType `json:"stored_txn_type" validate:"eft_num,max=3"`
-	OriginatorShortName   string          `json:"short_name" validate:"max=15"`
-	OriginatorLongName    string          `json:"long_name" validate:"required,max=30"`
-	UserID                string          `json:"user_id" validate:"eft_alpha,max=10"`
-	CrossRefNo            string          `json:"cross_ref_no" validate:"eft_alpha,max=19"`
-	SundryInfo            string          `json:"sundry_info" validate:"eft_alpha,max=15"`
-	SettlementCode        string          `json:"settlement_code" validate:"eft_alpha,max=2"`
-	InvalidDataElementID  string          `json:"invalid_data_element_id" validate:"eft_num,max=11"`
-	RecordType            RecordType      `json:"type" validate:"rec_type"`
+	TxnType               TransactionType `json:"txn_type" validate:"required,numeric,max=3" spec:"4"`
+	Amount                int64           `json:"amount" validate:"required,max=9999999999" spec:"5"`
+	ItemTraceNo           string          `json:"item_trace_no" validate:"eft_num,max=22" spec:"9"`
+	InstitutionID         string          `json:"institution_id" validate:"required,eft_num,max=9" spec:"7"`
+	StoredTransactionType TransactionType `json:"stored_txn_type" validate:"eft_num,max=3" spec:"10"`
+	OriginatorShortName   string          `json:"short_name" validate:"max=15" spec:"11"`
+	OriginatorLongName    string          `json:"long_name" validate:"required,max=30" spec:"13"`
+	UserID                string          `json:"user_id" validate:"eft_alpha,max=10" spec:"14"`
+	CrossRefNo            string          `json:"cross_ref_no" validate:"eft_alpha,max=19" spec:"15"`
+	SundryInfo            string          `json:"sundry_info" validate:"eft_alpha,max=15" spec:"18"`
+	SettlementCode        string          `json:"settlement_code" validate:"eft_alpha,max=2" spec:"20"`
+	InvalidDataElementID  string          `json:"invalid_data_element_id" validate:"eft_num,max=11" spec:"21"`
+	RecordType            RecordType      `json:"type" validate:"rec_type" spec:"1"`
+
+	raw *rawRecord
 }
 type BaseTxnOpt func(d *BaseTxn)
 
@@ -55,13 +59,24 @@ func WithStoredTransactionType(s string) BaseTxnOpt {
 }
 
 // WithInvalidDataElementID sets the Invalid Data Element No. field 21
-// mainly used for returns to indicate which fields are incorrect
+// used by returns (J and I records) to carry the CPA return reason, see BaseTxn.ReturnReasonCode
 func WithInvalidDataElementID(s string) BaseTxnOpt {
 	return func(d *BaseTxn) {
 		d.InvalidDataElementID = s
 	}
 }
 
+// ReturnReasonCode returns the CPA return reason of a returned item, such as 905 for a closed account, empty when there is none.
+// Returns carry it in the first three digits of the Invalid Data Element No. field 21,
+// their Stored Transaction Type field 10 holding the type of the original transaction.
+func (b BaseTxn) ReturnReasonCode() string {
+	id := strings.TrimSpace(b.InvalidDataElementID)
+	if isBlankNumeric(id) {
+		return ""
+	}
+	return padNumericStringWithTrailingZeros(id, 3)[:3]
+}
+
 // GetAmount returns the amount being transacted
 func (b BaseTxn) GetAmount() int64 {
 	return b.Amount
@@ -0,0 +1,89 @@
+package cadeft
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/moov-io/cadeft/calendar"
+)
+
+// BusinessDayRoll decides in which direction AdjustBusinessDays moves a date that is not a business day
+type BusinessDayRoll int
+
+const (
+	// RollFollowing moves dates to the next business day
+	RollFollowing BusinessDayRoll = iota
+	// RollPreceding moves dates to the previous business day
+	RollPreceding
+)
+
+// DateAdjustment records a transaction date that was moved by AdjustBusinessDays
+type DateAdjustment struct {
+	TxnIndex int       `json:"txn_index"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+}
+
+// ValidateBusinessDays flags every transaction whose due date or date funds available falls on a weekend or on a holiday of cal.
+// Any error that is encountered will be appended to a multierror and returned to the caller.
+func (f File) ValidateBusinessDays(cal *calendar.Calendar) error {
+	var err error
+	for i, t := range f.Txns {
+		date := t.GetDate()
+		if date == nil || cal.IsBusinessDay(*date) {
+			continue
+		}
+		err = multierror.Append(err, newError(msgTxn, i, nonBusinessDayError(cal, *date)))
+	}
+	return err
+}
+
+// AdjustBusinessDays moves the due date or date funds available of every transaction that does not fall on a business day of cal
+// to the next or previous business day depending on roll. The moved dates are returned so they can be reported back to the originator.
+func (f *File) AdjustBusinessDays(cal *calendar.Calendar, roll BusinessDayRoll) ([]DateAdjustment, error) {
+	adjustments := make([]DateAdjustment, 0)
+	for i, t := range f.Txns {
+		date := t.GetDate()
+		if date == nil || cal.IsBusinessDay(*date) {
+			continue
+		}
+		adjusted := cal.Following(*date)
+		if roll == RollPreceding {
+			adjusted = cal.Preceding(*date)
+		}
+		// dates are often shared between transactions so a new pointer is set instead of updating the existing one
+		if !setTxnDate(t, Ptr(adjusted)) {
+			return nil, fmt.Errorf("txn %d: date cannot be set on %T", i, t)
+		}
+		adjustments = append(adjustments, DateAdjustment{TxnIndex: i, From: *date, To: adjusted})
+	}
+	return adjustments, nil
+}
+
+func nonBusinessDayError(cal *calendar.Calendar, date time.Time) error {
+	if h, ok := cal.Holiday(date); ok {
+		return newError(msgNonBusinessDayHoliday, ErrNonBusinessDay, date.Format(time.DateOnly), h.Name)
+	}
+	return newError(msgNonBusinessDayWeekday, ErrNonBusinessDay, date.Format(time.DateOnly), date.Weekday())
+}
+
+func setTxnDate(t Transaction, date *time.Time) bool {
+	switch txn := t.(type) {
+	case *Debit:
+		txn.DueDate = date
+	case *Credit:
+		txn.DateFundsAvailable = date
+	case *DebitReturn:
+		txn.DueDate = date
+	case *CreditReturn:
+		txn.DateFundsAvailable = date
+	case *DebitReverse:
+		txn.DueDate = date
+	case *CreditReverse:
+		txn.DateFundsAvailable = date
+	default:
+		return false
+	}
+	return true
+}
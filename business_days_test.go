@@ -0,0 +1,58 @@
+package cadeft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moov-io/cadeft/calendar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusinessDays(t *testing.T) {
+	cal := calendar.PaymentsCanada()
+	businessDay := time.Date(2023, time.April, 6, 0, 0, 0, 0, time.UTC)
+	goodFriday := time.Date(2023, time.April, 7, 0, 0, 0, 0, time.UTC)
+	sunday := time.Date(2023, time.April, 9, 0, 0, 0, 0, time.UTC)
+	header := NewFileHeader("0000000001", 1, &businessDay, 12345, "CAD")
+	newFile := func() File {
+		return NewFile(header, Transactions{
+			Ptr(NewCredit("450", 1000, &businessDay, "123456789", "12345", "1", "short name", "payee name", "someone", "1231", "12345")),
+			Ptr(NewCredit("450", 1000, &goodFriday, "123456789", "12345", "2", "short name", "payee name", "someone", "1231", "12345")),
+			Ptr(NewDebit("400", 1000, &sunday, "123456789", "12345", "3", "short name", "payor name", "someone", "1231", "12345")),
+			Ptr(NewDebit("400", 1000, &goodFriday, "123456789", "12345", "4", "short name", "payor name", "someone", "1231", "12345")),
+		})
+	}
+
+	t.Run("validate", func(t *testing.T) {
+		r := require.New(t)
+		err := newFile().ValidateBusinessDays(cal)
+		r.ErrorIs(err, ErrNonBusinessDay)
+		r.ErrorContains(err, "txn 1: date is not a business day: 2023-04-07 is Good Friday")
+		r.ErrorContains(err, "txn 2: date is not a business day: 2023-04-09 is a Sunday")
+		r.NoError(NewFile(header, newFile().Txns[:1]).ValidateBusinessDays(cal))
+	})
+
+	t.Run("adjust following", func(t *testing.T) {
+		r := require.New(t)
+		f := newFile()
+		adjustments, err := f.AdjustBusinessDays(cal, RollFollowing)
+		r.NoError(err)
+		r.Len(adjustments, 3)
+		monday := time.Date(2023, time.April, 10, 0, 0, 0, 0, time.UTC)
+		r.Equal(DateAdjustment{TxnIndex: 2, From: sunday, To: monday}, adjustments[1])
+		r.Equal(monday, *f.Txns[1].GetDate())
+		r.Equal(monday, *f.Txns[3].GetDate())
+		r.NoError(f.ValidateBusinessDays(cal))
+		// shared dates are left untouched
+		r.Equal(time.Date(2023, time.April, 7, 0, 0, 0, 0, time.UTC), goodFriday)
+	})
+
+	t.Run("adjust preceding", func(t *testing.T) {
+		r := require.New(t)
+		f := newFile()
+		_, err := f.AdjustBusinessDays(cal, RollPreceding)
+		r.NoError(err)
+		r.Equal(businessDay, *f.Txns[1].GetDate())
+		r.Equal(businessDay, *f.Txns[2].GetDate())
+	})
+}
@@ -0,0 +1,155 @@
+// Package calendar determines Canadian business days so that due dates and funds available dates
+// can be kept off weekends and statutory holidays.
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Holiday is a non-business day observed in a given year
+type Holiday struct {
+	Name string
+	Date time.Time
+}
+
+// Calendar is a set of holiday rules on top of Saturday and Sunday being non-business days.
+// Calendar is safe for concurrent use.
+type Calendar struct {
+	rules []Rule
+
+	mu    sync.Mutex
+	years map[int][]Holiday
+}
+
+// New returns a Calendar observing the provided holiday rules
+func New(rules ...Rule) *Calendar {
+	return &Calendar{
+		rules: rules,
+		years: make(map[int][]Holiday),
+	}
+}
+
+// PaymentsCanada returns the national calendar of days on which Payments Canada does not clear items:
+// New Year's Day, Good Friday, Victoria Day, Canada Day, Civic Holiday, Labour Day, National Day for Truth and Reconciliation,
+// Thanksgiving, Remembrance Day, Christmas Day and Boxing Day.
+func PaymentsCanada() *Calendar {
+	return New(nationalRules()...)
+}
+
+// ForProvince returns the PaymentsCanada calendar with the holidays of the given province or territory added
+func ForProvince(p Province) (*Calendar, error) {
+	provincial, ok := provincialRules[p]
+	if !ok {
+		return nil, fmt.Errorf("unknown province %q", p)
+	}
+	rules := nationalRules()
+	rules = append(rules, provincial...)
+	return New(rules...), nil
+}
+
+// Holidays returns every holiday observed in year ordered by date
+func (c *Calendar) Holidays(year int) []Holiday {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if h, ok := c.years[year]; ok {
+		return append([]Holiday(nil), h...)
+	}
+	holidays := make([]Holiday, 0, len(c.rules))
+	taken := make(map[time.Time]bool)
+	for _, r := range c.rules {
+		if !r.appliesTo(year) {
+			continue
+		}
+		d := r.date(year)
+		if r.observed {
+			// move to the next weekday that is not already taken by another holiday
+			for isWeekend(d) || taken[d] {
+				d = d.AddDate(0, 0, 1)
+			}
+		}
+		taken[d] = true
+		holidays = append(holidays, Holiday{Name: r.Name, Date: d})
+	}
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+	c.years[year] = holidays
+	return append([]Holiday(nil), holidays...)
+}
+
+// Holiday returns the holiday observed on the date of t, if any. Only the year, month and day of t in its own location are considered.
+func (c *Calendar) Holiday(t time.Time) (Holiday, bool) {
+	d := day(t)
+	for _, h := range c.Holidays(d.Year()) {
+		if h.Date.Equal(d) {
+			return h, true
+		}
+	}
+	return Holiday{}, false
+}
+
+// IsBusinessDay reports whether t falls on a weekday that is not a holiday
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	if isWeekend(t) {
+		return false
+	}
+	_, ok := c.Holiday(t)
+	return !ok
+}
+
+// NextBusinessDay returns the first business day after t keeping the time of day and location of t
+func (c *Calendar) NextBusinessDay(t time.Time) time.Time {
+	t = t.AddDate(0, 0, 1)
+	for !c.IsBusinessDay(t) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// PreviousBusinessDay returns the last business day before t keeping the time of day and location of t
+func (c *Calendar) PreviousBusinessDay(t time.Time) time.Time {
+	t = t.AddDate(0, 0, -1)
+	for !c.IsBusinessDay(t) {
+		t = t.AddDate(0, 0, -1)
+	}
+	return t
+}
+
+// Following returns t if it is a business day otherwise the next business day
+func (c *Calendar) Following(t time.Time) time.Time {
+	if c.IsBusinessDay(t) {
+		return t
+	}
+	return c.NextBusinessDay(t)
+}
+
+// Preceding returns t if it is a business day otherwise the previous business day
+func (c *Calendar) Preceding(t time.Time) time.Time {
+	if c.IsBusinessDay(t) {
+		return t
+	}
+	return c.PreviousBusinessDay(t)
+}
+
+// AddBusinessDays moves t by n business days, backwards when n is negative
+func (c *Calendar) AddBusinessDays(t time.Time, n int) time.Time {
+	for ; n > 0; n-- {
+		t = c.NextBusinessDay(t)
+	}
+	for ; n < 0; n++ {
+		t = c.PreviousBusinessDay(t)
+	}
+	return t
+}
+
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+// day truncates t to midnight UTC of its calendar date so holidays can be compared across locations
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
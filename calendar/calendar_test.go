@@ -0,0 +1,98 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestPaymentsCanadaHolidays(t *testing.T) {
+	r := require.New(t)
+	cal := PaymentsCanada()
+	expected := []Holiday{
+		{Name: "New Year's Day", Date: date(2023, time.January, 2)},
+		{Name: "Good Friday", Date: date(2023, time.April, 7)},
+		{Name: "Victoria Day", Date: date(2023, time.May, 22)},
+		{Name: "Canada Day", Date: date(2023, time.July, 3)},
+		{Name: "Civic Holiday", Date: date(2023, time.August, 7)},
+		{Name: "Labour Day", Date: date(2023, time.September, 4)},
+		{Name: "National Day for Truth and Reconciliation", Date: date(2023, time.October, 2)},
+		{Name: "Thanksgiving", Date: date(2023, time.October, 9)},
+		{Name: "Remembrance Day", Date: date(2023, time.November, 13)},
+		{Name: "Christmas Day", Date: date(2023, time.December, 25)},
+		{Name: "Boxing Day", Date: date(2023, time.December, 26)},
+	}
+	r.Equal(expected, cal.Holidays(2023))
+	r.Len(cal.Holidays(2020), 10)
+}
+
+func TestObservedChristmasAndBoxingDay(t *testing.T) {
+	r := require.New(t)
+	cal := PaymentsCanada()
+	cases := map[int][2]time.Time{
+		// Christmas on a Saturday
+		2021: {date(2021, time.December, 27), date(2021, time.December, 28)},
+		// Christmas on a Sunday
+		2022: {date(2022, time.December, 26), date(2022, time.December, 27)},
+	}
+	for year, days := range cases {
+		christmas, ok := cal.Holiday(days[0])
+		r.True(ok)
+		r.Equal("Christmas Day", christmas.Name)
+		boxing, ok := cal.Holiday(days[1])
+		r.True(ok)
+		r.Equal("Boxing Day", boxing.Name, year)
+	}
+}
+
+func TestProvincialHolidays(t *testing.T) {
+	r := require.New(t)
+	_, err := ForProvince("XX")
+	r.Error(err)
+
+	qc, err := ForProvince(Quebec)
+	r.NoError(err)
+	h, ok := qc.Holiday(date(2024, time.April, 1))
+	r.True(ok)
+	r.Equal("Easter Monday", h.Name)
+	r.False(qc.IsBusinessDay(date(2024, time.June, 24)))
+	r.True(PaymentsCanada().IsBusinessDay(date(2024, time.April, 1)))
+
+	on, err := ForProvince(Ontario)
+	r.NoError(err)
+	r.False(on.IsBusinessDay(date(2024, time.February, 19)))
+	r.True(on.IsBusinessDay(date(2007, time.February, 19)))
+
+	bc, err := ForProvince(BritishColumbia)
+	r.NoError(err)
+	r.False(bc.IsBusinessDay(date(2018, time.February, 12)))
+	r.False(bc.IsBusinessDay(date(2019, time.February, 18)))
+}
+
+func TestBusinessDayHelpers(t *testing.T) {
+	r := require.New(t)
+	cal := PaymentsCanada()
+	toronto, err := time.LoadLocation("America/Toronto")
+	r.NoError(err)
+
+	// Thursday before Good Friday 2023
+	thursday := time.Date(2023, time.April, 6, 9, 30, 0, 0, toronto)
+	r.True(cal.IsBusinessDay(thursday))
+	next := cal.NextBusinessDay(thursday)
+	r.Equal(time.Date(2023, time.April, 10, 9, 30, 0, 0, toronto), next)
+	r.Equal(thursday, cal.PreviousBusinessDay(next))
+
+	saturday := date(2023, time.April, 8)
+	r.False(cal.IsBusinessDay(saturday))
+	r.Equal(date(2023, time.April, 10), cal.Following(saturday))
+	r.Equal(date(2023, time.April, 6), cal.Preceding(saturday))
+	r.Equal(thursday, cal.Following(thursday))
+
+	r.Equal(date(2023, time.April, 11), cal.AddBusinessDays(date(2023, time.April, 6), 2))
+	r.Equal(date(2023, time.April, 5), cal.AddBusinessDays(date(2023, time.April, 10), -2))
+}
@@ -0,0 +1,179 @@
+package calendar
+
+import "time"
+
+// Rule computes the date of a holiday for a given year
+type Rule struct {
+	Name string
+
+	date     func(year int) time.Time
+	observed bool
+	from     int
+	to       int
+}
+
+// From returns a copy of the rule that only applies from year onwards
+func (r Rule) From(year int) Rule {
+	r.from = year
+	return r
+}
+
+// To returns a copy of the rule that only applies up to and including year
+func (r Rule) To(year int) Rule {
+	r.to = year
+	return r
+}
+
+func (r Rule) appliesTo(year int) bool {
+	if r.from != 0 && year < r.from {
+		return false
+	}
+	if r.to != 0 && year > r.to {
+		return false
+	}
+	return true
+}
+
+// FixedDate is a holiday on the same day every year. When it falls on a weekend, or on a day already
+// taken by another holiday, it is observed on the following weekday.
+func FixedDate(name string, month time.Month, dayOfMonth int) Rule {
+	return Rule{
+		Name: name,
+		date: func(year int) time.Time {
+			return time.Date(year, month, dayOfMonth, 0, 0, 0, 0, time.UTC)
+		},
+		observed: true,
+	}
+}
+
+// NthWeekday is a holiday on the nth weekday of month, for example the first Monday of September
+func NthWeekday(name string, month time.Month, weekday time.Weekday, n int) Rule {
+	return Rule{
+		Name: name,
+		date: func(year int) time.Time {
+			d := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+			offset := (int(weekday) - int(d.Weekday()) + 7) % 7
+			return d.AddDate(0, 0, offset+7*(n-1))
+		},
+	}
+}
+
+// WeekdayBefore is a holiday on the last weekday strictly before the given day of month, for example Victoria Day
+// is the last Monday before May 25
+func WeekdayBefore(name string, month time.Month, dayOfMonth int, weekday time.Weekday) Rule {
+	return Rule{
+		Name: name,
+		date: func(year int) time.Time {
+			d := time.Date(year, month, dayOfMonth, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+			offset := (int(d.Weekday()) - int(weekday) + 7) % 7
+			return d.AddDate(0, 0, -offset)
+		},
+	}
+}
+
+// EasterOffset is a holiday a number of days from Easter Sunday, Good Friday is -2 and Easter Monday is 1
+func EasterOffset(name string, days int) Rule {
+	return Rule{
+		Name: name,
+		date: func(year int) time.Time {
+			return easter(year).AddDate(0, 0, days)
+		},
+	}
+}
+
+// easter computes Easter Sunday in the Gregorian calendar using the anonymous Gregorian algorithm
+func easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	dayOfMonth := ((h + l - 7*m + 114) % 31) + 1
+	return time.Date(year, time.Month(month), dayOfMonth, 0, 0, 0, 0, time.UTC)
+}
+
+var (
+	NewYearsDay                   = FixedDate("New Year's Day", time.January, 1)
+	GoodFriday                    = EasterOffset("Good Friday", -2)
+	EasterMonday                  = EasterOffset("Easter Monday", 1)
+	VictoriaDay                   = WeekdayBefore("Victoria Day", time.May, 25, time.Monday)
+	CanadaDay                     = FixedDate("Canada Day", time.July, 1)
+	CivicHoliday                  = NthWeekday("Civic Holiday", time.August, time.Monday, 1)
+	LabourDay                     = NthWeekday("Labour Day", time.September, time.Monday, 1)
+	TruthAndReconciliationDay     = FixedDate("National Day for Truth and Reconciliation", time.September, 30).From(2021)
+	Thanksgiving                  = NthWeekday("Thanksgiving", time.October, time.Monday, 2)
+	RemembranceDay                = FixedDate("Remembrance Day", time.November, 11)
+	ChristmasDay                  = FixedDate("Christmas Day", time.December, 25)
+	BoxingDay                     = FixedDate("Boxing Day", time.December, 26)
+	FamilyDay                     = NthWeekday("Family Day", time.February, time.Monday, 3)
+	NationalIndigenousPeoplesDay  = FixedDate("National Indigenous Peoples Day", time.June, 21)
+	SaintJeanBaptisteDay          = FixedDate("Saint-Jean-Baptiste Day", time.June, 24)
+	NationalPatriotsDay           = WeekdayBefore("National Patriots' Day", time.May, 25, time.Monday)
+	NunavutDay                    = FixedDate("Nunavut Day", time.July, 9)
+	DiscoveryDay                  = NthWeekday("Discovery Day", time.August, time.Monday, 3)
+	LouisRielDay                  = NthWeekday("Louis Riel Day", time.February, time.Monday, 3)
+	HeritageDay                   = NthWeekday("Heritage Day", time.February, time.Monday, 3)
+	IslanderDay                   = NthWeekday("Islander Day", time.February, time.Monday, 3)
+	britishColumbiaEarlyFamilyDay = NthWeekday("Family Day", time.February, time.Monday, 2)
+)
+
+// nationalRules returns the holidays on which Payments Canada does not clear, ordered so that Boxing Day is
+// observed after Christmas Day when both fall on a weekend
+func nationalRules() []Rule {
+	return []Rule{
+		NewYearsDay,
+		GoodFriday,
+		VictoriaDay,
+		CanadaDay,
+		CivicHoliday,
+		LabourDay,
+		TruthAndReconciliationDay,
+		Thanksgiving,
+		RemembranceDay,
+		ChristmasDay,
+		BoxingDay,
+	}
+}
+
+// Province is the two letter postal abbreviation of a Canadian province or territory
+type Province string
+
+const (
+	Alberta                 Province = "AB"
+	BritishColumbia         Province = "BC"
+	Manitoba                Province = "MB"
+	NewBrunswick            Province = "NB"
+	NewfoundlandAndLabrador Province = "NL"
+	NovaScotia              Province = "NS"
+	NorthwestTerritories    Province = "NT"
+	Nunavut                 Province = "NU"
+	Ontario                 Province = "ON"
+	PrinceEdwardIsland      Province = "PE"
+	Quebec                  Province = "QC"
+	Saskatchewan            Province = "SK"
+	Yukon                   Province = "YT"
+)
+
+var provincialRules = map[Province][]Rule{
+	Alberta:                 {FamilyDay.From(1990)},
+	BritishColumbia:         {britishColumbiaEarlyFamilyDay.From(2013).To(2018), FamilyDay.From(2019)},
+	Manitoba:                {LouisRielDay.From(2008)},
+	NewBrunswick:            {FamilyDay.From(2018)},
+	NewfoundlandAndLabrador: {},
+	NovaScotia:              {HeritageDay.From(2015)},
+	NorthwestTerritories:    {NationalIndigenousPeoplesDay.From(2001)},
+	Nunavut:                 {NunavutDay.From(2001)},
+	Ontario:                 {FamilyDay.From(2008)},
+	PrinceEdwardIsland:      {IslanderDay.From(2009)},
+	Quebec:                  {EasterMonday, NationalPatriotsDay.From(2003), SaintJeanBaptisteDay},
+	Saskatchewan:            {FamilyDay.From(2007)},
+	Yukon:                   {DiscoveryDay, NationalIndigenousPeoplesDay.From(2017)},
+}
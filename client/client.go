@@ -0,0 +1,170 @@
+// Package client is a typed Go client of the cadeft server, its endpoints follow the OpenAPI document in api/openapi.yaml
+// and its requests and responses are the models of package api.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/moov-io/cadeft"
+	"github.com/moov-io/cadeft/api"
+)
+
+// DefaultBaseURL is the address of a server started with the default flags of cmd/server
+const DefaultBaseURL = "http://localhost:8088"
+
+// Error is returned for every response with an unexpected status code
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("cadeft server responded %d: %s", e.StatusCode, e.Message)
+}
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+type Opt func(*Client)
+
+// WithHTTPClient sets the http.Client requests are sent with, http.DefaultClient by default
+func WithHTTPClient(c *http.Client) Opt {
+	return func(cl *Client) {
+		cl.httpClient = c
+	}
+}
+
+// New returns a Client of the server at baseURL, DefaultBaseURL when empty
+func New(baseURL string, opts ...Opt) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	c := &Client{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Health checks that the server is running
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/health", nil, "", http.StatusOK, nil)
+}
+
+// CreateFile stores f on the server as is
+func (c *Client) CreateFile(ctx context.Context, f cadeft.File) (api.StoredFile, error) {
+	var stored api.StoredFile
+	body, err := json.Marshal(f)
+	if err != nil {
+		return stored, fmt.Errorf("failed to write file JSON: %w", err)
+	}
+	err = c.do(ctx, http.MethodPost, "/files", bytes.NewReader(body), "application/json", http.StatusCreated, &stored)
+	return stored, err
+}
+
+// ParseFile sends a CPA-005 file to be parsed and stored by the server
+func (c *Client) ParseFile(ctx context.Context, contents io.Reader) (api.StoredFile, error) {
+	var stored api.StoredFile
+	err := c.do(ctx, http.MethodPost, "/files/parse", contents, "text/plain", http.StatusCreated, &stored)
+	return stored, err
+}
+
+// ValidateFile validates f without storing it, profile names a registered validation profile and may be empty
+func (c *Client) ValidateFile(ctx context.Context, f cadeft.File, profile string) (api.ValidationResult, error) {
+	var result api.ValidationResult
+	body, err := json.Marshal(f)
+	if err != nil {
+		return result, fmt.Errorf("failed to write file JSON: %w", err)
+	}
+	err = c.do(ctx, http.MethodPost, "/files/validate"+profileQuery(profile), bytes.NewReader(body), "application/json", http.StatusOK, &result)
+	return result, err
+}
+
+// ValidateStoredFile validates the stored file id, profile names a registered validation profile and may be empty
+func (c *Client) ValidateStoredFile(ctx context.Context, id, profile string) (api.ValidationResult, error) {
+	var result api.ValidationResult
+	err := c.do(ctx, http.MethodGet, "/files/"+url.PathEscape(id)+"/validate"+profileQuery(profile), nil, "", http.StatusOK, &result)
+	return result, err
+}
+
+// ListFiles returns the summaries of the stored files ordered by creation time
+func (c *Client) ListFiles(ctx context.Context) ([]api.FileSummary, error) {
+	var summaries []api.FileSummary
+	err := c.do(ctx, http.MethodGet, "/files", nil, "", http.StatusOK, &summaries)
+	return summaries, err
+}
+
+// GetFile returns the stored file id
+func (c *Client) GetFile(ctx context.Context, id string) (api.StoredFile, error) {
+	var stored api.StoredFile
+	err := c.do(ctx, http.MethodGet, "/files/"+url.PathEscape(id), nil, "", http.StatusOK, &stored)
+	return stored, err
+}
+
+// GetFileContents returns the stored file id written as CPA-005
+func (c *Client) GetFileContents(ctx context.Context, id string) (string, error) {
+	var contents bytes.Buffer
+	err := c.do(ctx, http.MethodGet, "/files/"+url.PathEscape(id)+"/contents", nil, "", http.StatusOK, &contents)
+	return contents.String(), err
+}
+
+// DeleteFile deletes the stored file id
+func (c *Client) DeleteFile(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/files/"+url.PathEscape(id), nil, "", http.StatusNoContent, nil)
+}
+
+func profileQuery(profile string) string {
+	if profile == "" {
+		return ""
+	}
+	return "?" + url.Values{"profile": {profile}}.Encode()
+}
+
+// do sends a request and reads a response of status want into out, a *bytes.Buffer receives the raw body and any other value is decoded as JSON
+func (c *Client) do(ctx context.Context, method, path string, body io.Reader, contentType string, want int, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != want {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		var errResp api.ErrorResponse
+		raw, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(raw, &errResp) == nil && errResp.Error != "" {
+			apiErr.Message = errResp.Error
+		} else {
+			apiErr.Message = strings.TrimSpace(string(raw))
+		}
+		return apiErr
+	}
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case *bytes.Buffer:
+		_, err = out.ReadFrom(resp.Body)
+		return err
+	default:
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to read %s %s response: %w", method, path, err)
+		}
+		return nil
+	}
+}
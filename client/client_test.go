@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/moov-io/cadeft"
+	"github.com/moov-io/cadeft/server"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleFile reads the sample file shipped with the repository, six credits followed by five debits
+func sampleFile(t *testing.T) cadeft.File {
+	t.Helper()
+	in, err := os.Open("../sample_files/CO14821.txt")
+	require.NoError(t, err)
+	defer in.Close()
+	f, err := cadeft.NewReader(in).ReadFile()
+	require.NoError(t, err)
+	return f
+}
+
+func testClient(t *testing.T) *Client {
+	t.Helper()
+	srv := httptest.NewServer(server.NewServer().Handler())
+	t.Cleanup(srv.Close)
+	return New(srv.URL+"/", WithHTTPClient(srv.Client()))
+}
+
+func TestClient(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	c := testClient(t)
+	r.NoError(c.Health(ctx))
+
+	created, err := c.CreateFile(ctx, sampleFile(t))
+	r.NoError(err)
+	r.NotEmpty(created.ID)
+	r.Len(created.File.Txns, 11)
+	r.Equal(cadeft.DebitRecord, created.File.Txns[6].GetType())
+
+	fetched, err := c.GetFile(ctx, created.ID)
+	r.NoError(err)
+	r.Equal(created.ID, fetched.ID)
+	r.Equal("0000000610", fetched.File.Header.OriginatorID)
+
+	contents, err := c.GetFileContents(ctx, created.ID)
+	r.NoError(err)
+	r.Equal("0000000610", contents[10:20])
+
+	result, err := c.ValidateStoredFile(ctx, created.ID, "")
+	r.NoError(err)
+	r.True(result.Valid)
+
+	sample, err := os.Open("../sample_files/CO14821.txt")
+	r.NoError(err)
+	defer sample.Close()
+	parsed, err := c.ParseFile(ctx, sample)
+	r.NoError(err)
+	r.NotEmpty(parsed.File.Txns)
+
+	// a parsed file is written back as CPA-005 that parses to the same transactions
+	parsedContents, err := c.GetFileContents(ctx, parsed.ID)
+	r.NoError(err)
+	reparsed, err := c.ParseFile(ctx, strings.NewReader(parsedContents))
+	r.NoError(err)
+	r.Equal(len(parsed.File.Txns), len(reparsed.File.Txns))
+
+	summaries, err := c.ListFiles(ctx)
+	r.NoError(err)
+	r.Len(summaries, 3)
+	r.Equal(created.ID, summaries[0].ID)
+	r.Equal(11, summaries[0].TxnCount)
+
+	r.NoError(c.DeleteFile(ctx, created.ID))
+	_, err = c.GetFile(ctx, created.ID)
+	var apiErr *Error
+	r.True(errors.As(err, &apiErr))
+	r.Equal(http.StatusNotFound, apiErr.StatusCode)
+	r.Equal(server.ErrFileNotFound.Error(), apiErr.Message)
+	r.Error(c.DeleteFile(ctx, created.ID))
+}
+
+func TestClientValidate(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	c := testClient(t)
+
+	f := sampleFile(t)
+	f.Txns[6].(*cadeft.Debit).PayorAccountNo = "ACCOUNT"
+	result, err := c.ValidateFile(ctx, f, "")
+	r.NoError(err)
+	r.False(result.Valid)
+	r.Len(result.Errors, 1)
+	r.Equal(6, *result.Errors[0].TxnIndex)
+	r.Equal(cadeft.DebitRecord, result.Errors[0].RecordType)
+	r.Equal("payor_account_no", result.Errors[0].JSONField)
+	r.Equal(8, result.Errors[0].FieldNumber)
+	r.Equal("numeric", result.Errors[0].Rule)
+	r.Equal("ACCOUNT", result.Errors[0].Value)
+	r.NotEmpty(result.Errors[0].Error())
+
+	_, err = c.ValidateFile(ctx, f, "unknown profile")
+	var apiErr *Error
+	r.True(errors.As(err, &apiErr))
+	r.Equal(http.StatusBadRequest, apiErr.StatusCode)
+	r.Contains(apiErr.Message, "unknown profile")
+
+	_, err = c.ParseFile(ctx, strings.NewReader("not a file"))
+	r.True(errors.As(err, &apiErr))
+	r.Equal(http.StatusBadRequest, apiErr.StatusCode)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/moov-io/cadeft"
+)
+
+// subcommands are run with cadeft <name> [flags], every subcommand adds its own flags to fs before parsing args
+var subcommands = map[string]func(fs *flag.FlagSet, args []string) error{
+	"split":   runSplit,
+	"diff":    runDiff,
+	"explain": runExplain,
+}
+
+func runSubcommand(name string, args []string) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	lang := fs.String("lang", "en", "language of error messages either en or fr")
+	if err := subcommands[name](fs, args); err != nil {
+		log.Fatal(cadeft.Translate(err, cadeft.Locale(*lang)))
+	}
+}
+
+// readEftFile parses the EFT file at path or from stdin when path is empty
+func readEftFile(path string) (cadeft.File, error) {
+	var in io.Reader = os.Stdin
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return cadeft.File{}, fmt.Errorf("failed to open file %s: %w", path, err)
+		}
+		defer file.Close()
+		in = file
+	}
+	raw, err := io.ReadAll(in)
+	if err != nil {
+		return cadeft.File{}, err
+	}
+	return cadeft.NewReader(strings.NewReader(string(raw))).ReadFile()
+}
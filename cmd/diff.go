@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/moov-io/cadeft"
+)
+
+func runDiff(fs *flag.FlagSet, args []string) error {
+	format := fs.String("format", "text", "output format either text or json")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: cadeft diff [-format text|json] <previous file> <new file>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("diff takes exactly two files")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid format %q, can only use text or json", *format)
+	}
+
+	a, err := readEftFile(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to parse file %s: %w", fs.Arg(0), err)
+	}
+	b, err := readEftFile(fs.Arg(1))
+	if err != nil {
+		return fmt.Errorf("failed to parse file %s: %w", fs.Arg(1), err)
+	}
+	d := cadeft.Diff(a, b)
+	if *format == "json" {
+		out, err := d.JSON()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", out)
+	} else {
+		fmt.Print(d.Text())
+	}
+	// like diff(1) exit with status 1 when the files differ
+	if !d.Empty() {
+		os.Exit(1)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/moov-io/cadeft"
+)
+
+func runExplain(fs *flag.FlagSet, args []string) error {
+	format := fs.String("format", "text", "output format either text or json")
+	segment := fs.Int("segment", -1, "index of the transaction to explain within a transaction line starting at 0, every transaction of the line by default")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: cadeft explain [-segment n] [-format text|json] <file or - for stdin> <line number>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("explain takes a file and a line number")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid format %q, can only use text or json", *format)
+	}
+	lineNo, err := strconv.Atoi(fs.Arg(1))
+	if err != nil || lineNo < 1 {
+		return fmt.Errorf("invalid line number %q", fs.Arg(1))
+	}
+
+	var in io.Reader = os.Stdin
+	if path := fs.Arg(0); path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open file %s: %w", path, err)
+		}
+		defer file.Close()
+		in = file
+	}
+	var opts []cadeft.ExplainOpt
+	if *segment >= 0 {
+		opts = append(opts, cadeft.WithSegment(*segment))
+	}
+	explanations, err := cadeft.Explain(in, lineNo, opts...)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		out, err := json.MarshalIndent(explanations, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", out)
+		return nil
+	}
+	for i, e := range explanations {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Print(e.Text())
+	}
+	return nil
+}
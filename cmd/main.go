@@ -12,18 +12,74 @@ import (
 	"github.com/moov-io/cadeft"
 )
 
+// validateFlag is set by -validate on its own to run the built-in validation or by -validate=<profile> to also apply a validation profile
+type validateFlag struct {
+	enabled bool
+	profile string
+}
+
+func (v *validateFlag) String() string {
+	if v == nil || !v.enabled {
+		return "false"
+	}
+	if v.profile != "" {
+		return v.profile
+	}
+	return "true"
+}
+
+func (v *validateFlag) Set(s string) error {
+	switch s {
+	case "true":
+		v.enabled, v.profile = true, ""
+	case "false":
+		v.enabled, v.profile = false, ""
+	default:
+		v.enabled, v.profile = true, s
+	}
+	return nil
+}
+
+func (v *validateFlag) IsBoolFlag() bool { return true }
+
 func main() {
+	if len(os.Args) > 1 {
+		if _, ok := subcommands[os.Args[1]]; ok {
+			runSubcommand(os.Args[1], os.Args[2:])
+			return
+		}
+	}
+
 	var mode string
 	var fileName string
 	flag.StringVar(&mode, "mode", "", "define the usage of the parser either build or parse")
 	flag.StringVar(&fileName, "file", "", "eft file to parse")
-	validate := flag.Bool("validate", false, "apply valdidation to file when building or parsing")
+	var validate validateFlag
+	flag.Var(&validate, "validate", "apply valdidation to file when building or parsing, -validate=<profile> also applies a validation profile")
+	profiles := flag.String("profiles", "", "YAML or JSON file defining validation profiles")
+	lang := flag.String("lang", "en", "language of error messages either en or fr")
 	flag.Parse()
 
 	if mode != "parse" && mode != "build" {
 		log.Fatal("invalid mode flag value, can only use parse or build")
 	}
 
+	if *profiles != "" {
+		loaded, err := cadeft.LoadValidationProfiles(*profiles)
+		if err != nil {
+			log.Fatal(cadeft.Translate(err, cadeft.Locale(*lang)))
+		}
+		for _, p := range loaded {
+			if err := cadeft.RegisterValidationProfile(p); err != nil {
+				log.Fatal(cadeft.Translate(err, cadeft.Locale(*lang)))
+			}
+		}
+	}
+	var validateOpts []cadeft.ValidateOpt
+	if validate.profile != "" {
+		validateOpts = append(validateOpts, cadeft.WithProfile(validate.profile))
+	}
+
 	if mode == "parse" {
 		// open file pointed to by file
 		var eftFile cadeft.File
@@ -35,7 +91,7 @@ func main() {
 			reader := cadeft.NewReader(file)
 			eftFile, err = reader.ReadFile()
 			if err != nil {
-				log.Fatal(fmt.Errorf("failed to parse file: %w", err))
+				log.Fatal(cadeft.Translate(err, cadeft.Locale(*lang)))
 			}
 		} else {
 			stdin, err := io.ReadAll(os.Stdin)
@@ -50,7 +106,12 @@ func main() {
 			reader := cadeft.NewReader(strings.NewReader(rawFile))
 			eftFile, err = reader.ReadFile()
 			if err != nil {
-				log.Fatal(err)
+				log.Fatal(cadeft.Translate(err, cadeft.Locale(*lang)))
+			}
+		}
+		if validate.enabled {
+			if err := eftFile.Validate(validateOpts...); err != nil {
+				log.Fatal(cadeft.Translate(err, cadeft.Locale(*lang)))
 			}
 		}
 		output, err := json.Marshal(eftFile)
@@ -68,14 +129,14 @@ func main() {
 			if err := json.NewDecoder(file).Decode(&eftFile); err != nil {
 				log.Fatal(err)
 			}
-			if *validate {
-				if err := eftFile.Validate(); err != nil {
-					log.Fatal(err)
+			if validate.enabled {
+				if err := eftFile.Validate(validateOpts...); err != nil {
+					log.Fatal(cadeft.Translate(err, cadeft.Locale(*lang)))
 				}
 			}
 			s, err := eftFile.Create()
 			if err != nil {
-				log.Fatal(err)
+				log.Fatal(cadeft.Translate(err, cadeft.Locale(*lang)))
 			}
 			fmt.Printf("%s", s)
 		}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/moov-io/cadeft"
+	"github.com/moov-io/cadeft/server"
+)
+
+func main() {
+	addr := flag.String("addr", server.DefaultAddr, "loopback address to listen on")
+	profiles := flag.String("profiles", "", "YAML or JSON file defining validation profiles")
+	flag.Parse()
+
+	if *profiles != "" {
+		loaded, err := cadeft.LoadValidationProfiles(*profiles)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, p := range loaded {
+			if err := cadeft.RegisterValidationProfile(p); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+
+	listener, err := server.Listen(*addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv := &http.Server{
+		Handler:           server.NewServer().Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdown); err != nil {
+			log.Printf("failed to shut down: %v", err)
+		}
+	}()
+
+	log.Printf("listening on http://%s", listener.Addr())
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
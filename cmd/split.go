@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moov-io/cadeft"
+)
+
+func runSplit(fs *flag.FlagSet, args []string) error {
+	fileName := fs.String("file", "", "eft file to split, read from stdin when empty")
+	maxItems := fs.Int("max-items", 0, "maximum number of transactions per file")
+	maxValue := fs.String("max-value", "", "maximum total value of the transactions of a file such as 1,000,000.00")
+	byType := fs.Bool("by-type", false, "write every record type to files of its own")
+	outDir := fs.String("out", ".", "directory the split files are written to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	limits := cadeft.SplitLimits{MaxItems: *maxItems, ByRecordType: *byType}
+	if *maxValue != "" {
+		value, err := cadeft.ParseCents(*maxValue)
+		if err != nil {
+			return err
+		}
+		limits.MaxValue = int64(value)
+	}
+	eftFile, err := readEftFile(*fileName)
+	if err != nil {
+		return fmt.Errorf("failed to parse file: %w", err)
+	}
+	files, err := cadeft.SplitFile(eftFile, limits)
+	if err != nil {
+		return err
+	}
+
+	prefix := "split"
+	if *fileName != "" {
+		prefix = strings.TrimSuffix(filepath.Base(*fileName), filepath.Ext(*fileName))
+	}
+	for _, f := range files {
+		s, err := f.Create()
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(*outDir, fmt.Sprintf("%s-%04d.txt", prefix, f.Header.FileCreationNum))
+		if err := os.WriteFile(path, []byte(s), 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("%s\t%d transactions\n", path, len(f.Txns))
+	}
+	return nil
+}
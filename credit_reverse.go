@@ -1,20 +1,16 @@
 package cadeft
 
-import (
-	"fmt"
-	"strings"
-	"time"
-)
+import "time"
 
 // CreditReverse represents Logical Record Type E according to the EFT standard 005
 type CreditReverse struct {
 	BaseTxn
-	DateFundsAvailable  *time.Time `json:"date_funds_available" validate:"required"`
-	PayeeAccountNo      string     `json:"payee_account_no" validate:"required,max=12,numeric"`
-	PayeeName           string     `json:"payee_name" validate:"required,max=30"`
-	ReturnInstitutionID string     `json:"return_institution_id" validate:"required,max=9,numeric"`
-	ReturnAccountNo     string     `json:"return_account_no" validate:"required,max=12,eft_alpha"`
-	OriginalItemTraceNo string     `json:"original_item_trace_no" validate:"required,eft_num,max=22"`
+	DateFundsAvailable  *time.Time `json:"date_funds_available" validate:"required" spec:"6"`
+	PayeeAccountNo      string     `json:"payee_account_no" validate:"required,max=12,numeric" spec:"8"`
+	PayeeName           string     `json:"payee_name" validate:"required,max=30" spec:"12"`
+	ReturnInstitutionID string     `json:"return_institution_id" validate:"required,max=9,numeric" spec:"16"`
+	ReturnAccountNo     string     `json:"return_account_no" validate:"required,max=12,eft_alpha" spec:"17"`
+	OriginalItemTraceNo string     `json:"original_item_trace_no" validate:"required,eft_num,max=22" spec:"19"`
 }
 
 func NewCreditReverse(
@@ -56,89 +52,26 @@ func NewCreditReverse(
 }
 
 // Parse takes in a serialized transaction segment and populates a CreditReverse struct containing the relevant data.
-// The data passed in should be of length 240, the transaction length associated with the EFT file spec.
+// The data passed in should be of length 240, the transaction length associated with the EFT file spec, and is read following the layout returned by LookupRecordLayout.
 func (c *CreditReverse) Parse(data string) error {
-	var err error
-	rs := []rune(data)
-	if len(rs) != segmentLength {
+	if len([]rune(data)) != segmentLength {
 		return NewParseError(ErrInvalidRecordLength, "")
 	}
-	c.TxnType = TransactionType(string(rs[:3]))
-	c.Amount, err = parseNum(string(rs[3:13]))
-	if err != nil {
-		return NewParseError(err, "failed to parse amount")
-	}
-	dateFundsAvail, err := parseDate(string(rs[13:19]))
-	if err != nil {
-		return NewParseError(err, "failed to parse date funds available")
+	if err := decodeFields(CreditReverseRecord, []rune(data), c); err != nil {
+		return err
 	}
-	c.DateFundsAvailable = &dateFundsAvail
-	c.InstitutionID = string(rs[19:28])
-	c.PayeeAccountNo = strings.TrimSpace(string(rs[28:40]))
-	c.ItemTraceNo = string(rs[40:62])
-	c.StoredTransactionType = TransactionType(string(rs[62:65]))
-	c.OriginatorShortName = strings.TrimSpace(string(rs[65:80]))
-	c.PayeeName = strings.TrimSpace(string(rs[80:110]))
-	c.OriginatorLongName = strings.TrimSpace(string(rs[110:140]))
-	c.UserID = strings.TrimSpace(string(rs[140:150]))
-	c.CrossRefNo = strings.TrimSpace(string(rs[150:169]))
-	c.ReturnInstitutionID = strings.TrimSpace(string(rs[169:178]))
-	c.ReturnAccountNo = strings.TrimSpace(string(rs[178:190]))
-	c.SundryInfo = strings.TrimSpace(string(rs[190:205]))
-	c.OriginalItemTraceNo = strings.TrimSpace(string(rs[205:227]))
-	c.SettlementCode = strings.TrimSpace(string(rs[227:229]))
 	c.RecordType = CreditReverseRecord
 	return nil
 }
 
 // Build serializes a CreditReverse into a 240 length string that adheres to the EFT standard 005 standard.
-// Numeric fields are padded with zeros to the left and alphanumeric fields are padded with spaces to the right
-// any missing fields are filled with 0's or blanks
+// Fields are written at the offsets and with the padding of the layout returned by LookupRecordLayout, any missing fields are filled with 0's or blanks
 func (c CreditReverse) Build() (string, error) {
-	var sb strings.Builder
-	sb.Grow(240)
-	sb.WriteString(padNumericStringWithZeros(string(c.TxnType), 3))
-	sb.WriteString(convertNumToZeroPaddedString(c.Amount, 10))
-	if c.DateFundsAvailable != nil {
-		sb.WriteString(padNumericStringWithZeros(convertTimestampToEftDate(*c.DateFundsAvailable), 6))
-	} else {
-		sb.WriteString(padNumericStringWithZeros("", 6))
-	}
-	sb.WriteString(padNumericStringWithZeros(c.InstitutionID, 9))
-	sb.WriteString(abreviateStringToLength(c.PayeeAccountNo, 12))
-	sb.WriteString(padNumericStringWithZeros(c.ItemTraceNo, 22))
-	sb.WriteString(padNumericStringWithZeros(string(c.StoredTransactionType), 3))
-	shortName, err := formatName(c.OriginatorShortName, 15)
-	if err != nil {
-		return "", fmt.Errorf("failed to format originator short name: %w", err)
-	}
-	sb.WriteString(shortName)
-	payeeName, err := formatName(c.PayeeName, 30)
-	if err != nil {
-		return "", fmt.Errorf("failed to format payee name: %w", err)
-	}
-	sb.WriteString(payeeName)
-	longName, err := formatName(c.OriginatorLongName, 30)
-	if err != nil {
-		return "", fmt.Errorf("failed to format originator long name: %w", err)
-	}
-	sb.WriteString(longName)
-	sb.WriteString(abreviateStringToLength(c.UserID, 10))
-	sb.WriteString(abreviateStringToLength(c.CrossRefNo, 19))
-	sb.WriteString(padNumericStringWithZeros(c.ReturnInstitutionID, 9))
-	sb.WriteString(abreviateStringToLength(c.ReturnAccountNo, 12))
-	sb.WriteString(abreviateStringToLength(c.SundryInfo, 15))
-	sb.WriteString(padNumericStringWithZeros(c.OriginalItemTraceNo, 22))
-	sb.WriteString(abreviateStringToLength(c.SettlementCode, 2))
-	sb.WriteString(padNumericStringWithTrailingZeros(c.InvalidDataElementID, 11))
-	return sb.String(), nil
+	return encodeFields(CreditReverseRecord, c)
 }
 
 func (c CreditReverse) Validate() error {
-	if err := eftValidator.Struct(&c); err != nil {
-		return err
-	}
-	return nil
+	return validateStruct(&c, CreditReverseRecord)
 }
 
 func (c CreditReverse) GetType() RecordType {
@@ -0,0 +1,119 @@
+package cadeft
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// DateWindow limits how many days before and after the file creation date a transaction may be dated
+type DateWindow struct {
+	DaysBefore int `json:"days_before" yaml:"days_before"`
+	DaysAfter  int `json:"days_after" yaml:"days_after"`
+}
+
+// DateWindowRules holds the date windows a direct clearer applies to each family of transactions
+type DateWindowRules struct {
+	// Transactions applies to C and D records
+	Transactions DateWindow `json:"transactions" yaml:"transactions"`
+	// Returns applies to I and J records
+	Returns DateWindow `json:"returns" yaml:"returns"`
+	// Reversals applies to E and F records
+	Reversals DateWindow `json:"reversals" yaml:"reversals"`
+}
+
+// DefaultDateWindowRules allows C and D records to be dated up to 30 days around the file creation date,
+// returns up to 90 days in the past and reversals within DefaultReversalWindow in the past.
+var DefaultDateWindowRules = DateWindowRules{
+	Transactions: DateWindow{DaysBefore: 30, DaysAfter: 30},
+	Returns:      DateWindow{DaysBefore: 90, DaysAfter: 0},
+	Reversals:    DateWindow{DaysBefore: int(DefaultReversalWindow / (24 * time.Hour)), DaysAfter: 0},
+}
+
+var (
+	dateWindowMu       sync.RWMutex
+	directClearerRules = map[string]DateWindowRules{}
+)
+
+// RegisterDateWindowRules stores the date window rules of a direct clearer so they can be looked up by name with ValidateDateWindowsFor
+func RegisterDateWindowRules(directClearer string, rules DateWindowRules) {
+	dateWindowMu.Lock()
+	defer dateWindowMu.Unlock()
+	directClearerRules[directClearer] = rules
+}
+
+// LookupDateWindowRules returns the rules registered for a direct clearer
+func LookupDateWindowRules(directClearer string) (DateWindowRules, bool) {
+	dateWindowMu.RLock()
+	defer dateWindowMu.RUnlock()
+	rules, ok := directClearerRules[directClearer]
+	return rules, ok
+}
+
+// ValidateDateWindows checks that the date of every transaction sits within the window of its record type relative to FileHeader.CreationDate.
+// Any error that is encountered will be appended to a multierror and returned to the caller.
+func (f File) ValidateDateWindows(rules DateWindowRules) error {
+	if f.Header == nil || f.Header.CreationDate == nil {
+		return newError(msgHeaderCreationDateMissing)
+	}
+	var err error
+	for i, t := range f.Txns {
+		if dateErr := rules.check(*f.Header.CreationDate, t); dateErr != nil {
+			err = multierror.Append(err, newError(msgTxn, i, dateErr))
+		}
+	}
+	return err
+}
+
+// check returns an error wrapping ErrDateOutsideWindow when the date of t is outside of its window around creationDate
+func (rules DateWindowRules) check(creationDate time.Time, t Transaction) error {
+	date := t.GetDate()
+	if date == nil {
+		return nil
+	}
+	window, name := rules.windowFor(t.GetType())
+	days := int(dateOnly(*date).Sub(dateOnly(creationDate)).Hours() / 24)
+	if days < -window.DaysBefore {
+		return newError(msgDateBeforeWindow, ErrDateOutsideWindow, date.Format(time.DateOnly), -days, name, window.DaysBefore)
+	} else if days > window.DaysAfter {
+		return newError(msgDateAfterWindow, ErrDateOutsideWindow, date.Format(time.DateOnly), days, name, window.DaysAfter)
+	}
+	return nil
+}
+
+// ValidateDateWindowsFor runs ValidateDateWindows with the rules registered for directClearer
+func (f File) ValidateDateWindowsFor(directClearer string) error {
+	rules, ok := LookupDateWindowRules(directClearer)
+	if !ok {
+		return fmt.Errorf("no date window rules registered for direct clearer %q", directClearer)
+	}
+	return f.ValidateDateWindows(rules)
+}
+
+func (rules DateWindowRules) windowFor(recordType RecordType) (DateWindow, string) {
+	switch recordType {
+	case ReturnCreditRecord, ReturnDebitRecord:
+		return rules.Returns, "returns"
+	case CreditReverseRecord, DebitReverseRecord:
+		return rules.Reversals, "reversals"
+	case HeaderRecord, CreditRecord, DebitRecord, NoticeOfChangeRecord, NoticeOfChangeHeader, NoticeOfChangeFooter, FooterRecord:
+	}
+	return rules.Transactions, "transactions"
+}
+
+// txnDateField returns the Go name of the date field of records of type rt, DueDate or DateFundsAvailable
+func txnDateField(rt RecordType) string {
+	for _, f := range recordLayouts[rt].Fields {
+		if f.Format == DateFormat {
+			return f.goName
+		}
+	}
+	return ""
+}
+
+// dateOnly drops the time of day so that dates are compared by calendar day
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
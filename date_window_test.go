@@ -0,0 +1,69 @@
+package cadeft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDateWindows(t *testing.T) {
+	creation := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	header := NewFileHeader("0000000001", 1, &creation, 12345, "CAD")
+	at := func(days int) *time.Time {
+		return Ptr(creation.AddDate(0, 0, days))
+	}
+	credit := func(days int) Transaction {
+		return Ptr(NewCredit("450", 1000, at(days), "123456789", "12345", "1", "short name", "payee name", "someone", "1231", "12345"))
+	}
+	debitReturn := func(days int) Transaction {
+		return Ptr(NewDebitReturn("900", 1000, at(days), "12345", "123", "2", "someone", "payor", "some long name", "54321", "123211", "4"))
+	}
+	creditReverse := func(days int) Transaction {
+		return Ptr(NewCreditReverse("450", 1000, at(days), "12345", "1234567", "2", "sender", "receiver", "sender full name", "54321", "7654321", "3"))
+	}
+
+	type testCase struct {
+		txn       Transaction
+		rules     DateWindowRules
+		expectErr bool
+	}
+	cases := map[string]testCase{
+		"credit on creation date":        {txn: credit(0), rules: DefaultDateWindowRules},
+		"credit 30 days in the future":   {txn: credit(30), rules: DefaultDateWindowRules},
+		"credit 31 days in the future":   {txn: credit(31), rules: DefaultDateWindowRules, expectErr: true},
+		"credit 31 days in the past":     {txn: credit(-31), rules: DefaultDateWindowRules, expectErr: true},
+		"return 90 days in the past":     {txn: debitReturn(-90), rules: DefaultDateWindowRules},
+		"return dated in the future":     {txn: debitReturn(1), rules: DefaultDateWindowRules, expectErr: true},
+		"reversal 6 days in the past":    {txn: creditReverse(-6), rules: DefaultDateWindowRules, expectErr: true},
+		"reversal within custom window":  {txn: creditReverse(-6), rules: DateWindowRules{Reversals: DateWindow{DaysBefore: 10}}},
+		"time of day is ignored":         {txn: Ptr(NewCredit("450", 1000, Ptr(creation.Add(-time.Minute).AddDate(0, 0, 31)), "123456789", "12345", "1", "short name", "payee name", "someone", "1231", "12345")), rules: DefaultDateWindowRules},
+		"custom window for transactions": {txn: credit(-2), rules: DateWindowRules{Transactions: DateWindow{DaysBefore: 1, DaysAfter: 5}}, expectErr: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			err := NewFile(header, Transactions{tc.txn}).ValidateDateWindows(tc.rules)
+			if tc.expectErr {
+				r.ErrorIs(err, ErrDateOutsideWindow)
+			} else {
+				r.NoError(err)
+			}
+		})
+	}
+}
+
+func TestValidateDateWindowsFor(t *testing.T) {
+	r := require.New(t)
+	creation := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	header := NewFileHeader("0000000001", 1, &creation, 12345, "CAD")
+	file := NewFile(header, Transactions{
+		Ptr(NewCredit("450", 1000, Ptr(creation.AddDate(0, 0, 10)), "123456789", "12345", "1", "short name", "payee name", "someone", "1231", "12345")),
+	})
+	r.Error(file.ValidateDateWindowsFor("unknown clearer"))
+
+	RegisterDateWindowRules("strict clearer", DateWindowRules{Transactions: DateWindow{DaysAfter: 7}})
+	r.ErrorIs(file.ValidateDateWindowsFor("strict clearer"), ErrDateOutsideWindow)
+	RegisterDateWindowRules("lenient clearer", DefaultDateWindowRules)
+	r.NoError(file.ValidateDateWindowsFor("lenient clearer"))
+}
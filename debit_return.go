@@ -1,20 +1,16 @@
 package cadeft
 
-import (
-	"fmt"
-	"strings"
-	"time"
-)
+import "time"
 
 // DebitReturn represents Logical Record Type J according to the EFT standard 005
 type DebitReturn struct {
 	BaseTxn
-	DueDate               *time.Time `json:"due_date" validate:"required"`
-	PayorAccountNo        string     `json:"payor_account_no" validate:"required,max=12,numeric"`
-	PayorName             string     `json:"payor_name" validate:"required,max=30"`
-	OriginalInstitutionID string     `json:"original_institution_id" validate:"required,max=9,numeric"`
-	OriginalAccountNo     string     `json:"original_account_no" validate:"required,max=12,eft_alpha"`
-	OriginalItemTraceNo   string     `json:"original_item_trace_no" validate:"required,eft_num,max=22"`
+	DueDate               *time.Time `json:"due_date" validate:"required" spec:"6"`
+	PayorAccountNo        string     `json:"payor_account_no" validate:"required,max=12,numeric" spec:"8"`
+	PayorName             string     `json:"payor_name" validate:"required,max=30" spec:"12"`
+	OriginalInstitutionID string     `json:"original_institution_id" validate:"required,max=9,numeric" spec:"16"`
+	OriginalAccountNo     string     `json:"original_account_no" validate:"required,max=12,eft_alpha" spec:"17"`
+	OriginalItemTraceNo   string     `json:"original_item_trace_no" validate:"required,eft_num,max=22" spec:"19"`
 }
 
 func NewDebitReturn(
@@ -57,81 +53,20 @@ func NewDebitReturn(
 }
 
 // Build serializes a DebitReturn into a 240 length string that adheres to the EFT standard 005 standard.
-// Numeric fields are padded with zeros to the left and alphanumeric fields are padded with spaces to the right
-// any missing fields are filled with 0's or blanks
+// Fields are written at the offsets and with the padding of the layout returned by LookupRecordLayout, any missing fields are filled with 0's or blanks
 func (d DebitReturn) Build() (string, error) {
-	var sb strings.Builder
-	sb.Grow(240)
-	sb.WriteString(padNumericStringWithZeros(string(d.TxnType), 3))
-	sb.WriteString(convertNumToZeroPaddedString(d.Amount, 10))
-	if d.DueDate != nil {
-		sb.WriteString(padNumericStringWithZeros(convertTimestampToEftDate(*d.DueDate), 6))
-	} else {
-		sb.WriteString(padNumericStringWithZeros("", 6))
-	}
-	sb.WriteString(padNumericStringWithZeros(d.InstitutionID, 9))
-	sb.WriteString(abreviateStringToLength(d.PayorAccountNo, 12))
-	sb.WriteString(padNumericStringWithZeros(d.ItemTraceNo, 22))
-	sb.WriteString(padNumericStringWithZeros(string(d.StoredTransactionType), 3))
-	shortName, err := formatName(d.OriginatorShortName, 15)
-	if err != nil {
-		return "", fmt.Errorf("failed to format originator short name: %w", err)
-	}
-	sb.WriteString(shortName)
-	payorName, err := formatName(d.PayorName, 30)
-	if err != nil {
-		return "", fmt.Errorf("failed to format payor name: %w", err)
-	}
-	sb.WriteString(payorName)
-	longName, err := formatName(d.OriginatorLongName, 30)
-	if err != nil {
-		return "", fmt.Errorf("failed to format originator long name: %w", err)
-	}
-	sb.WriteString(longName)
-	sb.WriteString(abreviateStringToLength(d.UserID, 10))
-	sb.WriteString(abreviateStringToLength(d.CrossRefNo, 19))
-	sb.WriteString(padNumericStringWithZeros(d.OriginalInstitutionID, 9))
-	sb.WriteString(abreviateStringToLength(d.OriginalAccountNo, 12))
-	sb.WriteString(abreviateStringToLength(d.SundryInfo, 15))
-	sb.WriteString(padNumericStringWithZeros(d.OriginalItemTraceNo, 22))
-	sb.WriteString(abreviateStringToLength(d.SettlementCode, 2))
-	sb.WriteString(padNumericStringWithTrailingZeros(d.InvalidDataElementID, 11))
-	return sb.String(), nil
+	return encodeFields(ReturnDebitRecord, d)
 }
 
 // Parse takes in a serialized transaction segment and populates a DebitReturn struct containing the relevant data.
-// The data passed in should be of length 240, the transaction length associated with the EFT file spec.
+// The data passed in should be of length 240, the transaction length associated with the EFT file spec, and is read following the layout returned by LookupRecordLayout.
 func (d *DebitReturn) Parse(data string) error {
-	var err error
-	rs := []rune(data)
-	if len(rs) != segmentLength {
+	if len([]rune(data)) != segmentLength {
 		return NewParseError(ErrInvalidRecordLength, "")
 	}
-	d.TxnType = TransactionType(string(rs[:3]))
-	d.Amount, err = parseNum(string(rs[3:13]))
-	if err != nil {
-		return NewParseError(err, "failed to parse amount")
-	}
-	dueDate, err := parseDate(string(rs[13:19]))
-	if err != nil {
-		return NewParseError(err, "failed to parse due date")
+	if err := decodeFields(ReturnDebitRecord, []rune(data), d); err != nil {
+		return err
 	}
-	d.DueDate = &dueDate
-	d.InstitutionID = string(rs[19:28])
-	d.PayorAccountNo = strings.TrimSpace(string(rs[28:40]))
-	d.ItemTraceNo = string(rs[40:62])
-	d.StoredTransactionType = TransactionType(string(rs[62:65]))
-	d.OriginatorShortName = strings.TrimSpace(string(rs[65:80]))
-	d.PayorName = strings.TrimSpace(string(rs[80:110]))
-	d.OriginatorLongName = strings.TrimSpace(string(rs[110:140]))
-	d.UserID = strings.TrimSpace(string(rs[140:150]))
-	d.CrossRefNo = strings.TrimSpace(string(rs[150:169]))
-	d.OriginalInstitutionID = strings.TrimSpace(string(rs[169:178]))
-	d.OriginalAccountNo = strings.TrimSpace(string(rs[178:190]))
-	d.SundryInfo = strings.TrimSpace(string(rs[190:205]))
-	d.OriginalItemTraceNo = strings.TrimSpace(string(rs[205:227]))
-	d.SettlementCode = strings.TrimSpace(string(rs[227:229]))
-	d.InvalidDataElementID = strings.TrimSpace(string(rs[229:240]))
 	d.RecordType = ReturnDebitRecord
 	return nil
 }
@@ -139,10 +74,7 @@ func (d *DebitReturn) Parse(data string) error {
 // Validate checks whether the fields of a DebitReturn struct contain the correct fields that are required when writing/reading an EFT file.
 // The validation check can be found on Section D of EFT standard 005.
 func (d DebitReturn) Validate() error {
-	if err := eftValidator.Struct(&d); err != nil {
-		return err
-	}
-	return nil
+	return validateStruct(&d, ReturnDebitRecord)
 }
 
 func (d DebitReturn) GetType() RecordType {
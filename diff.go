@@ -0,0 +1,245 @@
+package cadeft
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// FieldChange is a field whose value differs between two files, fields are named after their json name
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// TxnDiff is a transaction that only exists in one of the files
+type TxnDiff struct {
+	Index int         `json:"index"`
+	Key   string      `json:"key"`
+	Txn   Transaction `json:"transaction"`
+}
+
+// TxnChange is a transaction found in both files with different field values
+type TxnChange struct {
+	Key     string        `json:"key"`
+	IndexA  int           `json:"index_a"`
+	IndexB  int           `json:"index_b"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// FileDiff lists the differences between two files as returned by Diff
+type FileDiff struct {
+	Header  []FieldChange `json:"header,omitempty"`
+	Footer  []FieldChange `json:"footer,omitempty"`
+	Added   []TxnDiff     `json:"added,omitempty"`
+	Removed []TxnDiff     `json:"removed,omitempty"`
+	Changed []TxnChange   `json:"changed,omitempty"`
+}
+
+// Diff compares file a to file b. Transactions are matched by item trace number and, for transactions without one,
+// by account number, amount and date. Transactions of b without a match are added, those of a are removed.
+// A missing footer is computed from the transactions of its file before comparing.
+func Diff(a, b File) FileDiff {
+	var d FileDiff
+	d.Header = diffRecords(a.Header, b.Header)
+	d.Footer = diffRecords(footerOrComputed(a), footerOrComputed(b))
+
+	matchedB := make(map[int]bool, len(b.Txns))
+	matchedA := make(map[int]bool, len(a.Txns))
+	match := func(i, j int, key string) {
+		matchedA[i], matchedB[j] = true, true
+		if changes := diffRecords(a.Txns[i], b.Txns[j]); len(changes) > 0 {
+			d.Changed = append(d.Changed, TxnChange{Key: key, IndexA: i, IndexB: j, Changes: changes})
+		}
+	}
+
+	// first pair transactions carrying the same item trace number then fall back to account, amount and date for the rest
+	for _, keyFn := range []func(Transaction) string{diffTraceKey, diffFallbackKey} {
+		candidates := make(map[string][]int)
+		for j, t := range b.Txns {
+			if key := keyFn(t); key != "" && !matchedB[j] {
+				candidates[key] = append(candidates[key], j)
+			}
+		}
+		for i, t := range a.Txns {
+			key := keyFn(t)
+			if key == "" || matchedA[i] || len(candidates[key]) == 0 {
+				continue
+			}
+			j := candidates[key][0]
+			candidates[key] = candidates[key][1:]
+			match(i, j, key)
+		}
+	}
+
+	for i, t := range a.Txns {
+		if !matchedA[i] {
+			d.Removed = append(d.Removed, TxnDiff{Index: i, Key: diffKey(t), Txn: t})
+		}
+	}
+	for j, t := range b.Txns {
+		if !matchedB[j] {
+			d.Added = append(d.Added, TxnDiff{Index: j, Key: diffKey(t), Txn: t})
+		}
+	}
+	return d
+}
+
+// Empty reports whether both files were equivalent
+func (d FileDiff) Empty() bool {
+	return len(d.Header) == 0 && len(d.Footer) == 0 && len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// JSON serializes the diff
+func (d FileDiff) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// Text renders the diff for people, one line per difference
+func (d FileDiff) Text() string {
+	if d.Empty() {
+		return "files are identical\n"
+	}
+	var sb strings.Builder
+	writeChanges := func(indent string, changes []FieldChange) {
+		for _, c := range changes {
+			fmt.Fprintf(&sb, "%s~ %s: %s -> %s\n", indent, c.Field, formatDiffValue(c.Before), formatDiffValue(c.After))
+		}
+	}
+	if len(d.Header) > 0 {
+		sb.WriteString("header:\n")
+		writeChanges("  ", d.Header)
+	}
+	if len(d.Footer) > 0 {
+		sb.WriteString("footer:\n")
+		writeChanges("  ", d.Footer)
+	}
+	for _, r := range d.Removed {
+		fmt.Fprintf(&sb, "- txn %d %s %s %s\n", r.Index, r.Txn.GetType(), r.Key, Cents(r.Txn.GetAmount()))
+	}
+	for _, a := range d.Added {
+		fmt.Fprintf(&sb, "+ txn %d %s %s %s\n", a.Index, a.Txn.GetType(), a.Key, Cents(a.Txn.GetAmount()))
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(&sb, "~ txn %d -> %d %s\n", c.IndexA, c.IndexB, c.Key)
+		writeChanges("    ", c.Changes)
+	}
+	return sb.String()
+}
+
+func formatDiffValue(v any) string {
+	if v == nil {
+		return "<none>"
+	}
+	return fmt.Sprintf("%q", fmt.Sprint(v))
+}
+
+func footerOrComputed(f File) *FileFooter {
+	if f.Footer != nil || f.Header == nil {
+		return f.Footer
+	}
+	return NewFileFooter(RecordHeader{
+		RecordType:      FooterRecord,
+		OriginatorID:    f.Header.OriginatorID,
+		FileCreationNum: f.Header.FileCreationNum,
+	}, f.Txns)
+}
+
+func diffTraceKey(t Transaction) string {
+	traceNo := t.GetBaseTxn().ItemTraceNo
+	if isBlankNumeric(traceNo) {
+		return ""
+	}
+	return padNumericStringWithZeros(strings.TrimSpace(traceNo), 22)
+}
+
+// diffFallbackKey matches transactions without an item trace number, traced transactions are only matched by their trace number
+func diffFallbackKey(t Transaction) string {
+	if diffTraceKey(t) != "" {
+		return ""
+	}
+	date := ""
+	if d := t.GetDate(); d != nil {
+		date = d.Format(time.DateOnly)
+	}
+	return fmt.Sprintf("%s/%d/%s", strings.TrimSpace(t.GetAccountNo()), t.GetAmount(), date)
+}
+
+// diffKey describes a transaction by its item trace number or by account, amount and date when it has none
+func diffKey(t Transaction) string {
+	if key := diffTraceKey(t); key != "" {
+		return key
+	}
+	return diffFallbackKey(t)
+}
+
+// diffString drops the padding Build adds so built and parsed records compare equal,
+// numeric fields are padded with zeros, so a field of zeros compares equal to an empty one, and alphanumeric fields with spaces
+func diffString(s, rules string) string {
+	s = strings.TrimSpace(s)
+	for _, rule := range strings.Split(rules, ",") {
+		if rule == "numeric" || rule == "eft_num" {
+			return strings.TrimLeft(s, "0")
+		}
+	}
+	return s
+}
+
+// diffRecords compares the exported fields of two records by json name, a, b or both may be nil
+func diffRecords(a, b any) []FieldChange {
+	fieldsA, fieldsB := diffFields(a), diffFields(b)
+	names := make([]string, 0, len(fieldsA.names)+len(fieldsB.names))
+	names = append(names, fieldsA.names...)
+	for _, n := range fieldsB.names {
+		if _, ok := fieldsA.values[n]; !ok {
+			names = append(names, n)
+		}
+	}
+	var changes []FieldChange
+	for _, n := range names {
+		before, after := fieldsA.values[n], fieldsB.values[n]
+		if !reflect.DeepEqual(before, after) {
+			changes = append(changes, FieldChange{Field: n, Before: before, After: after})
+		}
+	}
+	return changes
+}
+
+type recordFields struct {
+	names  []string
+	values map[string]any
+}
+
+func diffFields(record any) recordFields {
+	fields := recordFields{values: map[string]any{}}
+	rv := reflect.ValueOf(record)
+	if !rv.IsValid() || (rv.Kind() == reflect.Pointer && rv.IsNil()) {
+		return fields
+	}
+	rv = reflect.Indirect(rv)
+	for _, sf := range reflect.VisibleFields(rv.Type()) {
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if !sf.IsExported() || sf.Anonymous || name == "" || name == "-" {
+			continue
+		}
+		fv := rv.FieldByIndex(sf.Index)
+		var value any
+		switch v := fv.Interface().(type) {
+		case *time.Time:
+			if v != nil {
+				value = v.Format(time.DateOnly)
+			}
+		default:
+			value = fv.Interface()
+			if fv.Kind() == reflect.String {
+				value = diffString(fv.String(), sf.Tag.Get("validate"))
+			}
+		}
+		fields.names = append(fields.names, name)
+		fields.values[name] = value
+	}
+	return fields
+}
@@ -0,0 +1,109 @@
+package cadeft
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	date := time.Date(2023, time.October, 2, 0, 0, 0, 0, time.UTC)
+	credit := func(amount int64, account, traceNo string, opts ...BaseTxnOpt) Transaction {
+		return Ptr(NewCredit("450", amount, &date, "123456789", account, traceNo, "SHORT", "PAYEE", "LONG", "123456789", "210987654321", opts...))
+	}
+	newFile := func(fileCreationNum int64, txns ...Transaction) File {
+		return NewFile(NewFileHeader("0000000001", fileCreationNum, &date, 12345, "CAD"), txns)
+	}
+
+	t.Run("identical files", func(t *testing.T) {
+		r := require.New(t)
+		a := newFile(1, credit(100, "111", "1"), credit(200, "222", "000"))
+		d := Diff(a, newFile(1, credit(100, "111", "1"), credit(200, "222", "000")))
+		r.True(d.Empty())
+		r.Equal("files are identical\n", d.Text())
+	})
+
+	t.Run("reports added removed and changed items", func(t *testing.T) {
+		r := require.New(t)
+		a := newFile(1,
+			credit(100, "111", "0000000001"),
+			credit(200, "222", ""),
+			credit(300, "333", ""),
+		)
+		b := newFile(2,
+			credit(250, "222", ""),
+			credit(150, "111", "1", WithCrossRefNo("INV-1")),
+			credit(300, "333", ""),
+		)
+		d := Diff(a, b)
+		r.False(d.Empty())
+		r.Equal([]FieldChange{{Field: "file_creation_number", Before: int64(1), After: int64(2)}}, d.Header)
+		r.Contains(d.Footer, FieldChange{Field: "total_value_credit", Before: int64(600), After: int64(700)})
+
+		r.Len(d.Changed, 1)
+		r.Equal(TxnChange{
+			Key:    "0000000000000000000001",
+			IndexA: 0,
+			IndexB: 1,
+			Changes: []FieldChange{
+				{Field: "amount", Before: int64(100), After: int64(150)},
+				{Field: "cross_ref_no", Before: "", After: "INV-1"},
+			},
+		}, d.Changed[0])
+		r.Len(d.Removed, 1)
+		r.Equal(1, d.Removed[0].Index)
+		r.Equal("222/200/2023-10-02", d.Removed[0].Key)
+		r.Len(d.Added, 1)
+		r.Equal(0, d.Added[0].Index)
+
+		expected := `header:
+  ~ file_creation_number: "1" -> "2"
+footer:
+  ~ file_creation_number: "1" -> "2"
+  ~ total_value_credit: "600" -> "700"
+- txn 1 C 222/200/2023-10-02 2.00
++ txn 0 C 222/250/2023-10-02 2.50
+~ txn 0 -> 1 0000000000000000000001
+    ~ amount: "100" -> "150"
+    ~ cross_ref_no: "" -> "INV-1"
+`
+		r.Equal(expected, d.Text())
+
+		out, err := d.JSON()
+		r.NoError(err)
+		var decoded map[string]any
+		r.NoError(json.Unmarshal(out, &decoded))
+		r.Len(decoded["added"], 1)
+		added := decoded["added"].([]any)[0].(map[string]any)
+		r.Equal(float64(250), added["transaction"].(map[string]any)["amount"])
+	})
+
+	t.Run("traced items are not matched by account amount and date", func(t *testing.T) {
+		r := require.New(t)
+		d := Diff(newFile(1, credit(100, "111", "0000000001")), newFile(1, credit(100, "111", "0000000002")))
+		r.Empty(d.Changed)
+		r.Len(d.Removed, 1)
+		r.Equal("0000000000000000000001", d.Removed[0].Key)
+		r.Len(d.Added, 1)
+		r.Equal("0000000000000000000002", d.Added[0].Key)
+	})
+
+	t.Run("parsed file against built file", func(t *testing.T) {
+		r := require.New(t)
+		built := newFile(1, credit(100, "111", "1"))
+		s, err := built.Create()
+		r.NoError(err)
+		parsed, err := NewReader(strings.NewReader(s)).ReadFile()
+		r.NoError(err)
+		r.True(Diff(built, parsed).Empty(), Diff(built, parsed).Text())
+	})
+
+	t.Run("missing header", func(t *testing.T) {
+		r := require.New(t)
+		d := Diff(File{}, newFile(1))
+		r.Contains(d.Header, FieldChange{Field: "currency_code", Before: nil, After: "CAD"})
+	})
+}
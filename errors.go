@@ -1,13 +1,47 @@
 package cadeft
 
 import (
-	"errors"
+	"encoding/json"
 	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
 )
 
+// MessageID identifies a message of the catalog errors are rendered from, see Translate.
+// Every error created by cadeft carries the MessageID of its message rather than being matched by its text.
+type MessageID string
+
+// messageError is an error whose text is the message id filled with args, errors among args are wrapped
+type messageError struct {
+	id   MessageID
+	args []any
+}
+
+func newError(id MessageID, args ...any) error {
+	return &messageError{id: id, args: args}
+}
+
+func (e *messageError) Error() string {
+	return catalogs[English].format(e.id, e.args)
+}
+
+func (e *messageError) Unwrap() []error {
+	var errs []error
+	for _, arg := range e.args {
+		if err, ok := arg.(error); ok {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
 type ParseError struct {
 	Err error
 	Msg string
+	// ID identifies Msg in the message catalog, it is empty for messages given to NewParseError which are not translated
+	ID   MessageID
+	args []any
 }
 
 func (p *ParseError) Error() string {
@@ -18,6 +52,11 @@ func (p *ParseError) Error() string {
 	}
 }
 
+func (p *ParseError) Unwrap() error {
+	return p.Err
+}
+
+// Deprecated: use Unwrap
 func (p *ParseError) UnWrap() error {
 	return p.Err
 }
@@ -29,37 +68,143 @@ func NewParseError(err error, msg string) error {
 	}
 }
 
+// newParseError returns a ParseError whose message is the message id filled with args
+func newParseError(err error, id MessageID, args ...any) error {
+	return &ParseError{
+		Err:  err,
+		Msg:  catalogs[English].format(id, args),
+		ID:   id,
+		args: args,
+	}
+}
+
+// ValidationError describes a single field that failed validation.
+// Err is one of the Err* sentinels of this package, or the underlying error, so it can be matched with errors.Is.
 type ValidationError struct {
-	Err error
+	// TxnIndex is the position of the transaction in File.Txns, nil for the file header or a transaction validated on its own
+	TxnIndex    *int       `json:"txn_index,omitempty"`
+	RecordType  RecordType `json:"record_type,omitempty"`
+	Field       string     `json:"field,omitempty"`
+	JSONField   string     `json:"json_field,omitempty"`
+	FieldNumber int        `json:"field_number,omitempty"`
+	Rule        string     `json:"rule,omitempty"`
+	Param       string     `json:"param,omitempty"`
+	Value       any        `json:"value,omitempty"`
+	Err         error      `json:"-"`
+
+	fieldErr validator.FieldError
 }
 
 func (v *ValidationError) Error() string {
-	return v.Err.Error()
+	return catalogs[English].validationError(v, error.Error, false)
+}
 
+func (v *ValidationError) Unwrap() error {
+	return v.Err
 }
 
+// Deprecated: use Unwrap
 func (v *ValidationError) UnWrap() error {
 	return v.Err
 }
 
+// MarshalJSON adds the error message to the serialized ValidationError
+func (v *ValidationError) MarshalJSON() ([]byte, error) {
+	type alias ValidationError
+	return json.Marshal(struct {
+		*alias
+		Message string `json:"message"`
+	}{
+		alias:   (*alias)(v),
+		Message: v.Error(),
+	})
+}
+
 func NewValidationError(err error) error {
 	return &ValidationError{
 		Err: err,
 	}
 }
 
+// ValidationErrors is returned by the Validate functions of this package and holds every field that failed validation
+type ValidationErrors []*ValidationError
+
+func (ve ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(ve))
+	for _, v := range ve {
+		msgs = append(msgs, v.Error())
+	}
+	if len(msgs) == 1 {
+		return msgs[0]
+	}
+	return fmt.Sprintf(catalogs[English].validationErrors, len(msgs)) + catalogs[English].separator + strings.Join(msgs, "; ")
+}
+
+// Unwrap allows errors.Is and errors.As to match any of the individual ValidationError
+func (ve ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(ve))
+	for _, v := range ve {
+		errs = append(errs, v)
+	}
+	return errs
+}
+
 var (
 	// parse errors
-	ErrInvalidRecordLength = errors.New("transaction record is not 240 characters")
+	ErrInvalidRecordLength = newError(msgInvalidRecordLength)
 	// Validation errors
-	ErrMissingOriginatorId                   = errors.New("missing originator ID")
-	ErrMissingCreationDate                   = errors.New("missing creation date")
-	ErrInvalidRecordType                     = errors.New("invalid record type")
-	ErrInvalidCurrencyCode                   = errors.New("invalid currency code")
-	ErrInvalidOriginatorIdLength             = errors.New("invalid originator ID length")
-	ErrInvalidOriginatorId                   = errors.New("invalid originator ID not alpha numeric")
-	ErrInvalidFileCreationNum                = errors.New("invalid file creation number")
-	ErrInvalidDestinationDataCenterNo        = errors.New("invalid destination data center")
-	ErrInvalidDirectClearerCommunicationArea = errors.New("invalid direct clearer communication area")
-	ErrScanParseError                        = errors.New("failed to parse txn")
+	ErrMissingHeader                         = newError(msgMissingHeader)
+	ErrMissingOriginatorId                   = newError(msgMissingOriginatorId)
+	ErrMissingCreationDate                   = newError(msgMissingCreationDate)
+	ErrInvalidRecordType                     = newError(msgInvalidRecordType)
+	ErrInvalidCurrencyCode                   = newError(msgInvalidCurrencyCode)
+	ErrInvalidOriginatorIdLength             = newError(msgInvalidOriginatorIdLength)
+	ErrInvalidOriginatorId                   = newError(msgInvalidOriginatorId)
+	ErrInvalidFileCreationNum                = newError(msgInvalidFileCreationNum)
+	ErrFileCreationNumReused                 = newError(msgFileCreationNumReused)
+	ErrNonBusinessDay                        = newError(msgNonBusinessDay)
+	ErrDateOutsideWindow                     = newError(msgDateOutsideWindow)
+	ErrInvalidDestinationDataCenterNo        = newError(msgInvalidDestinationDataCenterNo)
+	ErrInvalidDirectClearerCommunicationArea = newError(msgInvalidDirectClearerCommunicationArea)
+	ErrInvalidField                          = newError(msgInvalidField)
+	ErrInvalidTxnType                        = newError(msgInvalidTxnType)
+	ErrInvalidAmount                         = newError(msgInvalidAmount)
+	ErrInvalidDate                           = newError(msgInvalidDate)
+	ErrInvalidInstitutionID                  = newError(msgInvalidInstitutionID)
+	ErrInvalidAccountNo                      = newError(msgInvalidAccountNo)
+	ErrInvalidItemTraceNo                    = newError(msgInvalidItemTraceNo)
+	ErrInvalidStoredTxnType                  = newError(msgInvalidStoredTxnType)
+	ErrInvalidOriginatorShortName            = newError(msgInvalidOriginatorShortName)
+	ErrInvalidName                           = newError(msgInvalidName)
+	ErrInvalidOriginatorLongName             = newError(msgInvalidOriginatorLongName)
+	ErrInvalidUserID                         = newError(msgInvalidUserID)
+	ErrInvalidCrossRefNo                     = newError(msgInvalidCrossRefNo)
+	ErrInvalidReturnInstitutionID            = newError(msgInvalidReturnInstitutionID)
+	ErrInvalidReturnAccountNo                = newError(msgInvalidReturnAccountNo)
+	ErrInvalidOriginalInstitutionID          = newError(msgInvalidOriginalInstitutionID)
+	ErrInvalidOriginalAccountNo              = newError(msgInvalidOriginalAccountNo)
+	ErrInvalidOriginalItemTraceNo            = newError(msgInvalidOriginalItemTraceNo)
+	ErrInvalidSundryInfo                     = newError(msgInvalidSundryInfo)
+	ErrInvalidSettlementCode                 = newError(msgInvalidSettlementCode)
+	ErrInvalidInvalidDataElementID           = newError(msgInvalidInvalidDataElementID)
+	ErrTooManyItems                          = newError(msgTooManyItems)
+	ErrFieldOverflow                         = newError(msgFieldOverflow)
+	ErrInvalidMoney                          = newError(msgInvalidMoney)
+	ErrScanParseError                        = newError(msgScanParseError)
+	// reversal errors
+	ErrReversalWindowExceeded = newError(msgReversalWindowExceeded)
+	ErrNotReversible          = newError(msgNotReversible)
+	ErrItemTraceNoExhausted   = newError(msgItemTraceNoExhausted)
+	// split and merge errors
+	ErrSplitLimitExceeded   = newError(msgSplitLimitExceeded)
+	ErrIncompatibleHeaders  = newError(msgIncompatibleHeaders)
+	ErrDuplicateItemTraceNo = newError(msgDuplicateItemTraceNo)
+	// profile errors
+	ErrInvalidValidationProfile = newError(msgInvalidValidationProfile)
+	ErrUnknownValidationProfile = newError(msgUnknownValidationProfile)
+	// explain errors
+	ErrFillerNotBlank        = newError(msgFillerNotBlank)
+	ErrFieldMissing          = newError(msgFieldMissing)
+	ErrLineNotFound          = newError(msgLineNotFound)
+	ErrUnsupportedRecordType = newError(msgUnsupportedRecordType)
 )
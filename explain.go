@@ -0,0 +1,297 @@
+package cadeft
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// FieldExplanation is a field of a record as found in a line along with what is wrong with it
+type FieldExplanation struct {
+	FieldLayout
+	// Column is the position in the line of the first character of the field, starting at 1
+	Column int `json:"column"`
+	// Raw is the text of the field as found in the line, shorter than the field when the line is cut
+	Raw string `json:"raw"`
+	// Value is the field as parsed, dates are written as YYYY-MM-DD
+	Value any `json:"value,omitempty"`
+	// Errors lists why the field could not be parsed, failed validation or is filler holding data
+	Errors []error `json:"-"`
+}
+
+// MarshalJSON adds the error messages to the serialized FieldExplanation
+func (fe FieldExplanation) MarshalJSON() ([]byte, error) {
+	type alias FieldExplanation
+	return json.Marshal(struct {
+		alias
+		Errors []string `json:"errors,omitempty"`
+	}{
+		alias:  alias(fe),
+		Errors: errorMessages(fe.Errors),
+	})
+}
+
+// RecordExplanation lists every field of a header, footer or single transaction of a line
+type RecordExplanation struct {
+	Line int `json:"line"`
+	// Segment is the index of the transaction within a transaction line, nil for headers and footers
+	Segment    *int               `json:"segment,omitempty"`
+	RecordType RecordType         `json:"record_type"`
+	Fields     []FieldExplanation `json:"fields"`
+	// Errors are validation failures of the record that do not belong to a single field
+	Errors []error `json:"-"`
+}
+
+// MarshalJSON adds the error messages to the serialized RecordExplanation
+func (re RecordExplanation) MarshalJSON() ([]byte, error) {
+	type alias RecordExplanation
+	return json.Marshal(struct {
+		alias
+		Errors []string `json:"errors,omitempty"`
+	}{
+		alias:  alias(re),
+		Errors: errorMessages(re.Errors),
+	})
+}
+
+// Valid reports whether every field of the record parsed and passed validation
+func (re RecordExplanation) Valid() bool {
+	if len(re.Errors) > 0 {
+		return false
+	}
+	for _, f := range re.Fields {
+		if len(f.Errors) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Text renders the explanation as a table with one row per field, rows of fields with errors start with !
+func (re RecordExplanation) Text() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "line %d", re.Line)
+	if re.Segment != nil {
+		fmt.Fprintf(&sb, " segment %d", *re.Segment)
+	}
+	fmt.Fprintf(&sb, " record %s\n", re.RecordType)
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tCOL\tLEN\tNO\tFIELD\tRAW\tVALUE\tERRORS")
+	for _, f := range re.Fields {
+		mark, name := "", f.Name
+		if len(f.Errors) > 0 {
+			mark = "!"
+		}
+		if name == "" {
+			name = f.Description
+		}
+		value := ""
+		if f.Value != nil {
+			value = fmt.Sprint(f.Value)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%02d\t%s\t%q\t%s\t%s\n", mark, f.Column, f.Length, f.Number, name, f.Raw, value, strings.Join(errorMessages(f.Errors), "; "))
+	}
+	w.Flush()
+	for _, err := range re.Errors {
+		fmt.Fprintf(&sb, "! %s\n", err)
+	}
+	return sb.String()
+}
+
+// ExplainOpt narrows down what Explain reports
+type ExplainOpt func(*explainConfig)
+
+type explainConfig struct {
+	segment *int
+}
+
+// WithSegment only explains the transaction at index segment of a transaction line, counting from 0.
+// Without it every transaction of the line is explained and blank segments are skipped.
+func WithSegment(segment int) ExplainOpt {
+	return func(c *explainConfig) {
+		c.segment = &segment
+	}
+}
+
+// Explain reads in up to line lineNo, counting from 1, and explains every field of the records on it.
+// The rest of the file is not parsed so that broken files can be inspected line by line.
+func Explain(in io.Reader, lineNo int, opts ...ExplainOpt) ([]RecordExplanation, error) {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if n == lineNo {
+			return ExplainLine(scanner.Text(), lineNo, opts...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, newError(msgReadLine, err)
+	}
+	return nil, newError(msgLine, lineNo, ErrLineNotFound)
+}
+
+// ExplainLine explains the records of a single line, lineNo only labels the explanations
+func ExplainLine(line string, lineNo int, opts ...ExplainOpt) ([]RecordExplanation, error) {
+	var cfg explainConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	line, err := normalize(line)
+	if err != nil {
+		return nil, newError(msgReadLine, err)
+	}
+	rs := []rune(line)
+	if len(rs) == 0 {
+		return nil, newError(msgLine, lineNo, newError(msgLineBlank, ErrUnsupportedRecordType))
+	}
+
+	recordType := RecordType(rs[:1])
+	switch recordType {
+	case HeaderRecord, FooterRecord:
+		if cfg.segment != nil {
+			return nil, newError(msgLine, lineNo, newError(msgNoSegments, recordType))
+		}
+		return []RecordExplanation{explainRecord(recordType, rs, 0, lineNo)}, nil
+	case CreditRecord, DebitRecord, CreditReverseRecord, DebitReverseRecord, ReturnCreditRecord, ReturnDebitRecord:
+		segments := max(1, (len(rs)-commonRecordDataLength+segmentLength-1)/segmentLength)
+		if cfg.segment != nil {
+			if *cfg.segment < 0 || *cfg.segment >= segments {
+				return nil, newError(msgLine, lineNo, newError(msgSegmentNotFound, *cfg.segment, segments))
+			}
+			explanation := explainRecord(recordType, rs, *cfg.segment, lineNo)
+			return []RecordExplanation{explanation}, nil
+		}
+		var explanations []RecordExplanation
+		for i := 0; i < segments; i++ {
+			start := commonRecordDataLength + i*segmentLength
+			if start < len(rs) && isFillerString(string(rs[start:min(len(rs), start+segmentLength)])) {
+				continue
+			}
+			explanations = append(explanations, explainRecord(recordType, rs, i, lineNo))
+		}
+		return explanations, nil
+	case NoticeOfChangeRecord, NoticeOfChangeHeader, NoticeOfChangeFooter:
+	}
+	return nil, newError(msgLine, lineNo, newError(msgUnsupportedRecType, ErrUnsupportedRecordType, recordType))
+}
+
+// explainRecord explains the header or footer of a line, or the transaction in segment of a transaction line preceded by the record header of the line
+func explainRecord(rt RecordType, rs []rune, segment, lineNo int) RecordExplanation {
+	explanation := RecordExplanation{Line: lineNo, RecordType: rt}
+	record := reflect.New(reflect.TypeOf(layoutRecords[rt]))
+	offset := 0
+	if rt != HeaderRecord && rt != FooterRecord {
+		explanation.Segment = Ptr(segment)
+		offset = commonRecordDataLength + segment*segmentLength
+		for _, f := range txnRecordHeaderFields {
+			explanation.Fields = append(explanation.Fields, explainField(rt, f, rs, 0))
+		}
+		record.Elem().FieldByName("RecordType").Set(reflect.ValueOf(rt))
+	}
+
+	for _, f := range recordLayouts[rt].Fields {
+		fe := explainField(rt, f, rs, offset)
+		if value, ok := fe.Value.(*time.Time); ok {
+			record.Elem().FieldByName(f.goName).Set(reflect.ValueOf(value))
+			fe.Value = nil
+			if value != nil {
+				fe.Value = value.Format(time.DateOnly)
+			}
+		} else if fe.Value != nil && f.goName != "" {
+			fv := record.Elem().FieldByName(f.goName)
+			fv.Set(reflect.ValueOf(fe.Value).Convert(fv.Type()))
+		}
+		explanation.Fields = append(explanation.Fields, fe)
+	}
+
+	for _, err := range explainValidation(record.Elem().Interface()) {
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) && attachFieldError(explanation.Fields, validationErr) {
+			continue
+		}
+		explanation.Errors = append(explanation.Errors, err)
+	}
+	return explanation
+}
+
+// explainField reads the field f of a record starting at offset in the line
+func explainField(rt RecordType, f FieldLayout, rs []rune, offset int) FieldExplanation {
+	start, end := offset+f.Start, offset+f.End()
+	fe := FieldExplanation{FieldLayout: f, Column: start + 1}
+	if start < len(rs) {
+		fe.Raw = string(rs[start:min(end, len(rs))])
+	}
+	switch {
+	case f.Format == FillerFormat:
+		if !isFillerString(fe.Raw) {
+			fe.Errors = append(fe.Errors, ErrFillerNotBlank)
+		}
+	case end > len(rs):
+		fe.Errors = append(fe.Errors, ErrFieldMissing)
+	default:
+		value, err := decodeValue(f, fieldKind(rt, f), fe.Raw)
+		if err != nil {
+			fe.Errors = append(fe.Errors, err)
+			break
+		}
+		fe.Value = value
+	}
+	return fe
+}
+
+// explainValidation runs the spec and semantic validation of a record and returns the individual failures
+func explainValidation(record any) []error {
+	var errs []error
+	if v, ok := record.(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if s, ok := record.(semanticValidator); ok {
+		if err := s.ValidateSemantics(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	var flattened []error
+	for _, err := range errs {
+		var validationErrs ValidationErrors
+		if errors.As(err, &validationErrs) {
+			for _, v := range validationErrs {
+				flattened = append(flattened, v)
+			}
+			continue
+		}
+		flattened = append(flattened, err)
+	}
+	return flattened
+}
+
+// attachFieldError adds a validation failure to the field it is about, failures of fields that could not be parsed are dropped
+func attachFieldError(fields []FieldExplanation, err *ValidationError) bool {
+	for i := range fields {
+		if fields[i].Name != err.JSONField || err.JSONField == "" {
+			continue
+		}
+		if len(fields[i].Errors) == 0 {
+			fields[i].Errors = append(fields[i].Errors, err)
+		}
+		return true
+	}
+	return false
+}
+
+func errorMessages(errs []error) []string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return messages
+}
@@ -0,0 +1,144 @@
+package cadeft
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	sample, err := os.ReadFile("sample_files/CO14821.txt")
+	require.NoError(t, err)
+	lines := strings.Split(string(sample), "\r\n")
+
+	fieldByName := func(t *testing.T, e RecordExplanation, name string) FieldExplanation {
+		t.Helper()
+		for _, f := range e.Fields {
+			if f.Name == name || (name == "filler" && f.Format == FillerFormat) {
+				return f
+			}
+		}
+		require.FailNow(t, "field not found", name)
+		return FieldExplanation{}
+	}
+
+	t.Run("header", func(t *testing.T) {
+		r := require.New(t)
+		explanations, err := Explain(strings.NewReader(string(sample)), 1)
+		r.NoError(err)
+		r.Len(explanations, 1)
+		header := explanations[0]
+		r.True(header.Valid())
+		r.Nil(header.Segment)
+		r.Equal(HeaderRecord, header.RecordType)
+		date := fieldByName(t, header, "creation_date")
+		r.Equal(25, date.Column)
+		r.Equal(5, date.Number)
+		r.Equal("023138", date.Raw)
+		r.Equal("2023-05-18", date.Value)
+		r.Equal(int64(1), fieldByName(t, header, "file_creation_number").Value)
+	})
+
+	t.Run("nil date", func(t *testing.T) {
+		r := require.New(t)
+		line := lines[0][:24] + "000000" + lines[0][30:]
+		explanations, err := ExplainLine(line, 1)
+		r.NoError(err)
+		date := fieldByName(t, explanations[0], "creation_date")
+		r.Nil(date.Value)
+		// a header without creation date reads fine and fails validation
+		r.Len(date.Errors, 1)
+		r.ErrorIs(date.Errors[0], ErrMissingCreationDate)
+	})
+
+	t.Run("every transaction of a line", func(t *testing.T) {
+		r := require.New(t)
+		explanations, err := Explain(strings.NewReader(string(sample)), 3)
+		r.NoError(err)
+		// the debit line holds 5 transactions, the sixth segment is not there
+		r.Len(explanations, 5)
+		for i, e := range explanations {
+			r.Equal(DebitRecord, e.RecordType)
+			r.Equal(i, *e.Segment)
+			r.True(e.Valid(), e.Text())
+		}
+		// the record header of a transaction line is numbered 01 to 03 and the fields of the segment follow from 04
+		for i, f := range explanations[1].Fields {
+			r.Equal(i+1, f.Number, f.Name)
+		}
+		r.Equal("D", fieldByName(t, explanations[1], "type").Value)
+		r.Equal(2, fieldByName(t, explanations[1], "record_count").Number)
+		control := fieldByName(t, explanations[1], "origination_control_data")
+		r.Equal(3, control.Number)
+		r.Equal(11, control.Column)
+		r.Equal("00000006100001", control.Value)
+		r.Equal(4, fieldByName(t, explanations[1], "txn_type").Number)
+		amount := fieldByName(t, explanations[1], "amount")
+		r.Equal(commonRecordDataLength+segmentLength+4, amount.Column)
+	})
+
+	t.Run("problems are reported on their field", func(t *testing.T) {
+		r := require.New(t)
+		line := []rune(lines[1])
+		segment := commonRecordDataLength + segmentLength
+		copy(line[segment+3:], []rune("00000X0404"))
+		copy(line[segment+80:], []rune(strings.Repeat(" ", 30)))
+		copy(line[segment+205:], []rune("AUDIT NOTE"))
+
+		explanations, err := ExplainLine(string(line), 2, WithSegment(1))
+		r.NoError(err)
+		r.Len(explanations, 1)
+		e := explanations[0]
+		r.False(e.Valid())
+		r.Equal(1, *e.Segment)
+
+		var parseErr *ParseError
+		amount := fieldByName(t, e, "amount")
+		r.Len(amount.Errors, 1)
+		r.ErrorAs(amount.Errors[0], &parseErr)
+		r.Nil(amount.Value)
+
+		filler := fieldByName(t, e, "filler")
+		r.Equal(19, filler.Number)
+		r.Equal(segment+206, filler.Column)
+		r.Equal([]error{ErrFillerNotBlank}, filler.Errors)
+
+		payeeName := fieldByName(t, e, "payee_name")
+		r.Len(payeeName.Errors, 1)
+		var validationErr *ValidationError
+		r.True(errors.As(payeeName.Errors[0], &validationErr))
+		r.Equal("required", validationErr.Rule)
+
+		r.Empty(fieldByName(t, e, "payee_account_no").Errors)
+		r.Contains(e.Text(), "!  ")
+
+		out, err := json.Marshal(e)
+		r.NoError(err)
+		r.Contains(string(out), `"errors":["filler is not blank"]`)
+	})
+
+	t.Run("cut line", func(t *testing.T) {
+		r := require.New(t)
+		explanations, err := ExplainLine(lines[1][:commonRecordDataLength+100], 2)
+		r.NoError(err)
+		r.Len(explanations, 1)
+		r.Equal([]error{ErrFieldMissing}, fieldByName(t, explanations[0], "long_name").Errors)
+		r.Equal("D1-1-OCC ZERO", fieldByName(t, explanations[0], "payee_name").Raw[:13])
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		r := require.New(t)
+		_, err := Explain(strings.NewReader(string(sample)), 5)
+		r.ErrorIs(err, ErrLineNotFound)
+		_, err = ExplainLine("X123", 1)
+		r.ErrorIs(err, ErrUnsupportedRecordType)
+		_, err = ExplainLine(lines[0], 1, WithSegment(0))
+		r.Error(err)
+		_, err = ExplainLine(lines[1], 2, WithSegment(6))
+		r.EqualError(err, "line 2: segment 6 not found, the line has 6 segments")
+	})
+}
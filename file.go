@@ -4,10 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"time"
-
-	"github.com/hashicorp/go-multierror"
 )
 
 const (
@@ -59,6 +58,10 @@ type File struct {
 	Header *FileHeader  `json:"file_header,omitempty"`
 	Txns   Transactions `json:"transactions,omitempty"`
 	Footer *FileFooter  `json:"file_footer,omitempty"`
+	// ItemTraceNoGenerator is optional, when set Create assigns a trace number to every transaction missing one
+	ItemTraceNoGenerator *ItemTraceNoGenerator `json:"-"`
+
+	layout *fileLayout
 }
 
 func NewFile(header *FileHeader, txns []Transaction) File {
@@ -76,7 +79,15 @@ func (f *File) Create() (string, error) {
 	var sb strings.Builder
 	currentLine := 1
 	if f.Header == nil {
-		return "", errors.New("file header is missing")
+		return "", newError(msgFileHeaderMissing)
+	}
+	if f.ItemTraceNoGenerator != nil {
+		if err := f.ItemTraceNoGenerator.Fill(f.Txns); err != nil {
+			return "", fmt.Errorf("failed to assign item trace numbers: %w", err)
+		}
+	}
+	if f.layout.matches(*f) {
+		return f.createPreserved()
 	}
 	serializedHeader, err := f.Header.buildHeader(currentLine)
 	if err != nil {
@@ -253,21 +264,105 @@ func (f File) buildTxnEntries(txns []Transaction, recordHeader RecordHeader, cur
 	return sb.String(), nil
 }
 
+// ValidateOpt adds checks to File.Validate
+type ValidateOpt func(*validateConfig)
+
+type validateConfig struct {
+	profiles []string
+	rules    []Rule
+}
+
+// WithProfile applies the requirements of the ValidationProfile registered under name
+func WithProfile(name string) ValidateOpt {
+	return func(c *validateConfig) {
+		c.profiles = append(c.profiles, name)
+	}
+}
+
+// WithRules applies custom rules as File.ValidateWith does
+func WithRules(rules ...Rule) ValidateOpt {
+	return func(c *validateConfig) {
+		c.rules = append(c.rules, rules...)
+	}
+}
+
 // Validate runs validation on the entire file starting from the FileHeader then every Taransaction.
-// Any error that is encountered will be appended to a multierror and returned to the caller.
-func (f File) Validate() error {
-	var err error
-	headerErr := f.Header.Validate()
-	if headerErr != nil {
-		err = multierror.Append(err, headerErr)
+// Every field that fails validation is returned as a ValidationError within ValidationErrors, transaction errors carry the index of the transaction.
+func (f File) Validate(opts ...ValidateOpt) error {
+	var cfg validateConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	rules := cfg.rules
+	for _, name := range cfg.profiles {
+		p, ok := LookupValidationProfile(name)
+		if !ok {
+			return newError(msgErrQuoted, ErrUnknownValidationProfile, name)
+		}
+		rules = append(rules, p)
+	}
+	return f.ValidateWith(rules...)
+}
+
+// ValidateWith runs the built-in validation of Validate along with custom rules. Header rules run on the FileHeader, transaction rules
+// on every Transaction and file rules once on the whole file. All failures are combined in a single ValidationErrors.
+func (f File) ValidateWith(rules ...Rule) error {
+	var errs ValidationErrors
+	if f.Header == nil {
+		errs = append(errs, &ValidationError{RecordType: HeaderRecord, Field: "Header", JSONField: "file_header", Rule: "required", Err: ErrMissingHeader})
+	} else {
+		if headerErr := f.Header.Validate(); headerErr != nil {
+			errs = appendValidationErrors(errs, headerErr, nil, HeaderRecord)
+		}
+		for _, r := range rules {
+			if ruleErr := r.ValidateHeader(*f.Header); ruleErr != nil {
+				errs = appendValidationErrors(errs, ruleErr, nil, HeaderRecord)
+			}
+		}
 	}
 	for i, t := range f.Txns {
-		txnErr := t.Validate()
-		if txnErr != nil {
-			err = multierror.Append(err, fmt.Errorf("faild to validate txn %d: %w", i, txnErr))
+		if txnErr := t.Validate(); txnErr != nil {
+			errs = appendValidationErrors(errs, txnErr, Ptr(i), t.GetType())
 		}
+		for _, r := range rules {
+			if ruleErr := r.ValidateTxn(t); ruleErr != nil {
+				errs = appendValidationErrors(errs, ruleErr, Ptr(i), t.GetType())
+			}
+		}
+	}
+	for _, r := range rules {
+		if ruleErr := r.ValidateFile(f); ruleErr != nil {
+			errs = appendValidationErrors(errs, ruleErr, nil, "")
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// appendValidationErrors appends err to errs tagging each ValidationError with the index of the transaction it belongs to
+func appendValidationErrors(errs ValidationErrors, err error, txnIndex *int, recordType RecordType) ValidationErrors {
+	var validationErrs ValidationErrors
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErrs):
+	case errors.As(err, &validationErr):
+		validationErrs = ValidationErrors{validationErr}
+	default:
+		return append(errs, &ValidationError{TxnIndex: txnIndex, RecordType: recordType, Err: err})
+	}
+	for _, v := range validationErrs {
+		tagged := *v
+		if txnIndex != nil {
+			tagged.TxnIndex = txnIndex
+		}
+		if tagged.RecordType == "" {
+			tagged.RecordType = recordType
+		}
+		errs = append(errs, &tagged)
 	}
-	return err
+	return errs
 }
 
 func NewTransaction(
@@ -316,15 +411,15 @@ func (f *Transactions) UnmarshalJSON(data []byte) error {
 
 		tMap, ok := t.(map[string]interface{})
 		if !ok {
-			return errors.New("failed to unmarshall transaction")
+			return newError(msgUnmarshalTxn)
 		}
 		rawRecordType, ok := tMap["type"]
 		if !ok {
-			return errors.New("failed to unmarshall transaction")
+			return newError(msgUnmarshalTxn)
 		}
 		recordType, ok := rawRecordType.(string)
 		if !ok {
-			return errors.New("failed to unmarshall transaction")
+			return newError(msgUnmarshalTxn)
 		}
 
 		jsonStr, err := json.Marshal(tMap)
@@ -362,6 +457,20 @@ func (f *Transactions) UnmarshalJSON(data []byte) error {
 				return err
 			}
 			*f = append(*f, &dr)
+		case string(CreditReverseRecord):
+			var cr CreditReverse
+			err = json.Unmarshal(jsonStr, &cr)
+			if err != nil {
+				return err
+			}
+			*f = append(*f, &cr)
+		case string(DebitReverseRecord):
+			var dr DebitReverse
+			err = json.Unmarshal(jsonStr, &dr)
+			if err != nil {
+				return err
+			}
+			*f = append(*f, &dr)
 		}
 	}
 	return nil
@@ -371,10 +480,16 @@ func getTotalValueAndCount(recordType RecordType, txns []Transaction) (int64, in
 	var totalValue int64
 	var totalCount int64
 	for _, t := range txns {
-		if t.GetType() == recordType {
-			totalValue += t.GetAmount()
-			totalCount++
+		if t.GetType() != recordType {
+			continue
+		}
+		sum, err := Cents(totalValue).Add(Cents(t.GetAmount()))
+		if err != nil {
+			// saturate so that FileFooter.Build reports the total as too large for its field
+			sum = math.MaxInt64
 		}
+		totalValue = int64(sum)
+		totalCount++
 	}
 	return totalValue, totalCount
 }
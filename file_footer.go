@@ -2,7 +2,6 @@ package cadeft
 
 import (
 	"fmt"
-	"strings"
 )
 
 // FileFooter represents either Logical Record Type Z or V in the EFT 005 standard spec.
@@ -18,6 +17,8 @@ type FileFooter struct {
 	TotalCountOfERecords int64 `json:"total_count_reverse_debit"`
 	TotalValueOfFRecords int64 `json:"total_value_reverse_credit"`
 	TotalCountOfFRecords int64 `json:"total_count_reverse_credit"`
+
+	raw *rawRecord
 }
 
 func NewFileFooter(recordHeader RecordHeader, txns []Transaction) *FileFooter {
@@ -46,83 +47,30 @@ func NewFileFooter(recordHeader RecordHeader, txns []Transaction) *FileFooter {
 
 // Parse will take in a serialized footer record of type Z and parse the amounts into a FileFooter struct
 func (ff *FileFooter) Parse(line string) error {
-	var err error
 	rs := []rune(line)
 	if len(rs) < zRecordMinLength {
-		return fmt.Errorf("footer is too short")
+		return newError(msgFooterTooShort)
 	}
 
 	recordHeader := RecordHeader{}
-	err = recordHeader.parse(line)
-	if err != nil {
-		return fmt.Errorf("failed to parse record header for footer: %w", err)
+	if err := recordHeader.parse(line); err != nil {
+		return newError(msgParseFooterRecordHeader, err)
 	}
 	ff.RecordHeader = recordHeader
-	ff.TotalValueOfDebit, err = parseNum(string(rs[24:38]))
-	if err != nil {
-		return fmt.Errorf("failed to parse total value of debit: %w", err)
-	}
-	ff.TotalCountOfDebit, err = parseNum(string(rs[38:46]))
-	if err != nil {
-		return fmt.Errorf("failed to parse total count of debit: %w", err)
-	}
-	ff.TotalValueOfCredit, err = parseNum(string(rs[46:60]))
-	if err != nil {
-		return fmt.Errorf("failed to parse total value of credit: %w", err)
-	}
-	ff.TotalCountOfCredit, err = parseNum(string(rs[60:68]))
-	if err != nil {
-		return fmt.Errorf("failed to parse total count of credit: %w", err)
-	}
-	valERecordSegment := string(rs[68:82])
-	if !isFillerString(valERecordSegment) {
-		if ff.TotalValueOfERecords, err = parseNum(valERecordSegment); err != nil {
-			return fmt.Errorf("failed to parse total value of E records: %w", err)
-		}
-	}
-
-	numERecordSegment := string(rs[82:90])
-	if !isFillerString(numERecordSegment) {
-		if ff.TotalCountOfERecords, err = parseNum(numERecordSegment); err != nil {
-			return fmt.Errorf("failed to parse total count of E records: %w", err)
-		}
-	}
-
-	valFRecordsSegment := string(rs[90:104])
-	if !isFillerString(valFRecordsSegment) {
-		if ff.TotalValueOfFRecords, err = parseNum(valFRecordsSegment); err != nil {
-			return fmt.Errorf("failed to parse total value of F records: %w", err)
-		}
-	}
-
-	numFRecordSegment := string(rs[104:112])
-	if !isFillerString(numFRecordSegment) {
-		if ff.TotalCountOfFRecords, err = parseNum(numFRecordSegment); err != nil {
-			return fmt.Errorf("failed to parse totoal count of F records: %w", err)
-		}
-	}
-
-	return nil
+	return decodeFields(FooterRecord, rs, ff)
 }
 
 // Build takes the FileFooter struct, constructs a serialized string and returns it for writing
 func (ff FileFooter) Build() (string, error) {
-	var sb strings.Builder
 	serializedHeader, err := ff.RecordHeader.buildRecordHeader()
 	if err != nil {
 		return "", fmt.Errorf("failed to write record header: %w", err)
 	}
-	sb.WriteString(serializedHeader)
-	sb.WriteString(convertNumToZeroPaddedString(ff.TotalValueOfDebit, 14))
-	sb.WriteString(convertNumToZeroPaddedString(ff.TotalCountOfDebit, 8))
-	sb.WriteString(convertNumToZeroPaddedString(ff.TotalValueOfCredit, 14))
-	sb.WriteString(convertNumToZeroPaddedString(ff.TotalCountOfCredit, 8))
-	sb.WriteString(convertNumToZeroPaddedString(ff.TotalValueOfERecords, 14))
-	sb.WriteString(convertNumToZeroPaddedString(ff.TotalCountOfERecords, 8))
-	sb.WriteString(convertNumToZeroPaddedString(ff.TotalValueOfFRecords, 14))
-	sb.WriteString(convertNumToZeroPaddedString(ff.TotalCountOfFRecords, 8))
-	sb.WriteString(createFillerString(1352))
-	return sb.String(), nil
+	fields, err := encodeFields(FooterRecord, ff)
+	if err != nil {
+		return "", err
+	}
+	return serializedHeader + fields, nil
 }
 
 func (ff FileFooter) GetType() RecordType {
@@ -1,6 +1,7 @@
 package cadeft
 
 import (
+	"math"
 	"strings"
 	"testing"
 
@@ -62,3 +63,34 @@ func TestBuildFileFooter(t *testing.T) {
 	expectedFile := "Z000000000000000061000010000000000200000000002000000000020000000000200000000000000000000000000000000000000000000" + strings.Repeat(" ", 1352)
 	r.Equal(expectedFile, s)
 }
+
+func TestBuildFileFooterOverflow(t *testing.T) {
+	r := require.New(t)
+	recordHeader := RecordHeader{
+		RecordType:      FooterRecord,
+		OriginatorID:    "0000000610",
+		FileCreationNum: 1,
+	}
+	txns := make([]Transaction, 0, 10001)
+	for i := 0; i < 10001; i++ {
+		txns = append(txns, &Credit{BaseTxn: BaseTxn{Amount: 9999999999}})
+	}
+	ff := NewFileFooter(recordHeader, txns)
+	r.Equal(int64(100009999989999), ff.TotalValueOfCredit)
+	_, err := ff.Build()
+	r.ErrorIs(err, ErrFieldOverflow)
+	r.Contains(err.Error(), "total value of credit")
+
+	ff = NewFileFooter(recordHeader, []Transaction{
+		&Debit{BaseTxn: BaseTxn{Amount: math.MaxInt64}},
+		&Debit{BaseTxn: BaseTxn{Amount: 1}},
+	})
+	r.Equal(int64(math.MaxInt64), ff.TotalValueOfDebit)
+	_, err = ff.Build()
+	r.ErrorIs(err, ErrFieldOverflow)
+
+	ff = NewFileFooter(recordHeader, nil)
+	ff.TotalCountOfDebit = 100000000
+	_, err = ff.Build()
+	r.ErrorIs(err, ErrFieldOverflow)
+}
@@ -2,7 +2,6 @@ package cadeft
 
 import (
 	"fmt"
-	"strings"
 	"time"
 )
 
@@ -11,10 +10,11 @@ import (
 // the supported currency should be CAD or USD.
 type FileHeader struct {
 	RecordHeader
-	CreationDate                   *time.Time `json:"creation_date" validate:"required"`
-	DestinationDataCenterNo        int64      `json:"destination_data_center" validate:"min=0,max=99999,numeric"`
-	DirectClearerCommunicationArea string     `json:"communication_area" validate:"max=20"`
-	CurrencyCode                   string     `json:"currency_code" validate:"required,eft_cur,len=3"`
+	CreationDate                   *time.Time `json:"creation_date" validate:"required" spec:"5"`
+	DestinationDataCenterNo        int64      `json:"destination_data_center" validate:"min=0,max=99999,numeric" spec:"6"`
+	DirectClearerCommunicationArea string     `json:"communication_area" validate:"max=20" spec:"7"`
+	CurrencyCode                   string     `json:"currency_code" validate:"required,eft_cur,len=3" spec:"8"`
+	raw                            *rawRecord
 }
 
 func NewFileHeader(
@@ -51,64 +51,34 @@ func WithDirectClearerCommunicationArea(comm string) HeaderOpts {
 }
 
 func (fh *FileHeader) parse(line string) error {
-	var err error
 	rs := []rune(line)
 	if len(rs) < aRecordMinLength {
-		return fmt.Errorf("invalid header record length")
+		return newError(msgInvalidHeaderLength)
 	}
 	recordHeader := RecordHeader{}
-	if err = recordHeader.parse(line); err != nil {
-		return fmt.Errorf("failed to parse record header: %w", err)
+	if err := recordHeader.parse(line); err != nil {
+		return newError(msgParseRecordHeader, err)
 	}
 	fh.RecordHeader = recordHeader
-	creationDate, err := parseDate(string(rs[24:30]))
-	if err != nil {
-		return fmt.Errorf("failed to parse creation date for file header: %w", err)
-	}
-	fh.CreationDate = &creationDate
-	fh.DestinationDataCenterNo, err = parseNum(string(rs[30:35]))
-	if err != nil {
-		return fmt.Errorf("failed to parse destination data center for file header: %w", err)
-	}
-	fh.DirectClearerCommunicationArea = strings.TrimSpace(string(rs[35:55]))
-	fh.CurrencyCode = strings.TrimSpace(string(rs[55:58]))
-	return nil
+	return decodeFields(HeaderRecord, rs, fh)
 }
 
 // Validate checks whether the fields of a FileHeader struct contain the correct fields that are required when writing/reading an EFT file.
 // The validation check can be found on Section D of EFT standard 005.
 func (fh FileHeader) Validate() error {
-	if err := eftValidator.Struct(&fh); err != nil {
-		return err
-	}
-	return nil
+	return validateStruct(&fh, HeaderRecord)
 }
 
 func (fh FileHeader) buildHeader(currRecordCount int) (string, error) {
-	var sb strings.Builder
-
 	rh, err := fh.RecordHeader.buildRecordHeader()
 	if err != nil {
 		return "", fmt.Errorf("failed to write record header for file header: %w", err)
 	}
-	sb.WriteString(rh)
-	if fh.CreationDate != nil {
-		sb.WriteString(convertTimestampToEftDate(*fh.CreationDate))
-	} else {
-		sb.WriteString(convertNumToZeroPaddedString(0, 6))
-	}
-	sb.WriteString(convertNumToZeroPaddedString(fh.DestinationDataCenterNo, 5))
-
-	// add buffering of 20 empty characters Reserved Customer-Direct Clearer Communication area
-	if fh.DirectClearerCommunicationArea == "" {
-		sb.WriteString(createFillerString(20))
-	} else {
-		sb.WriteString(padStringWithBlanks(fh.DirectClearerCommunicationArea, 20))
+	fields, err := encodeFields(HeaderRecord, fh)
+	if err != nil {
+		return "", err
 	}
-
-	sb.WriteString(padStringWithBlanks(fh.CurrencyCode, 3))
-	sb.WriteString(createFillerString(1406))
-	return sb.String(), nil
+	return rh + fields, nil
 }
 
 func isHeaderRecordType(s string) bool {
@@ -2,8 +2,6 @@ package cadeft
 
 import (
 	"bufio"
-	"errors"
-	"fmt"
 	"io"
 	"strings"
 
@@ -20,12 +18,14 @@ type FileStreamer struct {
 	numTxnsPerLine int
 	currentTxn     int
 	currentLine    int
+	cfg            readConfig
 }
 
-func NewFileStream(in io.ReadSeeker) FileStreamer {
+func NewFileStream(in io.ReadSeeker, opts ...ReadOpt) FileStreamer {
 	return FileStreamer{
 		r:       in,
 		scanner: bufio.NewScanner(in),
+		cfg:     newReadConfig(opts),
 	}
 }
 
@@ -39,26 +39,27 @@ func (fs FileStreamer) GetHeader() (*FileHeader, error) {
 	// Frst line of the file should be the header
 	success := scanner.Scan()
 	if !success {
-		return nil, fmt.Errorf("failed to scan for file header: %w", scanner.Err())
+		return nil, newError(msgScanHeader, scanner.Err())
 	}
 
 	line := scanner.Text()
 	if len(line) == 0 {
-		return nil, errors.New("file header is empty")
+		return nil, newError(msgHeaderEmpty)
 	}
 
 	recType, err := parseRecordType(string([]rune(line)[:1]))
 	if err != nil {
-		return nil, fmt.Errorf("file header not found: %w", err)
+		return nil, newError(msgHeaderNotFound, err)
 	}
 	if recType != HeaderRecord {
-		return nil, errors.New("first record in file is not a header record")
+		return nil, newError(msgFirstRecordNotHeader)
 	}
 	header := &FileHeader{}
 	err = header.parse(line)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse file header: %w", err)
+		return nil, newError(msgParseFileHeader, err)
 	}
+	fs.cfg.localizeHeader(header)
 	return header, nil
 }
 
@@ -72,18 +73,18 @@ func (fs FileStreamer) GetFooter() (*FileFooter, error) {
 	for scanner.Scan() {
 		line := scanner.Text()
 		if len(line) < 1 {
-			return nil, errors.New("line too short to determine record type")
+			return nil, newError(msgLineTooShort)
 		}
 		recType := string([]rune(line)[:1])
 		if recType == string(FooterRecord) {
 			ff := &FileFooter{}
 			if err := ff.Parse(line); err != nil {
-				return nil, fmt.Errorf("failed to parse file footer: %w", err)
+				return nil, newError(msgParseFileFooter, err)
 			}
 			return ff, nil
 		}
 	}
-	return nil, errors.New("failed to find footer record")
+	return nil, newError(msgFooterNotFound)
 }
 
 // ScanTxn parses transaction records (D, C, I, J, E and F logical records) one at a time. Upon successfully parsing a transaction segment a Transaction struct is returned otherwise a non nil error is returned in
@@ -94,13 +95,13 @@ func (fs *FileStreamer) ScanTxn() (Transaction, error) {
 	if fs.currentLine == 0 {
 		if !fs.scanner.Scan() {
 			if fs.scanner.Err() != nil {
-				return nil, fmt.Errorf("failed forward reader to txn record: %w", fs.scanner.Err())
+				return nil, newError(msgForwardToTxn, fs.scanner.Err())
 			}
 			return nil, io.EOF
 
 		}
 		if len(fs.scanner.Text()) == 0 || !isHeaderRecordType(string([]rune(fs.scanner.Text())[:1])) {
-			return nil, errors.New("first line in file is not a header record")
+			return nil, newError(msgFirstLineNotHeader)
 		}
 		fs.currentLine++
 	}
@@ -109,7 +110,7 @@ func (fs *FileStreamer) ScanTxn() (Transaction, error) {
 	if fs.currentTxn == 0 && fs.numTxnsPerLine == 0 {
 		if !fs.scanner.Scan() {
 			if fs.scanner.Err() != nil {
-				return nil, fmt.Errorf("failed to read transactions: %w", fs.scanner.Err())
+				return nil, newError(msgReadTxns, fs.scanner.Err())
 			}
 			return nil, io.EOF
 		}
@@ -117,7 +118,7 @@ func (fs *FileStreamer) ScanTxn() (Transaction, error) {
 
 		line, err := normalize(strings.TrimSpace(fs.scanner.Text()))
 		if err != nil {
-			return nil, fmt.Errorf("failed to read transaction line: %w", err)
+			return nil, newError(msgReadTxnLine, err)
 		}
 
 		fs.lineContents = []rune(line)
@@ -127,11 +128,11 @@ func (fs *FileStreamer) ScanTxn() (Transaction, error) {
 		}
 
 		if len(fs.lineContents) < commonRecordDataLength {
-			return nil, fmt.Errorf("txn record at line %d shorter than common header length %d", fs.currentLine, commonRecordDataLength)
+			return nil, newError(msgTxnLineShort, fs.currentLine, commonRecordDataLength)
 		}
 
 		if len(fs.lineContents[commonRecordDataLength:])%segmentLength != 0 {
-			return nil, fmt.Errorf("txn record at line %d is not of correct length", fs.currentLine)
+			return nil, newError(msgTxnLineLength, fs.currentLine)
 		}
 
 		fs.numTxnsPerLine = len(fs.lineContents[commonRecordDataLength:]) / segmentLength
@@ -145,7 +146,7 @@ func (fs *FileStreamer) ScanTxn() (Transaction, error) {
 	recordType, err := parseRecordType(string(fs.lineContents[:1]))
 	if err != nil {
 		fs.currentTxn, fs.numTxnsPerLine = 0, 0
-		return nil, fmt.Errorf("unrecognized record type at line %d: %w", fs.currentLine, err)
+		return nil, newError(msgUnrecognizedRecTypeAt, fs.currentLine, err)
 	}
 
 	if fs.currentTxn == (fs.numTxnsPerLine - 1) {
@@ -155,13 +156,13 @@ func (fs *FileStreamer) ScanTxn() (Transaction, error) {
 
 	// determine starting index and ending index from currentTxn
 	if len(fs.lineContents) < commonRecordDataLength {
-		return nil, fmt.Errorf("txn record at line %d shorter than common header length %d", fs.currentLine, commonRecordDataLength)
+		return nil, newError(msgTxnLineShort, fs.currentLine, commonRecordDataLength)
 	}
 	txnsSegment := fs.lineContents[commonRecordDataLength:]
 	startIdx := segmentLength * fs.currentTxn
 	endIdx := segmentLength * (fs.currentTxn + 1)
 	if endIdx > len(txnsSegment) {
-		return nil, fmt.Errorf("txn segment bounds out of range at line %d", fs.currentLine)
+		return nil, newError(msgSegmentBounds, fs.currentLine)
 	}
 	seg := string(txnsSegment[startIdx:endIdx])
 
@@ -205,8 +206,9 @@ func (fs *FileStreamer) ScanTxn() (Transaction, error) {
 		}
 		txn = &dr
 	case HeaderRecord, NoticeOfChangeRecord, NoticeOfChangeHeader, NoticeOfChangeFooter, FooterRecord:
-		return nil, fmt.Errorf("invalid record type: %v", recordType)
+		return nil, newError(msgInvalidRecordTypeOf, recordType)
 	}
+	fs.cfg.localizeTxn(txn)
 	return txn, nil
 }
 
@@ -222,7 +224,7 @@ func (fs *FileStreamer) reset() {
 func newStreamParseError(err error, recordType string, txnNum, line int) error {
 	return multierror.Append(
 		err,
-		fmt.Errorf("parse error for record %s number %d line %d", recordType, txnNum, line),
+		newError(msgStreamParseError, recordType, txnNum, line),
 		ErrScanParseError,
 	)
 }
@@ -269,3 +269,59 @@ Z0000000060000000001000100000000014000000000070000000001400000000007000000000000
 		})
 	}
 }
+
+func TestReadFileWithLocation(t *testing.T) {
+	r := require.New(t)
+	toronto, err := time.LoadLocation("America/Toronto")
+	r.NoError(err)
+	date := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	header := NewFileHeader("0000000001", 1, &date, 12345, "CAD")
+	file := NewFile(header, Transactions{
+		Ptr(NewDebit("400", 1000, &date, "987654321", "1234", "12345", "Short name", "payor name", "my long name", "123456789", "1111111")),
+	})
+	raw, err := file.Create()
+	r.NoError(err)
+
+	f, err := NewReader(strings.NewReader(raw), WithLocation(toronto)).ReadFile()
+	r.NoError(err)
+	expected := time.Date(2023, 10, 2, 0, 0, 0, 0, toronto)
+	r.Equal(expected, *f.Header.CreationDate)
+	r.Equal(expected, *f.Txns[0].GetDate())
+
+	streamer := NewFileStream(strings.NewReader(raw), WithLocation(toronto))
+	streamedHeader, err := streamer.GetHeader()
+	r.NoError(err)
+	r.Equal(expected, *streamedHeader.CreationDate)
+	txn, err := streamer.ScanTxn()
+	r.NoError(err)
+	r.Equal(expected, *txn.GetDate())
+
+	// writing a parsed file back out keeps the same julian dates
+	rebuilt, err := f.Create()
+	r.NoError(err)
+	r.Equal(raw, rebuilt)
+}
+
+func TestReadFileNilDates(t *testing.T) {
+	r := require.New(t)
+	header := NewFileHeader("0000000001", 1, nil, 12345, "CAD")
+	file := NewFile(header, Transactions{
+		Ptr(NewDebit("400", 1000, nil, "987654321", "1234", "12345", "Short name", "payor name", "my long name", "123456789", "1111111")),
+	})
+	raw, err := file.Create()
+	r.NoError(err)
+
+	f, err := NewReader(strings.NewReader(raw)).ReadFile()
+	r.NoError(err)
+	r.Nil(f.Header.CreationDate)
+	r.Nil(f.Txns[0].GetDate())
+
+	rebuilt, err := f.Create()
+	r.NoError(err)
+	r.Equal(raw, rebuilt)
+
+	// a date with a real day of year out of range is still rejected
+	invalid := raw[:24] + "026000" + raw[30:]
+	_, err = NewReader(strings.NewReader(invalid)).ReadFile()
+	r.ErrorContains(err, "invalid day of year 000 for 2026")
+}
@@ -0,0 +1,182 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/moov-io/cadeft"
+)
+
+// Camt054Namespace is the namespace of the debit and credit notifications written
+const Camt054Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.054.001.08"
+
+type camt054Document struct {
+	XMLName      xml.Name                   `xml:"urn:iso:std:iso:20022:tech:xsd:camt.054.001.08 Document"`
+	Notification bankToCustomerNotification `xml:"BkToCstmrDbtCdtNtfctn"`
+}
+
+type bankToCustomerNotification struct {
+	GroupHeader   reportGroupHeader     `xml:"GrpHdr"`
+	Notifications []accountNotification `xml:"Ntfctn"`
+}
+
+type accountNotification struct {
+	ID           string              `xml:"Id"`
+	CreationDate string              `xml:"CreDtTm"`
+	Account      notificationAccount `xml:"Acct"`
+	Entries      []reportEntry       `xml:"Ntry"`
+}
+
+type notificationAccount struct {
+	ID       accountID `xml:"Id"`
+	Currency string    `xml:"Ccy,omitempty"`
+	Servicer *agent    `xml:"Svcr,omitempty"`
+}
+
+type reportEntry struct {
+	Amount            amount              `xml:"Amt"`
+	CreditDebit       string              `xml:"CdtDbtInd"`
+	ReversalIndicator bool                `xml:"RvslInd,omitempty"`
+	Status            entryStatus         `xml:"Sts"`
+	BookingDate       *dateAndDateTime    `xml:"BookgDt,omitempty"`
+	ValueDate         *dateAndDateTime    `xml:"ValDt,omitempty"`
+	BankTxnCode       bankTransactionCode `xml:"BkTxCd"`
+	Details           []entryDetails      `xml:"NtryDtls"`
+}
+
+type entryStatus struct {
+	Code string `xml:"Cd"`
+}
+
+type bankTransactionCode struct {
+	Domain bankTransactionDomain `xml:"Domn"`
+}
+
+type bankTransactionDomain struct {
+	Code   string                `xml:"Cd"`
+	Family bankTransactionFamily `xml:"Fmly"`
+}
+
+type bankTransactionFamily struct {
+	Code      string `xml:"Cd"`
+	SubFamily string `xml:"SubFmlyCd"`
+}
+
+type entryDetails struct {
+	Transactions []transactionDetails `xml:"TxDtls"`
+}
+
+type transactionDetails struct {
+	References     references         `xml:"Refs"`
+	Amount         amount             `xml:"Amt"`
+	CreditDebit    string             `xml:"CdtDbtInd"`
+	RelatedParties relatedParties     `xml:"RltdPties"`
+	RelatedAgents  relatedAgents      `xml:"RltdAgts"`
+	Return         *returnInformation `xml:"RtrInf,omitempty"`
+}
+
+type references struct {
+	InstructionID string `xml:"InstrId,omitempty"`
+	EndToEndID    string `xml:"EndToEndId,omitempty"`
+	MandateID     string `xml:"MndtId,omitempty"`
+}
+
+type relatedParties struct {
+	Debtor          *partyChoice `xml:"Dbtr"`
+	DebtorAccount   *cashAccount `xml:"DbtrAcct"`
+	Creditor        *partyChoice `xml:"Cdtr"`
+	CreditorAccount *cashAccount `xml:"CdtrAcct"`
+}
+
+type relatedAgents struct {
+	DebtorAgent   *agent `xml:"DbtrAgt"`
+	CreditorAgent *agent `xml:"CdtrAgt"`
+}
+
+type returnInformation struct {
+	Reason         *reason  `xml:"Rsn,omitempty"`
+	AdditionalInfo []string `xml:"AddtlInf,omitempty"`
+}
+
+// FileToCamt054 notifies the originator of the returned and reversed items of a returns file, one notification per originator account.
+// Returned and reversed credits (I and E records) are credited back, returned and reversed debits (J and F records) are debited.
+// Every entry links back to the original item through the end to end ID FileToPain001 or FileToPain008 wrote, returned items carry
+// the ISO 20022 reason of their CPA return reason and reversals are flagged with the reversal indicator. Other records are reported as issues.
+func FileToCamt054(f cadeft.File, opts ...ReportOpt) ([]byte, []Issue, error) {
+	if f.Header == nil {
+		return nil, nil, fmt.Errorf("file header is missing")
+	}
+	cfg := newReportConfig(opts)
+	var is issues
+	msgID := messageID(f.Header)
+	doc := camt054Document{Notification: bankToCustomerNotification{
+		GroupHeader: reportGroupHeader{MessageID: msgID, CreationDate: creationDateTime(f.Header)},
+	}}
+	index := map[string]int{}
+	for i, t := range f.Txns {
+		item, ok := newReturnedItem(t)
+		if !ok {
+			is.add(i, "type", string(t.GetType()), "only returned and reversed items are notified")
+			continue
+		}
+		key := strings.TrimSpace(item.originatorInstitutionID) + "/" + strings.TrimSpace(item.originatorAccountNo)
+		n, ok := index[key]
+		if !ok {
+			n = len(doc.Notification.Notifications)
+			index[key] = n
+			doc.Notification.Notifications = append(doc.Notification.Notifications, accountNotification{
+				ID:           fmt.Sprintf("%s-%d", msgID, n+1),
+				CreationDate: creationDateTime(f.Header),
+				Account: notificationAccount{
+					ID:       newAccount(item.originatorAccountNo).ID,
+					Currency: f.Header.CurrencyCode,
+					Servicer: cadeft.Ptr(newAgent(item.originatorInstitutionID)),
+				},
+			})
+		}
+		notification := &doc.Notification.Notifications[n]
+		notification.Entries = append(notification.Entries, newReportEntry(cfg, &is, i, item, f.Header))
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, is, fmt.Errorf("failed to write camt.054: %w", err)
+	}
+	return append([]byte(xml.Header), out...), is, nil
+}
+
+func newReportEntry(cfg reportConfig, is *issues, i int, item returnedItem, header *cadeft.FileHeader) reportEntry {
+	b := item.txn.GetBaseTxn()
+	amt := amount{Currency: header.CurrencyCode, Value: formatAmount(b.Amount)}
+	// money goes back to the originator of a credit and is taken back from the originator of a debit
+	creditDebit, family, subFamily := "DBIT", "IDDT", "UPDD"
+	if item.credit {
+		creditDebit, family, subFamily = "CRDT", "ICDT", "RRTN"
+	}
+	if item.reversal {
+		subFamily = "RRTN"
+	}
+	details := transactionDetails{
+		References:  references{InstructionID: item.originalInstructionID(), EndToEndID: item.originalEndToEndID(), MandateID: item.mandateID()},
+		Amount:      amt,
+		CreditDebit: creditDebit,
+	}
+	rp, ra := &details.RelatedParties, &details.RelatedAgents
+	rp.Debtor, rp.Creditor, rp.DebtorAccount, rp.CreditorAccount, ra.DebtorAgent, ra.CreditorAgent = item.parties()
+	if !item.reversal {
+		if r, info := cfg.returnReason(is, i, b); r != nil {
+			details.Return = &returnInformation{Reason: r, AdditionalInfo: info}
+		}
+	}
+	return reportEntry{
+		Amount:            amt,
+		CreditDebit:       creditDebit,
+		ReversalIndicator: item.reversal,
+		Status:            entryStatus{Code: "BOOK"},
+		BookingDate:       dateOnly(header.CreationDate),
+		ValueDate:         dateOnly(item.txn.GetDate()),
+		BankTxnCode:       bankTransactionCode{Domain: bankTransactionDomain{Code: "PMNT", Family: bankTransactionFamily{Code: family, SubFamily: subFamily}}},
+		Details:           []entryDetails{{Transactions: []transactionDetails{details}}},
+	}
+}
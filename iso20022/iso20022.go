@@ -0,0 +1,312 @@
+// Package iso20022 converts cadeft files to and from ISO 20022 payment messages.
+// CPA-005 and ISO 20022 do not carry the same fields, whatever cannot be converted as is
+// is reported as an Issue alongside the converted message or file.
+package iso20022
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/moov-io/cadeft"
+)
+
+// ClearingSystemCode identifies Payments Canada routing numbers in clearing system member IDs
+const ClearingSystemCode = "CACPA"
+
+// notProvided is the end to end ID of payments that do not carry one
+const notProvided = "NOTPROVIDED"
+
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// Issue is a field that could not be converted as is, it was dropped, truncated or defaulted
+type Issue struct {
+	// Index is the position of the transaction in File.Txns or in the message, -1 for file and group header fields
+	Index  int    `json:"index"`
+	Field  string `json:"field"`
+	Value  string `json:"value,omitempty"`
+	Reason string `json:"reason"`
+}
+
+func (i Issue) String() string {
+	if i.Index < 0 {
+		return fmt.Sprintf("%s %q: %s", i.Field, i.Value, i.Reason)
+	}
+	return fmt.Sprintf("txn %d: %s %q: %s", i.Index, i.Field, i.Value, i.Reason)
+}
+
+type issues []Issue
+
+func (is *issues) add(index int, field, value, reason string) {
+	*is = append(*is, Issue{Index: index, Field: field, Value: value, Reason: reason})
+}
+
+// fit truncates value to the length of a CPA-005 field and reports it when it is cut
+func (is *issues) fit(index int, field, value string, length int) string {
+	value = strings.TrimSpace(value)
+	if utf8.RuneCountInString(value) <= length {
+		return value
+	}
+	is.add(index, field, value, fmt.Sprintf("truncated to %d characters", length))
+	return string([]rune(value)[:length])
+}
+
+// amount is an ActiveOrHistoricCurrencyAndAmount
+type amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type partyIdentification struct {
+	Name string   `xml:"Nm,omitempty"`
+	ID   *partyID `xml:"Id,omitempty"`
+}
+
+type partyID struct {
+	OrgID *organisationID `xml:"OrgId,omitempty"`
+}
+
+type organisationID struct {
+	Other []genericID `xml:"Othr"`
+}
+
+type genericID struct {
+	ID         string      `xml:"Id"`
+	SchemeName *schemeName `xml:"SchmeNm,omitempty"`
+}
+
+type schemeName struct {
+	Code        string `xml:"Cd,omitempty"`
+	Proprietary string `xml:"Prtry,omitempty"`
+}
+
+type cashAccount struct {
+	ID accountID `xml:"Id"`
+}
+
+type accountID struct {
+	IBAN  string     `xml:"IBAN,omitempty"`
+	Other *genericID `xml:"Othr,omitempty"`
+}
+
+type agent struct {
+	FinInstnID financialInstitutionID `xml:"FinInstnId"`
+}
+
+type financialInstitutionID struct {
+	BICFI       string                  `xml:"BICFI,omitempty"`
+	ClrSysMmbID *clearingSystemMemberID `xml:"ClrSysMmbId,omitempty"`
+}
+
+type clearingSystemMemberID struct {
+	ClrSysID *clearingSystemID `xml:"ClrSysId,omitempty"`
+	MemberID string            `xml:"MmbId"`
+}
+
+type clearingSystemID struct {
+	Code string `xml:"Cd"`
+}
+
+type paymentTypeInformation struct {
+	CategoryPurpose *categoryPurpose `xml:"CtgyPurp,omitempty"`
+}
+
+type categoryPurpose struct {
+	Proprietary string `xml:"Prtry"`
+}
+
+type remittanceInformation struct {
+	Unstructured []string `xml:"Ustrd"`
+}
+
+// dateAndDateTime is a DateAndDateTime2Choice
+type dateAndDateTime struct {
+	Date     string `xml:"Dt,omitempty"`
+	DateTime string `xml:"DtTm,omitempty"`
+}
+
+// newAgent identifies a financial institution by its CPA-005 routing number, 0 followed by the institution and transit numbers
+func newAgent(institutionID string) agent {
+	return agent{FinInstnID: financialInstitutionID{ClrSysMmbID: &clearingSystemMemberID{
+		ClrSysID: &clearingSystemID{Code: ClearingSystemCode},
+		MemberID: strings.TrimSpace(institutionID),
+	}}}
+}
+
+// institutionID returns the routing number of an agent, BICs and routing numbers of other clearing systems are reported
+func (is *issues) institutionID(index int, field string, a *agent) string {
+	if a == nil {
+		is.add(index, field, "", "missing agent")
+		return ""
+	}
+	m := a.FinInstnID.ClrSysMmbID
+	if m == nil {
+		is.add(index, field, a.FinInstnID.BICFI, "agents must be identified by a Payments Canada routing number")
+		return ""
+	}
+	if m.ClrSysID != nil && m.ClrSysID.Code != ClearingSystemCode {
+		is.add(index, field, m.MemberID, "clearing system "+m.ClrSysID.Code+" is not Payments Canada")
+	}
+	id := strings.TrimSpace(m.MemberID)
+	if len(id) != 9 || !isDigits(id) {
+		is.add(index, field, id, "routing numbers are 9 digits, 0 followed by the institution and transit numbers")
+	}
+	return id
+}
+
+func newAccount(accountNo string) *cashAccount {
+	return &cashAccount{ID: accountID{Other: &genericID{ID: strings.TrimSpace(accountNo)}}}
+}
+
+// accountNo returns the account number of an account, IBANs are reported
+func (is *issues) accountNo(index int, field string, a *cashAccount, length int) string {
+	switch {
+	case a == nil:
+		is.add(index, field, "", "missing account")
+		return ""
+	case a.ID.Other == nil:
+		is.add(index, field, a.ID.IBAN, "IBANs are not supported")
+		return ""
+	}
+	return is.fit(index, field, a.ID.Other.ID, length)
+}
+
+func newOrganisation(name, id string) partyIdentification {
+	p := partyIdentification{Name: strings.TrimSpace(name)}
+	if id = strings.TrimSpace(id); id != "" {
+		p.ID = &partyID{OrgID: &organisationID{Other: []genericID{{ID: id}}}}
+	}
+	return p
+}
+
+// organisationID returns the first other identification of a party
+func (p *partyIdentification) organisationID() string {
+	if p == nil || p.ID == nil || p.ID.OrgID == nil || len(p.ID.OrgID.Other) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(p.ID.OrgID.Other[0].ID)
+}
+
+func (p *partyIdentification) name() string {
+	if p == nil {
+		return ""
+	}
+	return p.Name
+}
+
+func newRemittance(sundryInfo string) *remittanceInformation {
+	if sundryInfo = strings.TrimSpace(sundryInfo); sundryInfo == "" {
+		return nil
+	}
+	return &remittanceInformation{Unstructured: []string{sundryInfo}}
+}
+
+func (r *remittanceInformation) text() string {
+	if r == nil {
+		return ""
+	}
+	return strings.Join(r.Unstructured, " ")
+}
+
+func newPaymentType(txnType cadeft.TransactionType) *paymentTypeInformation {
+	return &paymentTypeInformation{CategoryPurpose: &categoryPurpose{Proprietary: string(txnType)}}
+}
+
+// txnType returns the CPA-005 transaction type carried as proprietary category purpose, or defaultType
+func (is *issues) txnType(index int, p *paymentTypeInformation, defaultType cadeft.TransactionType) cadeft.TransactionType {
+	if p == nil || p.CategoryPurpose == nil || p.CategoryPurpose.Proprietary == "" {
+		return defaultType
+	}
+	code := strings.TrimSpace(p.CategoryPurpose.Proprietary)
+	if len(code) != 3 || !isDigits(code) {
+		is.add(index, "txn_type", code, fmt.Sprintf("not a CPA transaction type, using %s", defaultType))
+		return defaultType
+	}
+	return cadeft.TransactionType(code)
+}
+
+// formatAmount writes cents as an ISO 20022 decimal amount
+func formatAmount(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// parseAmount reads an ISO 20022 decimal amount into cents, fractions of a cent are rejected rather than rounded
+func parseAmount(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" || !isDigits(whole) || (fraction != "" && !isDigits(fraction)) {
+		return 0, fmt.Errorf("%w %q", ErrInvalidAmount, s)
+	}
+	if len(fraction) > 2 {
+		if strings.Trim(fraction[2:], "0") != "" {
+			return 0, fmt.Errorf("%w %q: fractions of a cent", ErrInvalidAmount, s)
+		}
+		fraction = fraction[:2]
+	}
+	fraction += strings.Repeat("0", 2-len(fraction))
+	cents, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w %q: %w", ErrInvalidAmount, s, err)
+	}
+	return cents, nil
+}
+
+// parseDate reads an ISO date or date time, only the date part of a date time is kept
+func parseDate(d dateAndDateTime) (*time.Time, error) {
+	s := d.Date
+	if s == "" && len(d.DateTime) >= len(time.DateOnly) {
+		s = d.DateTime[:len(time.DateOnly)]
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: %w", s, err)
+	}
+	return &t, nil
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// itemTraceNo writes an item trace number with all of its 22 digits so that built and parsed files are given the same IDs
+func itemTraceNo(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.Trim(s, "0") == "" {
+		return ""
+	}
+	if isDigits(s) && len(s) < 22 {
+		return strings.Repeat("0", 22-len(s)) + s
+	}
+	return s
+}
+
+// messageID names a message after the originator and file creation number of a file
+func messageID(h *cadeft.FileHeader) string {
+	return fmt.Sprintf("%s-%04d", strings.TrimSpace(h.OriginatorID), h.FileCreationNum)
+}
+
+func creationDateTime(h *cadeft.FileHeader) string {
+	if h.CreationDate == nil {
+		return time.Now().Format("2006-01-02T15:04:05")
+	}
+	return h.CreationDate.Format("2006-01-02T15:04:05")
+}
@@ -0,0 +1,251 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/moov-io/cadeft"
+)
+
+// Pain001Namespace is the namespace of the customer credit transfer initiation messages read and written
+const Pain001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.09"
+
+// DefaultCreditTxnType is the CPA transaction type of imported credits that do not carry one as proprietary category purpose
+const DefaultCreditTxnType cadeft.TransactionType = "450"
+
+type pain001Document struct {
+	XMLName    xml.Name                   `xml:"urn:iso:std:iso:20022:tech:xsd:pain.001.001.09 Document"`
+	Initiation customerCreditTransferInit `xml:"CstmrCdtTrfInitn"`
+}
+
+type customerCreditTransferInit struct {
+	GroupHeader groupHeader             `xml:"GrpHdr"`
+	Payments    []creditPaymentInstruct `xml:"PmtInf"`
+}
+
+type groupHeader struct {
+	MessageID       string              `xml:"MsgId"`
+	CreationDate    string              `xml:"CreDtTm"`
+	NumberOfTxns    int                 `xml:"NbOfTxs"`
+	ControlSum      string              `xml:"CtrlSum,omitempty"`
+	InitiatingParty partyIdentification `xml:"InitgPty"`
+}
+
+type creditPaymentInstruct struct {
+	PaymentInfoID  string                 `xml:"PmtInfId"`
+	PaymentMethod  string                 `xml:"PmtMtd"`
+	NumberOfTxns   int                    `xml:"NbOfTxs,omitempty"`
+	ControlSum     string                 `xml:"CtrlSum,omitempty"`
+	ExecutionDate  dateAndDateTime        `xml:"ReqdExctnDt"`
+	Debtor         partyIdentification    `xml:"Dbtr"`
+	DebtorAccount  *cashAccount           `xml:"DbtrAcct"`
+	DebtorAgent    *agent                 `xml:"DbtrAgt"`
+	CreditTransfer []creditTransferTxnInf `xml:"CdtTrfTxInf"`
+}
+
+type creditTransferTxnInf struct {
+	PaymentID       paymentID               `xml:"PmtId"`
+	PaymentType     *paymentTypeInformation `xml:"PmtTpInf,omitempty"`
+	Amount          instructedAmount        `xml:"Amt"`
+	UltimateDebtor  *partyIdentification    `xml:"UltmtDbtr,omitempty"`
+	CreditorAgent   *agent                  `xml:"CdtrAgt"`
+	Creditor        partyIdentification     `xml:"Cdtr"`
+	CreditorAccount *cashAccount            `xml:"CdtrAcct"`
+	Remittance      *remittanceInformation  `xml:"RmtInf,omitempty"`
+}
+
+type paymentID struct {
+	InstructionID string `xml:"InstrId,omitempty"`
+	EndToEndID    string `xml:"EndToEndId"`
+}
+
+type instructedAmount struct {
+	Instructed amount `xml:"InstdAmt"`
+}
+
+// FileToPain001 converts the credits of f to a pain.001.001.09 message. Credits sharing a date funds available, originator long name
+// and return account are grouped in one payment information block. The item trace number is written as instruction ID and the
+// cross reference number as end to end ID, or the item trace number when there is none. Records other than credits and fields
+// without a pain.001 counterpart are reported as issues.
+func FileToPain001(f cadeft.File) ([]byte, []Issue, error) {
+	if f.Header == nil {
+		return nil, nil, fmt.Errorf("file header is missing")
+	}
+	var is issues
+	doc := pain001Document{}
+	msgID := messageID(f.Header)
+	index := map[string]int{}
+	var sums []int64
+	for i, t := range f.Txns {
+		credit, ok := t.(*cadeft.Credit)
+		if !ok {
+			is.add(i, "type", string(t.GetType()), "only credits are converted to pain.001")
+			continue
+		}
+		reportUnmapped(&is, i, credit.BaseTxn)
+		key := strings.Join([]string{formatDate(credit.DateFundsAvailable), credit.OriginatorLongName, credit.ReturnInstitutionID, credit.ReturnAccountNo}, "/")
+		p, ok := index[key]
+		if !ok {
+			p = len(doc.Initiation.Payments)
+			index[key] = p
+			sums = append(sums, 0)
+			doc.Initiation.Payments = append(doc.Initiation.Payments, creditPaymentInstruct{
+				PaymentInfoID: fmt.Sprintf("%s-%d", msgID, p+1),
+				PaymentMethod: "TRF",
+				ExecutionDate: dateAndDateTime{Date: formatDate(credit.DateFundsAvailable)},
+				Debtor:        partyIdentification{Name: strings.TrimSpace(credit.OriginatorLongName)},
+				DebtorAccount: newAccount(credit.ReturnAccountNo),
+				DebtorAgent:   cadeft.Ptr(newAgent(credit.ReturnInstitutionID)),
+			})
+		}
+		payment := &doc.Initiation.Payments[p]
+		payment.CreditTransfer = append(payment.CreditTransfer, newCreditTransfer(credit, f.Header.CurrencyCode))
+		sums[p] += credit.Amount
+	}
+
+	var total int64
+	for p := range doc.Initiation.Payments {
+		payment := &doc.Initiation.Payments[p]
+		payment.NumberOfTxns = len(payment.CreditTransfer)
+		payment.ControlSum = formatAmount(sums[p])
+		doc.Initiation.GroupHeader.NumberOfTxns += payment.NumberOfTxns
+		total += sums[p]
+	}
+	doc.Initiation.GroupHeader.MessageID = msgID
+	doc.Initiation.GroupHeader.CreationDate = creationDateTime(f.Header)
+	doc.Initiation.GroupHeader.ControlSum = formatAmount(total)
+	doc.Initiation.GroupHeader.InitiatingParty = newOrganisation("", f.Header.OriginatorID)
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, is, fmt.Errorf("failed to write pain.001: %w", err)
+	}
+	return append([]byte(xml.Header), out...), is, nil
+}
+
+func newCreditTransfer(c *cadeft.Credit, currency string) creditTransferTxnInf {
+	txn := creditTransferTxnInf{
+		PaymentID:       newPaymentID(c.BaseTxn),
+		PaymentType:     newPaymentType(c.TxnType),
+		Amount:          instructedAmount{Instructed: amount{Currency: currency, Value: formatAmount(c.Amount)}},
+		CreditorAgent:   cadeft.Ptr(newAgent(c.InstitutionID)),
+		Creditor:        partyIdentification{Name: strings.TrimSpace(c.PayeeName)},
+		CreditorAccount: newAccount(c.PayeeAccountNo),
+		Remittance:      newRemittance(c.SundryInfo),
+	}
+	if name := strings.TrimSpace(c.OriginatorShortName); name != "" {
+		txn.UltimateDebtor = &partyIdentification{Name: name}
+	}
+	return txn
+}
+
+// Pain001ToFile converts a pain.001.001.09 message to a file of credits under header, which provides the originator ID,
+// file creation number and currency a pain.001 message does not carry. The message must be in the currency of the header.
+// Values too long for their CPA-005 field are truncated and, like agents and accounts that cannot be expressed in CPA-005, reported as issues.
+// The resulting file is not validated.
+func Pain001ToFile(data []byte, header *cadeft.FileHeader) (cadeft.File, []Issue, error) {
+	if header == nil {
+		return cadeft.File{}, nil, fmt.Errorf("file header is missing")
+	}
+	var doc pain001Document
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return cadeft.File{}, nil, fmt.Errorf("failed to read pain.001: %w", err)
+	}
+	var is issues
+	h := *header
+	file := cadeft.NewFile(&h, nil)
+	if id := doc.Initiation.GroupHeader.InitiatingParty.organisationID(); id != "" && id != strings.TrimSpace(header.OriginatorID) {
+		is.add(-1, "originator_id", id, "initiating party differs from the originator ID of the header, using "+header.OriginatorID)
+	}
+	for _, payment := range doc.Initiation.Payments {
+		date, err := parseDate(payment.ExecutionDate)
+		if err != nil {
+			return cadeft.File{}, is, fmt.Errorf("payment %s: requested execution date: %w", payment.PaymentInfoID, err)
+		}
+		for _, txn := range payment.CreditTransfer {
+			i := len(file.Txns)
+			cents, err := txnAmount(i, txn.Amount.Instructed, header.CurrencyCode)
+			if err != nil {
+				return cadeft.File{}, is, err
+			}
+			itemTraceNo, crossRefNo := is.paymentIDs(i, txn.PaymentID)
+			shortName := payment.Debtor.name()
+			if txn.UltimateDebtor != nil {
+				shortName = txn.UltimateDebtor.name()
+			}
+			credit := cadeft.NewCredit(
+				is.txnType(i, txn.PaymentType, DefaultCreditTxnType),
+				cents,
+				date,
+				is.institutionID(i, "institution_id", txn.CreditorAgent),
+				is.accountNo(i, "payee_account_no", txn.CreditorAccount, 12),
+				itemTraceNo,
+				is.fit(i, "short_name", shortName, 15),
+				is.fit(i, "payee_name", txn.Creditor.Name, 30),
+				is.fit(i, "long_name", payment.Debtor.Name, 30),
+				is.institutionID(i, "return_institution_id", payment.DebtorAgent),
+				is.accountNo(i, "return_account_no", payment.DebtorAccount, 12),
+				cadeft.WithCrossRefNo(crossRefNo),
+				cadeft.WithSundryInfo(is.fit(i, "sundry_info", txn.Remittance.text(), 15)),
+			)
+			file.Txns = append(file.Txns, &credit)
+		}
+	}
+	return file, is, nil
+}
+
+// txnAmount reads the amount of the transaction at index i in cents, amounts in another currency than the file are rejected
+func txnAmount(i int, a amount, currency string) (int64, error) {
+	if a.Currency != currency {
+		return 0, fmt.Errorf("txn %d: currency %s does not match the file currency %s", i, a.Currency, currency)
+	}
+	cents, err := parseAmount(a.Value)
+	if err != nil {
+		return 0, fmt.Errorf("txn %d: %w", i, err)
+	}
+	return cents, nil
+}
+
+// newPaymentID writes the item trace number as instruction ID and the cross reference number, or the item trace number, as end to end ID
+func newPaymentID(b cadeft.BaseTxn) paymentID {
+	id := paymentID{InstructionID: itemTraceNo(b.ItemTraceNo), EndToEndID: strings.TrimSpace(b.CrossRefNo)}
+	if id.EndToEndID == "" {
+		id.EndToEndID = id.InstructionID
+	}
+	if id.EndToEndID == "" {
+		id.EndToEndID = notProvided
+	}
+	return id
+}
+
+// paymentIDs returns the item trace number and cross reference number written by newPaymentID
+func (is *issues) paymentIDs(i int, id paymentID) (string, string) {
+	itemTraceNo := strings.TrimSpace(id.InstructionID)
+	if itemTraceNo != "" && (len(itemTraceNo) > 22 || !isDigits(itemTraceNo)) {
+		is.add(i, "item_trace_no", itemTraceNo, "instruction ID is not an item trace number, dropped")
+		itemTraceNo = ""
+	}
+	crossRefNo := strings.TrimSpace(id.EndToEndID)
+	if crossRefNo == notProvided || crossRefNo == strings.TrimSpace(id.InstructionID) {
+		crossRefNo = ""
+	}
+	return itemTraceNo, is.fit(i, "cross_ref_no", crossRefNo, 19)
+}
+
+// reportUnmapped reports the transaction fields that have no counterpart in ISO 20022 messages
+func reportUnmapped(is *issues, i int, b cadeft.BaseTxn) {
+	unmapped := []struct{ field, value string }{
+		{"user_id", b.UserID},
+		{"settlement_code", b.SettlementCode},
+		{"invalid_data_element_id", strings.Trim(b.InvalidDataElementID, "0 ")},
+	}
+	if stored := strings.TrimSpace(string(b.StoredTransactionType)); stored != "" && strings.Trim(stored, "0") != "" {
+		unmapped = append(unmapped, struct{ field, value string }{"stored_txn_type", string(b.StoredTransactionType)})
+	}
+	for _, u := range unmapped {
+		if strings.TrimSpace(u.value) != "" {
+			is.add(i, u.field, strings.TrimSpace(u.value), "no ISO 20022 counterpart, dropped")
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package iso20022
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moov-io/cadeft"
+	"github.com/stretchr/testify/require"
+)
+
+func testHeader() *cadeft.FileHeader {
+	return cadeft.NewFileHeader("0000012345", 7, cadeft.Ptr(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)), 86900, "CAD")
+}
+
+func testCreditFile() cadeft.File {
+	date := cadeft.Ptr(time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC))
+	later := cadeft.Ptr(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC))
+	return cadeft.NewFile(testHeader(), []cadeft.Transaction{
+		cadeft.Ptr(cadeft.NewCredit("450", 12345, date, "000112345", "123456789", "0001123450000000000001", "ACME", "JANE DOE", "ACME PAYROLL INC", "000354321", "987654", cadeft.WithCrossRefNo("INV-1001"), cadeft.WithSundryInfo("MARCH PAY"))),
+		cadeft.Ptr(cadeft.NewCredit("450", 500, date, "000298765", "55555", "0001123450000000000002", "ACME", "JOHN ROE", "ACME PAYROLL INC", "000354321", "987654")),
+		cadeft.Ptr(cadeft.NewCredit("200", 100000, later, "000112345", "42", "", "ACME", "SAM POE", "ACME PAYROLL INC", "000354321", "987654", cadeft.WithUserID("BATCH7"))),
+		cadeft.Ptr(cadeft.NewDebit("450", 100, date, "000112345", "42", "", "ACME", "PAYOR", "ACME PAYROLL INC", "000354321", "987654")),
+	})
+}
+
+func TestFileToPain001(t *testing.T) {
+	r := require.New(t)
+	out, issues, err := FileToPain001(testCreditFile())
+	r.NoError(err)
+	xml := string(out)
+
+	r.Contains(xml, `<Document xmlns="`+Pain001Namespace+`">`)
+	r.Contains(xml, "<MsgId>0000012345-0007</MsgId>")
+	r.Contains(xml, "<CreDtTm>2026-03-02T00:00:00</CreDtTm>")
+	r.Contains(xml, "<NbOfTxs>3</NbOfTxs>")
+	r.Contains(xml, "<CtrlSum>1128.45</CtrlSum>")
+	r.Equal(2, strings.Count(xml, "<PmtInf>"))
+	r.Contains(xml, "<Dt>2026-03-04</Dt>")
+	r.Contains(xml, "<Dt>2026-03-05</Dt>")
+	r.Contains(xml, `<InstdAmt Ccy="CAD">123.45</InstdAmt>`)
+	r.Contains(xml, "<InstrId>0001123450000000000001</InstrId>")
+	r.Contains(xml, "<EndToEndId>INV-1001</EndToEndId>")
+	r.Contains(xml, "<EndToEndId>0001123450000000000002</EndToEndId>")
+	r.Contains(xml, "<EndToEndId>NOTPROVIDED</EndToEndId>")
+	r.Contains(xml, "<Cd>CACPA</Cd>")
+	r.Contains(xml, "<MmbId>000354321</MmbId>")
+	r.Contains(xml, "<Ustrd>MARCH PAY</Ustrd>")
+
+	r.Equal([]Issue{
+		{Index: 2, Field: "user_id", Value: "BATCH7", Reason: "no ISO 20022 counterpart, dropped"},
+		{Index: 3, Field: "type", Value: "D", Reason: "only credits are converted to pain.001"},
+	}, issues)
+
+	_, _, err = FileToPain001(cadeft.File{})
+	r.Error(err)
+}
+
+func TestPain001RoundTrip(t *testing.T) {
+	r := require.New(t)
+	original := testCreditFile()
+	out, _, err := FileToPain001(original)
+	r.NoError(err)
+
+	file, issues, err := Pain001ToFile(out, testHeader())
+	r.NoError(err)
+	r.Empty(issues)
+	r.Len(file.Txns, 3)
+	r.NoError(file.Validate())
+
+	for i, txn := range file.Txns {
+		got, want := txn.(*cadeft.Credit), original.Txns[i].(*cadeft.Credit)
+		r.Equal(want.TxnType, got.TxnType)
+		r.Equal(want.Amount, got.Amount)
+		r.Equal(want.DateFundsAvailable, got.DateFundsAvailable)
+		r.Equal(want.InstitutionID, got.InstitutionID)
+		r.Equal(want.PayeeAccountNo, got.PayeeAccountNo)
+		r.Equal(want.PayeeName, got.PayeeName)
+		r.Equal(want.ItemTraceNo, got.ItemTraceNo)
+		r.Equal(want.CrossRefNo, got.CrossRefNo)
+		r.Equal(want.SundryInfo, got.SundryInfo)
+		r.Equal(want.OriginatorShortName, got.OriginatorShortName)
+		r.Equal(want.OriginatorLongName, got.OriginatorLongName)
+		r.Equal(want.ReturnInstitutionID, got.ReturnInstitutionID)
+		r.Equal(want.ReturnAccountNo, got.ReturnAccountNo)
+	}
+}
+
+const foreignPain001 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.09">
+  <CstmrCdtTrfInitn>
+    <GrpHdr>
+      <MsgId>MSG-1</MsgId>
+      <CreDtTm>2026-03-02T10:00:00</CreDtTm>
+      <NbOfTxs>1</NbOfTxs>
+      <InitgPty><Id><OrgId><Othr><Id>OTHERORG</Id></Othr></OrgId></Id></InitgPty>
+    </GrpHdr>
+    <PmtInf>
+      <PmtInfId>PMT-1</PmtInfId>
+      <PmtMtd>TRF</PmtMtd>
+      <ReqdExctnDt><DtTm>2026-03-04T09:30:00</DtTm></ReqdExctnDt>
+      <Dbtr><Nm>A VERY LONG ORIGINATOR NAME THAT DOES NOT FIT</Nm></Dbtr>
+      <DbtrAcct><Id><IBAN>DE89370400440532013000</IBAN></Id></DbtrAcct>
+      <DbtrAgt><FinInstnId><BICFI>COBADEFFXXX</BICFI></FinInstnId></DbtrAgt>
+      <CdtTrfTxInf>
+        <PmtId><InstrId>ABC</InstrId><EndToEndId>END-TO-END-REFERENCE-42</EndToEndId></PmtId>
+        <PmtTpInf><CtgyPurp><Prtry>SALA</Prtry></CtgyPurp></PmtTpInf>
+        <Amt><InstdAmt Ccy="CAD">10.5</InstdAmt></Amt>
+        <CdtrAgt><FinInstnId><ClrSysMmbId><MmbId>000112345</MmbId></ClrSysMmbId></FinInstnId></CdtrAgt>
+        <Cdtr><Nm>JANE DOE</Nm></Cdtr>
+        <CdtrAcct><Id><Othr><Id>123456789</Id></Othr></Id></CdtrAcct>
+      </CdtTrfTxInf>
+    </PmtInf>
+  </CstmrCdtTrfInitn>
+</Document>`
+
+func TestPain001ToFile(t *testing.T) {
+	t.Run("fields that do not map are reported", func(t *testing.T) {
+		r := require.New(t)
+		file, issues, err := Pain001ToFile([]byte(foreignPain001), testHeader())
+		r.NoError(err)
+		r.Len(file.Txns, 1)
+		credit := file.Txns[0].(*cadeft.Credit)
+		r.Equal(int64(1050), credit.Amount)
+		r.Equal(DefaultCreditTxnType, credit.TxnType)
+		r.Equal("2026-03-04", credit.DateFundsAvailable.Format(time.DateOnly))
+		r.Equal("END-TO-END-REFERENC", credit.CrossRefNo)
+		r.Empty(credit.ItemTraceNo)
+		r.Equal("A VERY LONG ORI", credit.OriginatorShortName)
+		r.Equal("A VERY LONG ORIGINATOR NAME TH", credit.OriginatorLongName)
+
+		var fields []string
+		for _, is := range issues {
+			fields = append(fields, is.Field)
+		}
+		r.Equal([]string{"originator_id", "item_trace_no", "cross_ref_no", "txn_type", "short_name", "long_name", "return_institution_id", "return_account_no"}, fields)
+	})
+
+	t.Run("invalid messages", func(t *testing.T) {
+		tests := []struct {
+			name, from, to string
+		}{
+			{name: "other currency", from: `Ccy="CAD"`, to: `Ccy="USD"`},
+			{name: "fraction of a cent", from: ">10.5<", to: ">10.505<"},
+			{name: "not an amount", from: ">10.5<", to: ">ten<"},
+			{name: "missing date", from: "<DtTm>2026-03-04T09:30:00</DtTm>", to: ""},
+			{name: "not xml", from: "<Document", to: "Document"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, _, err := Pain001ToFile([]byte(strings.Replace(foreignPain001, tt.from, tt.to, 1)), testHeader())
+				require.Error(t, err)
+			})
+		}
+		_, _, err := Pain001ToFile([]byte(foreignPain001), nil)
+		require.Error(t, err)
+	})
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in    string
+		cents int64
+		err   bool
+	}{
+		{in: "0", cents: 0},
+		{in: "12", cents: 1200},
+		{in: "12.3", cents: 1230},
+		{in: "12.34", cents: 1234},
+		{in: "12.3400", cents: 1234},
+		{in: "12.345", err: true},
+		{in: "-1.00", err: true},
+		{in: "1,000.00", err: true},
+		{in: "", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			cents, err := parseAmount(tt.in)
+			if tt.err {
+				require.ErrorIs(t, err, ErrInvalidAmount)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.cents, cents)
+		})
+	}
+}
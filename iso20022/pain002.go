@@ -0,0 +1,140 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/moov-io/cadeft"
+)
+
+// Pain002Namespace is the namespace of the payment status reports written
+const Pain002Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.002.001.10"
+
+// Message name identifications of the messages returned items are reported against
+const (
+	Pain001MessageName = "pain.001.001.09"
+	Pain008MessageName = "pain.008.001.08"
+)
+
+type pain002Document struct {
+	XMLName xml.Name                    `xml:"urn:iso:std:iso:20022:tech:xsd:pain.002.001.10 Document"`
+	Report  customerPaymentStatusReport `xml:"CstmrPmtStsRpt"`
+}
+
+type customerPaymentStatusReport struct {
+	GroupHeader      reportGroupHeader          `xml:"GrpHdr"`
+	OriginalGroup    originalGroupInformation   `xml:"OrgnlGrpInfAndSts"`
+	OriginalPayments []originalPaymentAndStatus `xml:"OrgnlPmtInfAndSts"`
+}
+
+type reportGroupHeader struct {
+	MessageID    string `xml:"MsgId"`
+	CreationDate string `xml:"CreDtTm"`
+}
+
+type originalGroupInformation struct {
+	OriginalMessageID   string `xml:"OrgnlMsgId"`
+	OriginalMessageName string `xml:"OrgnlMsgNmId"`
+}
+
+type originalPaymentAndStatus struct {
+	OriginalPaymentInfoID string              `xml:"OrgnlPmtInfId"`
+	Transactions          []transactionStatus `xml:"TxInfAndSts"`
+}
+
+type transactionStatus struct {
+	OriginalInstructionID string                        `xml:"OrgnlInstrId,omitempty"`
+	OriginalEndToEndID    string                        `xml:"OrgnlEndToEndId"`
+	Status                string                        `xml:"TxSts"`
+	StatusReason          *statusReasonInformation      `xml:"StsRsnInf,omitempty"`
+	OriginalTxn           *originalTransactionReference `xml:"OrgnlTxRef"`
+}
+
+type statusReasonInformation struct {
+	Reason         *reason  `xml:"Rsn,omitempty"`
+	AdditionalInfo []string `xml:"AddtlInf,omitempty"`
+}
+
+type originalTransactionReference struct {
+	Amount          instructedAmount           `xml:"Amt"`
+	CollectionDate  string                     `xml:"ReqdColltnDt,omitempty"`
+	ExecutionDate   *dateAndDateTime           `xml:"ReqdExctnDt,omitempty"`
+	Mandate         *mandateRelatedInformation `xml:"MndtRltdInf,omitempty"`
+	Debtor          *partyChoice               `xml:"Dbtr"`
+	DebtorAccount   *cashAccount               `xml:"DbtrAcct"`
+	DebtorAgent     *agent                     `xml:"DbtrAgt"`
+	CreditorAgent   *agent                     `xml:"CdtrAgt"`
+	Creditor        *partyChoice               `xml:"Cdtr"`
+	CreditorAccount *cashAccount               `xml:"CdtrAcct"`
+}
+
+// FileToPain002 reports the returned items of a returns file as rejected transactions of the original message, a
+// pain.001 for returned credits (I records) or a pain.008 for returned debits (J records). Each transaction links back to
+// the original item through the end to end ID FileToPain001 or FileToPain008 wrote and carries the ISO 20022 reason of its
+// CPA return reason. The payment information ID of the original message is not known and written as NOTPROVIDED.
+// Other records, reversals included as they are not status reports, are reported as issues.
+func FileToPain002(f cadeft.File, originalMessage string, opts ...ReportOpt) ([]byte, []Issue, error) {
+	if f.Header == nil {
+		return nil, nil, fmt.Errorf("file header is missing")
+	}
+	var returnType cadeft.RecordType
+	switch originalMessage {
+	case Pain001MessageName:
+		returnType = cadeft.ReturnCreditRecord
+	case Pain008MessageName:
+		returnType = cadeft.ReturnDebitRecord
+	default:
+		return nil, nil, fmt.Errorf("unsupported original message %q", originalMessage)
+	}
+	cfg := newReportConfig(opts)
+	var is issues
+	doc := pain002Document{Report: customerPaymentStatusReport{
+		GroupHeader: reportGroupHeader{MessageID: messageID(f.Header), CreationDate: creationDateTime(f.Header)},
+		OriginalGroup: originalGroupInformation{
+			OriginalMessageID:   cfg.originalMessageID,
+			OriginalMessageName: originalMessage,
+		},
+	}}
+	payment := originalPaymentAndStatus{OriginalPaymentInfoID: notProvided}
+	for i, t := range f.Txns {
+		item, ok := newReturnedItem(t)
+		if !ok || t.GetType() != returnType {
+			is.add(i, "type", string(t.GetType()), fmt.Sprintf("only %s records are reported against %s", returnType, originalMessage))
+			continue
+		}
+		payment.Transactions = append(payment.Transactions, newTransactionStatus(cfg, &is, i, item, f.Header.CurrencyCode))
+	}
+	if len(payment.Transactions) > 0 {
+		doc.Report.OriginalPayments = append(doc.Report.OriginalPayments, payment)
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, is, fmt.Errorf("failed to write pain.002: %w", err)
+	}
+	return append([]byte(xml.Header), out...), is, nil
+}
+
+func newTransactionStatus(cfg reportConfig, is *issues, i int, item returnedItem, currency string) transactionStatus {
+	b := item.txn.GetBaseTxn()
+	status := transactionStatus{
+		OriginalInstructionID: item.originalInstructionID(),
+		OriginalEndToEndID:    item.originalEndToEndID(),
+		Status:                "RJCT",
+		OriginalTxn:           &originalTransactionReference{Amount: instructedAmount{Instructed: amount{Currency: currency, Value: formatAmount(b.Amount)}}},
+	}
+	if r, info := cfg.returnReason(is, i, b); r != nil {
+		status.StatusReason = &statusReasonInformation{Reason: r, AdditionalInfo: info}
+	}
+	ref := status.OriginalTxn
+	if item.credit {
+		ref.ExecutionDate = dateOnly(item.txn.GetDate())
+	} else {
+		ref.CollectionDate = formatDate(item.txn.GetDate())
+		if mandateID := item.mandateID(); mandateID != "" {
+			ref.Mandate = &mandateRelatedInformation{MandateID: mandateID}
+		}
+	}
+	ref.Debtor, ref.Creditor, ref.DebtorAccount, ref.CreditorAccount, ref.DebtorAgent, ref.CreditorAgent = item.parties()
+	return status
+}
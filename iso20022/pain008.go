@@ -0,0 +1,257 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/moov-io/cadeft"
+)
+
+// Pain008Namespace is the namespace of the customer direct debit initiation messages read and written
+const Pain008Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.008.001.08"
+
+// DefaultDebitTxnType is the CPA transaction type of imported debits that do not carry one as proprietary category purpose
+const DefaultDebitTxnType cadeft.TransactionType = "450"
+
+// CreditorSchemeName is the proprietary scheme name of creditor identifiers holding the CPA originator ID
+const CreditorSchemeName = "CPA"
+
+type pain008Document struct {
+	XMLName    xml.Name                `xml:"urn:iso:std:iso:20022:tech:xsd:pain.008.001.08 Document"`
+	Initiation customerDirectDebitInit `xml:"CstmrDrctDbtInitn"`
+}
+
+type customerDirectDebitInit struct {
+	GroupHeader groupHeader            `xml:"GrpHdr"`
+	Payments    []debitPaymentInstruct `xml:"PmtInf"`
+}
+
+type debitPaymentInstruct struct {
+	PaymentInfoID    string               `xml:"PmtInfId"`
+	PaymentMethod    string               `xml:"PmtMtd"`
+	NumberOfTxns     int                  `xml:"NbOfTxs,omitempty"`
+	ControlSum       string               `xml:"CtrlSum,omitempty"`
+	CollectionDate   string               `xml:"ReqdColltnDt"`
+	Creditor         partyIdentification  `xml:"Cdtr"`
+	CreditorAccount  *cashAccount         `xml:"CdtrAcct"`
+	CreditorAgent    *agent               `xml:"CdtrAgt"`
+	CreditorSchemeID *partyIdentification `xml:"CdtrSchmeId,omitempty"`
+	DirectDebit      []directDebitTxnInf  `xml:"DrctDbtTxInf"`
+}
+
+type directDebitTxnInf struct {
+	PaymentID        paymentID               `xml:"PmtId"`
+	PaymentType      *paymentTypeInformation `xml:"PmtTpInf,omitempty"`
+	Amount           amount                  `xml:"InstdAmt"`
+	DirectDebitTxn   *directDebitTransaction `xml:"DrctDbtTx,omitempty"`
+	UltimateCreditor *partyIdentification    `xml:"UltmtCdtr,omitempty"`
+	DebtorAgent      *agent                  `xml:"DbtrAgt"`
+	Debtor           partyIdentification     `xml:"Dbtr"`
+	DebtorAccount    *cashAccount            `xml:"DbtrAcct"`
+	Remittance       *remittanceInformation  `xml:"RmtInf,omitempty"`
+}
+
+type directDebitTransaction struct {
+	Mandate          *mandateRelatedInformation `xml:"MndtRltdInf,omitempty"`
+	CreditorSchemeID *partyIdentification       `xml:"CdtrSchmeId,omitempty"`
+}
+
+type mandateRelatedInformation struct {
+	MandateID string `xml:"MndtId,omitempty"`
+}
+
+// FileToPain008 converts the debits of f to a pain.008.001.08 message. Debits sharing a due date, originator long name and return account
+// are grouped in one payment information block whose creditor scheme identification is the originator ID of the header.
+// The cross reference number is written as mandate ID and the item trace number as instruction and end to end ID.
+// Records other than debits and fields without a pain.008 counterpart are reported as issues.
+func FileToPain008(f cadeft.File) ([]byte, []Issue, error) {
+	if f.Header == nil {
+		return nil, nil, fmt.Errorf("file header is missing")
+	}
+	var is issues
+	doc := pain008Document{}
+	msgID := messageID(f.Header)
+	index := map[string]int{}
+	var sums []int64
+	for i, t := range f.Txns {
+		debit, ok := t.(*cadeft.Debit)
+		if !ok {
+			is.add(i, "type", string(t.GetType()), "only debits are converted to pain.008")
+			continue
+		}
+		reportUnmapped(&is, i, debit.BaseTxn)
+		key := strings.Join([]string{formatDate(debit.DueDate), debit.OriginatorLongName, debit.ReturnInstitutionID, debit.ReturnAccountNo}, "/")
+		p, ok := index[key]
+		if !ok {
+			p = len(doc.Initiation.Payments)
+			index[key] = p
+			sums = append(sums, 0)
+			doc.Initiation.Payments = append(doc.Initiation.Payments, debitPaymentInstruct{
+				PaymentInfoID:    fmt.Sprintf("%s-%d", msgID, p+1),
+				PaymentMethod:    "DD",
+				CollectionDate:   formatDate(debit.DueDate),
+				Creditor:         partyIdentification{Name: strings.TrimSpace(debit.OriginatorLongName)},
+				CreditorAccount:  newAccount(debit.ReturnAccountNo),
+				CreditorAgent:    cadeft.Ptr(newAgent(debit.ReturnInstitutionID)),
+				CreditorSchemeID: newCreditorSchemeID(f.Header.OriginatorID),
+			})
+		}
+		payment := &doc.Initiation.Payments[p]
+		payment.DirectDebit = append(payment.DirectDebit, newDirectDebit(debit, f.Header.CurrencyCode))
+		sums[p] += debit.Amount
+	}
+
+	var total int64
+	for p := range doc.Initiation.Payments {
+		payment := &doc.Initiation.Payments[p]
+		payment.NumberOfTxns = len(payment.DirectDebit)
+		payment.ControlSum = formatAmount(sums[p])
+		doc.Initiation.GroupHeader.NumberOfTxns += payment.NumberOfTxns
+		total += sums[p]
+	}
+	doc.Initiation.GroupHeader.MessageID = msgID
+	doc.Initiation.GroupHeader.CreationDate = creationDateTime(f.Header)
+	doc.Initiation.GroupHeader.ControlSum = formatAmount(total)
+	doc.Initiation.GroupHeader.InitiatingParty = newOrganisation("", f.Header.OriginatorID)
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, is, fmt.Errorf("failed to write pain.008: %w", err)
+	}
+	return append([]byte(xml.Header), out...), is, nil
+}
+
+func newDirectDebit(d *cadeft.Debit, currency string) directDebitTxnInf {
+	traceNo := itemTraceNo(d.ItemTraceNo)
+	txn := directDebitTxnInf{
+		PaymentID:     paymentID{InstructionID: traceNo, EndToEndID: traceNo},
+		PaymentType:   newPaymentType(d.TxnType),
+		Amount:        amount{Currency: currency, Value: formatAmount(d.Amount)},
+		DebtorAgent:   cadeft.Ptr(newAgent(d.InstitutionID)),
+		Debtor:        partyIdentification{Name: strings.TrimSpace(d.PayorName)},
+		DebtorAccount: newAccount(d.PayorAccountNo),
+		Remittance:    newRemittance(d.SundryInfo),
+	}
+	if txn.PaymentID.EndToEndID == "" {
+		txn.PaymentID.EndToEndID = notProvided
+	}
+	if mandateID := strings.TrimSpace(d.CrossRefNo); mandateID != "" {
+		txn.DirectDebitTxn = &directDebitTransaction{Mandate: &mandateRelatedInformation{MandateID: mandateID}}
+	}
+	if name := strings.TrimSpace(d.OriginatorShortName); name != "" {
+		txn.UltimateCreditor = &partyIdentification{Name: name}
+	}
+	return txn
+}
+
+func newCreditorSchemeID(originatorID string) *partyIdentification {
+	return &partyIdentification{ID: &partyID{OrgID: &organisationID{Other: []genericID{{
+		ID:         strings.TrimSpace(originatorID),
+		SchemeName: &schemeName{Proprietary: CreditorSchemeName},
+	}}}}}
+}
+
+// Pain008ToFile converts a pain.008.001.08 message to a file of debits under header, which provides the originator ID,
+// file creation number and currency a pain.008 message does not carry. The number of transactions and control sums of the
+// message must match its transactions and its amounts must be in the currency of the header. Creditor identifiers other than
+// the originator ID of the header, values too long for their CPA-005 field and agents and accounts that cannot be expressed
+// in CPA-005 are reported as issues. The resulting file is validated, validation errors are returned along with the file.
+func Pain008ToFile(data []byte, header *cadeft.FileHeader) (cadeft.File, []Issue, error) {
+	if header == nil {
+		return cadeft.File{}, nil, fmt.Errorf("file header is missing")
+	}
+	var doc pain008Document
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return cadeft.File{}, nil, fmt.Errorf("failed to read pain.008: %w", err)
+	}
+	var is issues
+	h := *header
+	file := cadeft.NewFile(&h, nil)
+	originatorID := strings.TrimSpace(header.OriginatorID)
+	if id := doc.Initiation.GroupHeader.InitiatingParty.organisationID(); id != "" && id != originatorID {
+		is.add(-1, "originator_id", id, "initiating party differs from the originator ID of the header, using "+header.OriginatorID)
+	}
+	var total int64
+	for _, payment := range doc.Initiation.Payments {
+		date, err := parseDate(dateAndDateTime{Date: payment.CollectionDate})
+		if err != nil {
+			return cadeft.File{}, is, fmt.Errorf("payment %s: requested collection date: %w", payment.PaymentInfoID, err)
+		}
+		var sum int64
+		for _, txn := range payment.DirectDebit {
+			i := len(file.Txns)
+			cents, err := txnAmount(i, txn.Amount, header.CurrencyCode)
+			if err != nil {
+				return cadeft.File{}, is, err
+			}
+			sum += cents
+			schemeID := payment.CreditorSchemeID
+			if txn.DirectDebitTxn != nil && txn.DirectDebitTxn.CreditorSchemeID != nil {
+				schemeID = txn.DirectDebitTxn.CreditorSchemeID
+			}
+			if id := schemeID.organisationID(); id != "" && id != originatorID {
+				is.add(i, "originator_id", id, "creditor scheme identification differs from the originator ID of the header, using "+header.OriginatorID)
+			}
+			itemTraceNo, _ := is.paymentIDs(i, paymentID{InstructionID: txn.PaymentID.InstructionID})
+			if e2e := strings.TrimSpace(txn.PaymentID.EndToEndID); e2e != notProvided && e2e != strings.TrimSpace(txn.PaymentID.InstructionID) {
+				is.add(i, "end_to_end_id", e2e, "cross reference number holds the mandate ID, dropped")
+			}
+			mandateID := ""
+			if txn.DirectDebitTxn != nil && txn.DirectDebitTxn.Mandate != nil {
+				mandateID = txn.DirectDebitTxn.Mandate.MandateID
+			}
+			shortName := payment.Creditor.name()
+			if txn.UltimateCreditor != nil {
+				shortName = txn.UltimateCreditor.name()
+			}
+			debit := cadeft.NewDebit(
+				is.txnType(i, txn.PaymentType, DefaultDebitTxnType),
+				cents,
+				date,
+				is.institutionID(i, "institution_id", txn.DebtorAgent),
+				is.accountNo(i, "payor_account_no", txn.DebtorAccount, 12),
+				itemTraceNo,
+				is.fit(i, "short_name", shortName, 15),
+				is.fit(i, "payor_name", txn.Debtor.Name, 30),
+				is.fit(i, "long_name", payment.Creditor.Name, 30),
+				is.institutionID(i, "return_institution_id", payment.CreditorAgent),
+				is.accountNo(i, "return_account_no", payment.CreditorAccount, 12),
+				cadeft.WithCrossRefNo(is.fit(i, "cross_ref_no", mandateID, 19)),
+				cadeft.WithSundryInfo(is.fit(i, "sundry_info", txn.Remittance.text(), 15)),
+			)
+			file.Txns = append(file.Txns, &debit)
+		}
+		if err := checkTotals("payment "+payment.PaymentInfoID, payment.NumberOfTxns, payment.ControlSum, len(payment.DirectDebit), sum); err != nil {
+			return cadeft.File{}, is, err
+		}
+		total += sum
+	}
+	grpHdr := doc.Initiation.GroupHeader
+	if err := checkTotals("group header", grpHdr.NumberOfTxns, grpHdr.ControlSum, len(file.Txns), total); err != nil {
+		return cadeft.File{}, is, err
+	}
+	if err := file.Validate(); err != nil {
+		return file, is, fmt.Errorf("converted file is invalid: %w", err)
+	}
+	return file, is, nil
+}
+
+// checkTotals compares the optional number of transactions and control sum of a message block to what it holds.
+// A block leaving out its number of transactions reads as zero, which is not checked.
+func checkTotals(block string, numberOfTxns int, controlSum string, count int, sum int64) error {
+	if numberOfTxns != 0 && numberOfTxns != count {
+		return fmt.Errorf("%s: number of transactions %d does not match the %d transactions", block, numberOfTxns, count)
+	}
+	if controlSum == "" {
+		return nil
+	}
+	cents, err := parseAmount(controlSum)
+	if err != nil {
+		return fmt.Errorf("%s: control sum: %w", block, err)
+	}
+	if cents != sum {
+		return fmt.Errorf("%s: control sum %s does not match the total amount %s", block, controlSum, formatAmount(sum))
+	}
+	return nil
+}
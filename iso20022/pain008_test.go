@@ -0,0 +1,182 @@
+package iso20022
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moov-io/cadeft"
+	"github.com/stretchr/testify/require"
+)
+
+func testDebitFile() cadeft.File {
+	date := cadeft.Ptr(time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC))
+	later := cadeft.Ptr(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC))
+	return cadeft.NewFile(testHeader(), []cadeft.Transaction{
+		cadeft.Ptr(cadeft.NewDebit("450", 2599, date, "000112345", "123456789", "0001123450000000000001", "ACME GYM", "JANE DOE", "ACME FITNESS INC", "000354321", "987654", cadeft.WithCrossRefNo("PAD-2026-0001"), cadeft.WithSundryInfo("MARCH DUES"))),
+		cadeft.Ptr(cadeft.NewDebit("450", 2599, date, "000298765", "55555", "0001123450000000000002", "ACME GYM", "JOHN ROE", "ACME FITNESS INC", "000354321", "987654", cadeft.WithCrossRefNo("PAD-2026-0002"))),
+		cadeft.Ptr(cadeft.NewDebit("430", 10000, later, "000112345", "42", "", "ACME GYM", "SAM POE", "ACME FITNESS INC", "000354321", "987654", cadeft.WithSettlementCode("01"))),
+		cadeft.Ptr(cadeft.NewCredit("450", 100, date, "000112345", "42", "", "ACME GYM", "PAYEE", "ACME FITNESS INC", "000354321", "987654")),
+	})
+}
+
+func TestFileToPain008(t *testing.T) {
+	r := require.New(t)
+	out, issues, err := FileToPain008(testDebitFile())
+	r.NoError(err)
+	xml := string(out)
+
+	r.Contains(xml, `<Document xmlns="`+Pain008Namespace+`">`)
+	r.Contains(xml, "<MsgId>0000012345-0007</MsgId>")
+	r.Contains(xml, "<NbOfTxs>3</NbOfTxs>")
+	r.Contains(xml, "<CtrlSum>151.98</CtrlSum>")
+	r.Contains(xml, "<CtrlSum>51.98</CtrlSum>")
+	r.Contains(xml, "<CtrlSum>100.00</CtrlSum>")
+	r.Equal(2, strings.Count(xml, "<PmtInf>"))
+	r.Contains(xml, "<PmtMtd>DD</PmtMtd>")
+	r.Contains(xml, "<ReqdColltnDt>2026-03-04</ReqdColltnDt>")
+	r.Contains(xml, `<InstdAmt Ccy="CAD">25.99</InstdAmt>`)
+	r.Contains(xml, "<MndtId>PAD-2026-0001</MndtId>")
+	r.Contains(xml, "<EndToEndId>0001123450000000000002</EndToEndId>")
+	r.Contains(xml, "<EndToEndId>NOTPROVIDED</EndToEndId>")
+	r.Contains(xml, "<Prtry>"+CreditorSchemeName+"</Prtry>")
+	r.Contains(xml, "<Nm>ACME FITNESS INC</Nm>")
+
+	r.Equal([]Issue{
+		{Index: 2, Field: "settlement_code", Value: "01", Reason: "no ISO 20022 counterpart, dropped"},
+		{Index: 3, Field: "type", Value: "C", Reason: "only debits are converted to pain.008"},
+	}, issues)
+
+	_, _, err = FileToPain008(cadeft.File{})
+	r.Error(err)
+}
+
+func TestPain008RoundTrip(t *testing.T) {
+	r := require.New(t)
+	original := testDebitFile()
+	out, _, err := FileToPain008(original)
+	r.NoError(err)
+
+	file, issues, err := Pain008ToFile(out, testHeader())
+	r.NoError(err)
+	r.Empty(issues)
+	r.Len(file.Txns, 3)
+
+	for i, txn := range file.Txns {
+		got, want := txn.(*cadeft.Debit), original.Txns[i].(*cadeft.Debit)
+		r.Equal(want.TxnType, got.TxnType)
+		r.Equal(want.Amount, got.Amount)
+		r.Equal(want.DueDate, got.DueDate)
+		r.Equal(want.InstitutionID, got.InstitutionID)
+		r.Equal(want.PayorAccountNo, got.PayorAccountNo)
+		r.Equal(want.PayorName, got.PayorName)
+		r.Equal(want.ItemTraceNo, got.ItemTraceNo)
+		r.Equal(want.CrossRefNo, got.CrossRefNo)
+		r.Equal(want.SundryInfo, got.SundryInfo)
+		r.Equal(want.OriginatorShortName, got.OriginatorShortName)
+		r.Equal(want.OriginatorLongName, got.OriginatorLongName)
+		r.Equal(want.ReturnInstitutionID, got.ReturnInstitutionID)
+		r.Equal(want.ReturnAccountNo, got.ReturnAccountNo)
+	}
+}
+
+const billingPain008 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.008.001.08">
+  <CstmrDrctDbtInitn>
+    <GrpHdr>
+      <MsgId>BILL-77</MsgId>
+      <CreDtTm>2026-03-02T10:00:00</CreDtTm>
+      <NbOfTxs>2</NbOfTxs>
+      <CtrlSum>75.00</CtrlSum>
+      <InitgPty><Nm>BILLING</Nm></InitgPty>
+    </GrpHdr>
+    <PmtInf>
+      <PmtInfId>BILL-77-1</PmtInfId>
+      <PmtMtd>DD</PmtMtd>
+      <NbOfTxs>2</NbOfTxs>
+      <CtrlSum>75.00</CtrlSum>
+      <ReqdColltnDt>2026-03-04</ReqdColltnDt>
+      <Cdtr><Nm>ACME FITNESS INC</Nm></Cdtr>
+      <CdtrAcct><Id><Othr><Id>987654</Id></Othr></Id></CdtrAcct>
+      <CdtrAgt><FinInstnId><ClrSysMmbId><ClrSysId><Cd>CACPA</Cd></ClrSysId><MmbId>000354321</MmbId></ClrSysMmbId></FinInstnId></CdtrAgt>
+      <CdtrSchmeId><Id><OrgId><Othr><Id>9999999999</Id><SchmeNm><Prtry>CPA</Prtry></SchmeNm></Othr></OrgId></Id></CdtrSchmeId>
+      <DrctDbtTxInf>
+        <PmtId><EndToEndId>INVOICE-123</EndToEndId></PmtId>
+        <InstdAmt Ccy="CAD">50.00</InstdAmt>
+        <DrctDbtTx><MndtRltdInf><MndtId>MANDATE-REFERENCE-0000042</MndtId></MndtRltdInf></DrctDbtTx>
+        <DbtrAgt><FinInstnId><ClrSysMmbId><MmbId>000112345</MmbId></ClrSysMmbId></FinInstnId></DbtrAgt>
+        <Dbtr><Nm>JANE DOE</Nm></Dbtr>
+        <DbtrAcct><Id><Othr><Id>123456789</Id></Othr></Id></DbtrAcct>
+      </DrctDbtTxInf>
+      <DrctDbtTxInf>
+        <PmtId><InstrId>0001123450000000000009</InstrId><EndToEndId>0001123450000000000009</EndToEndId></PmtId>
+        <PmtTpInf><CtgyPurp><Prtry>430</Prtry></CtgyPurp></PmtTpInf>
+        <InstdAmt Ccy="CAD">25</InstdAmt>
+        <DrctDbtTx><MndtRltdInf><MndtId>M-7</MndtId></MndtRltdInf></DrctDbtTx>
+        <DbtrAgt><FinInstnId><ClrSysMmbId><MmbId>000298765</MmbId></ClrSysMmbId></FinInstnId></DbtrAgt>
+        <Dbtr><Nm>JOHN ROE</Nm></Dbtr>
+        <DbtrAcct><Id><Othr><Id>55555</Id></Othr></Id></DbtrAcct>
+      </DrctDbtTxInf>
+    </PmtInf>
+  </CstmrDrctDbtInitn>
+</Document>`
+
+func TestPain008ToFile(t *testing.T) {
+	t.Run("mandates, totals and issues", func(t *testing.T) {
+		r := require.New(t)
+		file, issues, err := Pain008ToFile([]byte(billingPain008), testHeader())
+		r.NoError(err)
+		r.Len(file.Txns, 2)
+
+		first, second := file.Txns[0].(*cadeft.Debit), file.Txns[1].(*cadeft.Debit)
+		r.Equal(int64(5000), first.Amount)
+		r.Equal("MANDATE-REFERENCE-0", first.CrossRefNo)
+		r.Equal(DefaultDebitTxnType, first.TxnType)
+		r.Equal("ACME FITNESS IN", first.OriginatorShortName)
+		r.Equal("2026-03-04", first.DueDate.Format(time.DateOnly))
+		r.Equal(cadeft.TransactionType("430"), second.TxnType)
+		r.Equal("M-7", second.CrossRefNo)
+		r.Equal("0001123450000000000009", second.ItemTraceNo)
+		r.Equal(int64(2500), second.Amount)
+
+		r.Equal([]Issue{
+			{Index: 0, Field: "originator_id", Value: "9999999999", Reason: "creditor scheme identification differs from the originator ID of the header, using 0000012345"},
+			{Index: 0, Field: "end_to_end_id", Value: "INVOICE-123", Reason: "cross reference number holds the mandate ID, dropped"},
+			{Index: 0, Field: "short_name", Value: "ACME FITNESS INC", Reason: "truncated to 15 characters"},
+			{Index: 0, Field: "cross_ref_no", Value: "MANDATE-REFERENCE-0000042", Reason: "truncated to 19 characters"},
+			{Index: 1, Field: "originator_id", Value: "9999999999", Reason: "creditor scheme identification differs from the originator ID of the header, using 0000012345"},
+			{Index: 1, Field: "short_name", Value: "ACME FITNESS INC", Reason: "truncated to 15 characters"},
+		}, issues)
+	})
+
+	t.Run("invalid messages", func(t *testing.T) {
+		tests := []struct {
+			name, from, to string
+		}{
+			{name: "group control sum", from: "<CtrlSum>75.00</CtrlSum>", to: "<CtrlSum>75.01</CtrlSum>"},
+			{name: "payment control sum", from: "<NbOfTxs>2</NbOfTxs>\n      <CtrlSum>75.00</CtrlSum>\n      <ReqdColltnDt>", to: "<NbOfTxs>2</NbOfTxs>\n      <CtrlSum>70.00</CtrlSum>\n      <ReqdColltnDt>"},
+			{name: "group number of transactions", from: "<NbOfTxs>2</NbOfTxs>", to: "<NbOfTxs>3</NbOfTxs>"},
+			{name: "other currency", from: `Ccy="CAD">25`, to: `Ccy="USD">25`},
+			{name: "missing collection date", from: "<ReqdColltnDt>2026-03-04</ReqdColltnDt>", to: ""},
+			{name: "pain.001 namespace", from: Pain008Namespace, to: Pain001Namespace},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				in := strings.Replace(billingPain008, tt.from, tt.to, 1)
+				require.NotEqual(t, billingPain008, in)
+				_, _, err := Pain008ToFile([]byte(in), testHeader())
+				require.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("resulting file is validated", func(t *testing.T) {
+		r := require.New(t)
+		in := strings.Replace(billingPain008, "<Dbtr><Nm>JANE DOE</Nm></Dbtr>", "<Dbtr></Dbtr>", 1)
+		file, _, err := Pain008ToFile([]byte(in), testHeader())
+		r.Error(err)
+		var validationErrs cadeft.ValidationErrors
+		r.ErrorAs(err, &validationErrs)
+		r.Len(file.Txns, 2)
+	})
+}
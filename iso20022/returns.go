@@ -0,0 +1,188 @@
+package iso20022
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/moov-io/cadeft"
+)
+
+// ReturnReason is a CPA return reason code, carried in the invalid data element ID of returned items as read by cadeft.BaseTxn.ReturnReasonCode,
+// and its ISO 20022 counterpart
+type ReturnReason struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	// ISOCode is an ExternalStatusReason1Code, also used as ExternalReturnReason1Code in notifications
+	ISOCode string `json:"iso_code"`
+}
+
+var returnReasons = map[string]ReturnReason{
+	"900": {Code: "900", Description: "Edit reject", ISOCode: "FF01"},
+	"901": {Code: "901", Description: "NSF (debit only)", ISOCode: "AM04"},
+	"902": {Code: "902", Description: "Account not found", ISOCode: "AC01"},
+	"903": {Code: "903", Description: "Payment stopped/recalled", ISOCode: "MS02"},
+	"904": {Code: "904", Description: "Post/stale dated", ISOCode: "DT01"},
+	"905": {Code: "905", Description: "Account closed", ISOCode: "AC04"},
+	"906": {Code: "906", Description: "Account transferred", ISOCode: "AC01"},
+	"907": {Code: "907", Description: "No chequing privileges", ISOCode: "AG01"},
+	"908": {Code: "908", Description: "Funds not cleared", ISOCode: "AM04"},
+	"909": {Code: "909", Description: "Currency/account mismatch", ISOCode: "AM03"},
+	"910": {Code: "910", Description: "Payor/payee deceased", ISOCode: "MD07"},
+	"911": {Code: "911", Description: "Account frozen", ISOCode: "AC06"},
+	"912": {Code: "912", Description: "Invalid/incorrect account no.", ISOCode: "AC01"},
+	"914": {Code: "914", Description: "Incorrect payor/payee", ISOCode: "BE01"},
+	"915": {Code: "915", Description: "Refused by payor/payee", ISOCode: "MS02"},
+	"916": {Code: "916", Description: "Not in accordance with agreement - personal", ISOCode: "MD06"},
+	"917": {Code: "917", Description: "Agreement revoked", ISOCode: "MD01"},
+	"918": {Code: "918", Description: "No pre-notification", ISOCode: "MD01"},
+	"919": {Code: "919", Description: "Not in accordance with agreement - business", ISOCode: "MD06"},
+	"920": {Code: "920", Description: "No confirmation/pre-notification - business", ISOCode: "MD01"},
+	"921": {Code: "921", Description: "Customer initiated return - personal", ISOCode: "MD06"},
+	"922": {Code: "922", Description: "Customer initiated return - business", ISOCode: "MD06"},
+	"990": {Code: "990", Description: "Institution in default", ISOCode: "ED05"},
+}
+
+// LookupReturnReason returns the CPA return reason with the given code and its ISO 20022 reason code
+func LookupReturnReason(code string) (ReturnReason, bool) {
+	r, ok := returnReasons[strings.TrimSpace(code)]
+	return r, ok
+}
+
+// ReportOpt changes how returns are reported by FileToPain002 and FileToCamt054
+type ReportOpt func(*reportConfig)
+
+type reportConfig struct {
+	reasons           map[string]string
+	originalMessageID string
+}
+
+// WithReasonCode reports the CPA return reason cpaCode with the ISO 20022 reason isoCode instead of the default mapping
+func WithReasonCode(cpaCode, isoCode string) ReportOpt {
+	return func(c *reportConfig) {
+		c.reasons[cpaCode] = isoCode
+	}
+}
+
+// WithOriginalMessageID sets the message ID of the pain.001 or pain.008 message the returned items were sent in, NOTPROVIDED by default
+func WithOriginalMessageID(id string) ReportOpt {
+	return func(c *reportConfig) {
+		c.originalMessageID = id
+	}
+}
+
+func newReportConfig(opts []ReportOpt) reportConfig {
+	cfg := reportConfig{reasons: map[string]string{}, originalMessageID: notProvided}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+type reason struct {
+	Code        string `xml:"Cd,omitempty"`
+	Proprietary string `xml:"Prtry,omitempty"`
+}
+
+// partyChoice is a Party40Choice holding a party
+type partyChoice struct {
+	Party partyIdentification `xml:"Pty"`
+}
+
+// returnReason maps the CPA return reason of the returned item at index i, unknown codes are reported and written as proprietary reasons
+func (c reportConfig) returnReason(is *issues, i int, b cadeft.BaseTxn) (*reason, []string) {
+	code := b.ReturnReasonCode()
+	if code == "" {
+		is.add(i, "invalid_data_element_id", b.InvalidDataElementID, "no return reason code")
+		return nil, nil
+	}
+	r, ok := LookupReturnReason(code)
+	if isoCode, overridden := c.reasons[code]; overridden {
+		r.ISOCode = isoCode
+		ok = true
+	}
+	if !ok {
+		is.add(i, "invalid_data_element_id", code, "unknown CPA return reason, reported as proprietary")
+		return &reason{Proprietary: code}, nil
+	}
+	var info []string
+	if r.Description != "" {
+		info = []string{fmt.Sprintf("CPA %s %s", code, r.Description)}
+	}
+	return &reason{Code: r.ISOCode}, info
+}
+
+// returnedItem is an I, J, E or F record seen from the originator of the item it returns or reverses
+type returnedItem struct {
+	txn cadeft.Transaction
+	// credit is set for returned and reversed credits, the originator was the debtor of those
+	credit   bool
+	reversal bool
+	// originatorInstitutionID and originatorAccountNo are the account of the originator the item is posted back to
+	originatorInstitutionID string
+	originatorAccountNo     string
+}
+
+func newReturnedItem(t cadeft.Transaction) (returnedItem, bool) {
+	item := returnedItem{txn: t}
+	switch t.GetType() {
+	case cadeft.ReturnCreditRecord:
+		item.credit = true
+		item.originatorInstitutionID, item.originatorAccountNo = t.GetOriginalInstitutionID(), t.GetOriginalAccountNo()
+	case cadeft.ReturnDebitRecord:
+		item.originatorInstitutionID, item.originatorAccountNo = t.GetOriginalInstitutionID(), t.GetOriginalAccountNo()
+	case cadeft.CreditReverseRecord:
+		item.credit, item.reversal = true, true
+		item.originatorInstitutionID, item.originatorAccountNo = t.GetReturnInstitutionID(), t.GetReturnAccountNo()
+	case cadeft.DebitReverseRecord:
+		item.reversal = true
+		item.originatorInstitutionID, item.originatorAccountNo = t.GetReturnInstitutionID(), t.GetReturnAccountNo()
+	case cadeft.HeaderRecord, cadeft.CreditRecord, cadeft.DebitRecord, cadeft.NoticeOfChangeRecord, cadeft.NoticeOfChangeHeader, cadeft.NoticeOfChangeFooter, cadeft.FooterRecord:
+		return item, false
+	}
+	return item, true
+}
+
+// originalEndToEndID is the end to end ID the item was sent with by FileToPain001 or FileToPain008:
+// the cross reference number of credits when there is one, the original item trace number otherwise
+func (r returnedItem) originalEndToEndID() string {
+	if crossRefNo := strings.TrimSpace(r.txn.GetBaseTxn().CrossRefNo); r.credit && crossRefNo != "" {
+		return crossRefNo
+	}
+	if traceNo := r.originalInstructionID(); traceNo != "" {
+		return traceNo
+	}
+	return notProvided
+}
+
+// originalInstructionID is the original item trace number, empty when the item does not carry one
+func (r returnedItem) originalInstructionID() string {
+	return itemTraceNo(r.txn.GetOriginalItemTraceNo())
+}
+
+func (r returnedItem) mandateID() string {
+	if r.credit {
+		return ""
+	}
+	return strings.TrimSpace(r.txn.GetBaseTxn().CrossRefNo)
+}
+
+// parties returns the debtor and creditor of the original item along with their accounts and agents
+func (r returnedItem) parties() (debtor, creditor *partyChoice, debtorAccount, creditorAccount *cashAccount, debtorAgent, creditorAgent *agent) {
+	b := r.txn.GetBaseTxn()
+	originator := &partyChoice{Party: partyIdentification{Name: strings.TrimSpace(b.OriginatorLongName)}}
+	counterparty := &partyChoice{Party: partyIdentification{Name: strings.TrimSpace(r.txn.GetName())}}
+	originatorAccount, counterpartyAccount := newAccount(r.originatorAccountNo), newAccount(r.txn.GetAccountNo())
+	originatorAgent, counterpartyAgent := cadeft.Ptr(newAgent(r.originatorInstitutionID)), cadeft.Ptr(newAgent(b.InstitutionID))
+	if r.credit {
+		return originator, counterparty, originatorAccount, counterpartyAccount, originatorAgent, counterpartyAgent
+	}
+	return counterparty, originator, counterpartyAccount, originatorAccount, counterpartyAgent, originatorAgent
+}
+
+func dateOnly(t *time.Time) *dateAndDateTime {
+	if t == nil {
+		return nil
+	}
+	return &dateAndDateTime{Date: formatDate(t)}
+}
@@ -0,0 +1,181 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moov-io/cadeft"
+	"github.com/stretchr/testify/require"
+)
+
+func testReturnsFile() cadeft.File {
+	date := cadeft.Ptr(time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC))
+	header := cadeft.NewFileHeader("0000099999", 31, cadeft.Ptr(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)), 86900, "CAD")
+	return cadeft.NewFile(header, []cadeft.Transaction{
+		cadeft.Ptr(cadeft.NewCreditReturn("450", 12345, date, "000112345", "123456789", "0009999990000000000001", "ACME", "JANE DOE", "ACME PAYROLL INC", "000354321", "987654", "0001123450000000000001", cadeft.WithCrossRefNo("INV-1001"), cadeft.WithStoredTransactionType("450"), cadeft.WithInvalidDataElementID("905"))),
+		cadeft.Ptr(cadeft.NewDebitReturn("450", 2599, date, "000298765", "55555", "0009999990000000000002", "ACME GYM", "JOHN ROE", "ACME FITNESS INC", "000354321", "987654", "0001123450000000000002", cadeft.WithCrossRefNo("PAD-2026-0002"), cadeft.WithStoredTransactionType("450"), cadeft.WithInvalidDataElementID("901"))),
+		cadeft.Ptr(cadeft.NewDebitReturn("450", 100, date, "000298765", "55555", "0009999990000000000003", "ACME GYM", "JOHN ROE", "ACME FITNESS INC", "000354321", "987654", "0001123450000000000003", cadeft.WithStoredTransactionType("450"), cadeft.WithInvalidDataElementID("999"))),
+		cadeft.Ptr(cadeft.NewCreditReverse("450", 500, date, "000112345", "42", "", "ACME", "SAM POE", "ACME PAYROLL INC", "000354321", "987654", "0001123450000000000004")),
+		cadeft.Ptr(cadeft.NewCredit("450", 100, date, "000112345", "42", "", "ACME", "PAYEE", "ACME PAYROLL INC", "000354321", "987654")),
+	})
+}
+
+func TestLookupReturnReason(t *testing.T) {
+	r := require.New(t)
+	reason, ok := LookupReturnReason("901")
+	r.True(ok)
+	r.Equal("AM04", reason.ISOCode)
+	reason, ok = LookupReturnReason(" 905")
+	r.True(ok)
+	r.Equal("AC04", reason.ISOCode)
+	for code, isoCode := range map[string]string{"920": "MD01", "921": "MD06", "922": "MD06"} {
+		reason, ok = LookupReturnReason(code)
+		r.True(ok, code)
+		r.Equal(isoCode, reason.ISOCode, code)
+	}
+	_, ok = LookupReturnReason("450")
+	r.False(ok)
+}
+
+func TestReturnsFilePassesSemanticRule(t *testing.T) {
+	f := testReturnsFile()
+	require.NoError(t, f.ValidateWith(cadeft.SemanticRule))
+}
+
+func TestFileToPain002(t *testing.T) {
+	t.Run("returned credits", func(t *testing.T) {
+		r := require.New(t)
+		out, issues, err := FileToPain002(testReturnsFile(), Pain001MessageName, WithOriginalMessageID("0000012345-0007"))
+		r.NoError(err)
+
+		var doc pain002Document
+		r.NoError(xml.Unmarshal(out, &doc))
+		r.Equal("0000099999-0031", doc.Report.GroupHeader.MessageID)
+		r.Equal("0000012345-0007", doc.Report.OriginalGroup.OriginalMessageID)
+		r.Equal(Pain001MessageName, doc.Report.OriginalGroup.OriginalMessageName)
+		r.Len(doc.Report.OriginalPayments, 1)
+		r.Len(doc.Report.OriginalPayments[0].Transactions, 1)
+
+		status := doc.Report.OriginalPayments[0].Transactions[0]
+		r.Equal("INV-1001", status.OriginalEndToEndID)
+		r.Equal("0001123450000000000001", status.OriginalInstructionID)
+		r.Equal("RJCT", status.Status)
+		r.Equal("AC04", status.StatusReason.Reason.Code)
+		r.Equal([]string{"CPA 905 Account closed"}, status.StatusReason.AdditionalInfo)
+		r.Equal("123.45", status.OriginalTxn.Amount.Instructed.Value)
+		r.Equal("2026-03-04", status.OriginalTxn.ExecutionDate.Date)
+		r.Equal("ACME PAYROLL INC", status.OriginalTxn.Debtor.Party.Name)
+		r.Equal("987654", status.OriginalTxn.DebtorAccount.ID.Other.ID)
+		r.Equal("JANE DOE", status.OriginalTxn.Creditor.Party.Name)
+		r.Equal("000112345", status.OriginalTxn.CreditorAgent.FinInstnID.ClrSysMmbID.MemberID)
+
+		r.Len(issues, 4)
+		for _, is := range issues {
+			r.Equal("type", is.Field)
+		}
+	})
+
+	t.Run("returned debits", func(t *testing.T) {
+		r := require.New(t)
+		out, issues, err := FileToPain002(testReturnsFile(), Pain008MessageName, WithReasonCode("901", "AM05"))
+		r.NoError(err)
+		xmlOut := string(out)
+		r.Contains(xmlOut, `<Document xmlns="`+Pain002Namespace+`">`)
+		r.Contains(xmlOut, "<OrgnlMsgId>NOTPROVIDED</OrgnlMsgId>")
+
+		var doc pain002Document
+		r.NoError(xml.Unmarshal(out, &doc))
+		txns := doc.Report.OriginalPayments[0].Transactions
+		r.Len(txns, 2)
+		r.Equal("0001123450000000000002", txns[0].OriginalEndToEndID)
+		r.Equal("AM05", txns[0].StatusReason.Reason.Code)
+		r.Equal("PAD-2026-0002", txns[0].OriginalTxn.Mandate.MandateID)
+		r.Equal("2026-03-04", txns[0].OriginalTxn.CollectionDate)
+		r.Equal("JOHN ROE", txns[0].OriginalTxn.Debtor.Party.Name)
+		r.Equal("ACME FITNESS INC", txns[0].OriginalTxn.Creditor.Party.Name)
+		r.Equal("999", txns[1].StatusReason.Reason.Proprietary)
+
+		r.Contains(issues, Issue{Index: 2, Field: "invalid_data_element_id", Value: "999", Reason: "unknown CPA return reason, reported as proprietary"})
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, _, err := FileToPain002(testReturnsFile(), "pain.001.001.03")
+		require.Error(t, err)
+		_, _, err = FileToPain002(cadeft.File{}, Pain001MessageName)
+		require.Error(t, err)
+	})
+}
+
+func TestFileToCamt054(t *testing.T) {
+	r := require.New(t)
+	out, issues, err := FileToCamt054(testReturnsFile())
+	r.NoError(err)
+	r.Contains(string(out), `<Document xmlns="`+Camt054Namespace+`">`)
+
+	var doc camt054Document
+	r.NoError(xml.Unmarshal(out, &doc))
+	r.Len(doc.Notification.Notifications, 1)
+	notification := doc.Notification.Notifications[0]
+	r.Equal("987654", notification.Account.ID.Other.ID)
+	r.Equal("000354321", notification.Account.Servicer.FinInstnID.ClrSysMmbID.MemberID)
+	r.Len(notification.Entries, 4)
+
+	returnedCredit := notification.Entries[0]
+	r.Equal("CRDT", returnedCredit.CreditDebit)
+	r.False(returnedCredit.ReversalIndicator)
+	r.Equal("ICDT", returnedCredit.BankTxnCode.Domain.Family.Code)
+	r.Equal("2026-03-09", returnedCredit.BookingDate.Date)
+	r.Equal("2026-03-04", returnedCredit.ValueDate.Date)
+	details := returnedCredit.Details[0].Transactions[0]
+	r.Equal("INV-1001", details.References.EndToEndID)
+	r.Empty(details.References.MandateID)
+	r.Equal("AC04", details.Return.Reason.Code)
+
+	returnedDebit := notification.Entries[1]
+	r.Equal("DBIT", returnedDebit.CreditDebit)
+	r.Equal("UPDD", returnedDebit.BankTxnCode.Domain.Family.SubFamily)
+	details = returnedDebit.Details[0].Transactions[0]
+	r.Equal("0001123450000000000002", details.References.EndToEndID)
+	r.Equal("PAD-2026-0002", details.References.MandateID)
+	r.Equal("AM04", details.Return.Reason.Code)
+	r.Equal("JOHN ROE", details.RelatedParties.Debtor.Party.Name)
+
+	reversal := notification.Entries[3]
+	r.Equal("CRDT", reversal.CreditDebit)
+	r.True(reversal.ReversalIndicator)
+	r.Equal("5.00", reversal.Amount.Value)
+	r.Nil(reversal.Details[0].Transactions[0].Return)
+	r.Equal("0001123450000000000004", reversal.Details[0].Transactions[0].References.EndToEndID)
+
+	r.Equal([]Issue{
+		{Index: 2, Field: "invalid_data_element_id", Value: "999", Reason: "unknown CPA return reason, reported as proprietary"},
+		{Index: 4, Field: "type", Value: "C", Reason: "only returned and reversed items are notified"},
+	}, issues)
+}
+
+func TestReturnsOfParsedFile(t *testing.T) {
+	r := require.New(t)
+	in := testReturnsFile()
+	serialized, err := in.Create()
+	r.NoError(err)
+	parsed, err := cadeft.NewReader(strings.NewReader(serialized)).ReadFile()
+	r.NoError(err)
+
+	// parsed trace numbers are padded with zeros and filler is read back, neither may change what is reported
+	out, _, err := FileToCamt054(parsed)
+	r.NoError(err)
+	var doc camt054Document
+	r.NoError(xml.Unmarshal(out, &doc))
+	var endToEndIDs, reasons []string
+	for _, e := range doc.Notification.Notifications[0].Entries {
+		details := e.Details[0].Transactions[0]
+		endToEndIDs = append(endToEndIDs, details.References.EndToEndID)
+		if details.Return != nil {
+			reasons = append(reasons, details.Return.Reason.Code+details.Return.Reason.Proprietary)
+		}
+	}
+	r.ElementsMatch([]string{"INV-1001", "0001123450000000000002", "0001123450000000000003", "0001123450000000000004"}, endToEndIDs)
+	r.ElementsMatch([]string{"AC04", "AM04", "999"}, reasons)
+}
@@ -0,0 +1,97 @@
+package cadeft
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	itemTraceNoInstitutionLen = 9
+	itemTraceNoDataCentreLen  = 5
+	itemTraceNoSequenceLen    = 8
+	maxItemTraceNoSequence    = 99999999
+)
+
+// ItemTraceNoGenerator hands out unique 22 digit item trace numbers for the transactions of a single file.
+// A trace number is made of the 9 digit originating institution ID, the 5 digit data centre number and an 8 digit sequence.
+// ItemTraceNoGenerator is safe for concurrent use.
+type ItemTraceNoGenerator struct {
+	mu       sync.Mutex
+	prefix   string
+	sequence int64
+}
+
+type ItemTraceNoOpt func(*ItemTraceNoGenerator)
+
+// WithTraceNoStartingSequence sets the sequence of the first trace number handed out, sequences start at 1 by default.
+// Files sharing an institution and data centre should continue the sequence of the previous file to keep trace numbers unique.
+func WithTraceNoStartingSequence(seq int64) ItemTraceNoOpt {
+	return func(g *ItemTraceNoGenerator) {
+		g.sequence = seq - 1
+	}
+}
+
+// NewItemTraceNoGenerator creates an ItemTraceNoGenerator for the 9 digit institution ID of the originating institution,
+// 0 followed by its institution and transit numbers, and the destination data centre of header
+func NewItemTraceNoGenerator(institutionID string, header FileHeader, opts ...ItemTraceNoOpt) (*ItemTraceNoGenerator, error) {
+	if len(institutionID) != itemTraceNoInstitutionLen || !numericRegex.MatchString(institutionID) {
+		return nil, fmt.Errorf("institution ID %q is not %d digits", institutionID, itemTraceNoInstitutionLen)
+	}
+	if header.DestinationDataCenterNo < 0 || header.DestinationDataCenterNo > 99999 {
+		return nil, fmt.Errorf("data centre %d is not %d digits", header.DestinationDataCenterNo, itemTraceNoDataCentreLen)
+	}
+	g := &ItemTraceNoGenerator{
+		prefix: institutionID + convertNumToZeroPaddedString(header.DestinationDataCenterNo, itemTraceNoDataCentreLen),
+	}
+	for _, o := range opts {
+		o(g)
+	}
+	if g.sequence < 0 {
+		return nil, errors.New("starting sequence must be greater than 0")
+	}
+	return g, nil
+}
+
+// Next returns the next item trace number or ErrItemTraceNoExhausted once the 8 digit sequence has been used up
+func (g *ItemTraceNoGenerator) Next() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.sequence >= maxItemTraceNoSequence {
+		return "", ErrItemTraceNoExhausted
+	}
+	g.sequence++
+	return g.prefix + convertNumToZeroPaddedString(g.sequence, itemTraceNoSequenceLen), nil
+}
+
+// Fill assigns a new item trace number to every transaction that does not have one yet
+func (g *ItemTraceNoGenerator) Fill(txns Transactions) error {
+	for i, t := range txns {
+		if !isMissingItemTraceNo(t.GetBaseTxn().ItemTraceNo) {
+			continue
+		}
+		setter, ok := t.(itemTraceNoSetter)
+		if !ok {
+			return fmt.Errorf("txn %d: item trace number cannot be set on %T", i, t)
+		}
+		traceNo, err := g.Next()
+		if err != nil {
+			return newError(msgTxn, i, err)
+		}
+		setter.setItemTraceNo(traceNo)
+	}
+	return nil
+}
+
+type itemTraceNoSetter interface {
+	setItemTraceNo(string)
+}
+
+func (b *BaseTxn) setItemTraceNo(s string) {
+	b.ItemTraceNo = s
+}
+
+func isMissingItemTraceNo(s string) bool {
+	return strings.Trim(s, " 0") == ""
+}
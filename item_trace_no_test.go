@@ -0,0 +1,93 @@
+package cadeft
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemTraceNoGenerator(t *testing.T) {
+	date := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("institution then data centre", func(t *testing.T) {
+		r := require.New(t)
+		g, err := NewItemTraceNoGenerator("000100002", *NewFileHeader("0000000610", 1, &date, 61210, "CAD"), WithTraceNoStartingSequence(6))
+		r.NoError(err)
+		traceNo, err := g.Next()
+		r.NoError(err)
+		r.Equal("0001000026121000000006", traceNo)
+		traceNo, err = g.Next()
+		r.NoError(err)
+		r.Equal("0001000026121000000007", traceNo)
+	})
+
+	t.Run("institution ID", func(t *testing.T) {
+		r := require.New(t)
+		header := *NewFileHeader("ORIGINATOR", 12, &date, 610, "CAD")
+		g, err := NewItemTraceNoGenerator("000100002", header)
+		r.NoError(err)
+		traceNo, err := g.Next()
+		r.NoError(err)
+		r.Equal("0001000020061000000001", traceNo)
+
+		for _, id := range []string{"", "abc", "00010000", "0001000020"} {
+			_, err = NewItemTraceNoGenerator(id, header)
+			r.Error(err, id)
+		}
+	})
+
+	t.Run("sequence exhausted", func(t *testing.T) {
+		r := require.New(t)
+		g, err := NewItemTraceNoGenerator("000100002", *NewFileHeader("0000000610", 1, &date, 61210, "CAD"), WithTraceNoStartingSequence(maxItemTraceNoSequence))
+		r.NoError(err)
+		_, err = g.Next()
+		r.NoError(err)
+		_, err = g.Next()
+		r.ErrorIs(err, ErrItemTraceNoExhausted)
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		r := require.New(t)
+		g, err := NewItemTraceNoGenerator("000100002", *NewFileHeader("0000000610", 1, &date, 61210, "CAD"))
+		r.NoError(err)
+		var mu sync.Mutex
+		seen := make(map[string]struct{})
+		wg := sync.WaitGroup{}
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					traceNo, err := g.Next()
+					if err != nil {
+						return
+					}
+					mu.Lock()
+					seen[traceNo] = struct{}{}
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		r.Len(seen, 1000)
+	})
+}
+
+func TestCreateFileFillsItemTraceNo(t *testing.T) {
+	r := require.New(t)
+	date := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	header := NewFileHeader("0000000610", 1, &date, 61210, "CAD")
+	withTraceNo := Ptr(NewCredit("450", 1000, &date, "123456789", "12345", "1111111111111111111111", "short name", "payee name", "someone", "1231", "12345"))
+	withoutTraceNo := Ptr(NewCredit("450", 1000, &date, "123456789", "12345", "", "short name", "payee name", "someone", "1231", "12345"))
+	file := NewFile(header, Transactions{withTraceNo, withoutTraceNo})
+	g, err := NewItemTraceNoGenerator("000100002", *header)
+	r.NoError(err)
+	file.ItemTraceNoGenerator = g
+
+	_, err = file.Create()
+	r.NoError(err)
+	r.Equal("1111111111111111111111", withTraceNo.ItemTraceNo)
+	r.Equal("0001000026121000000001", withoutTraceNo.ItemTraceNo)
+}
@@ -0,0 +1,403 @@
+package cadeft
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// FieldFormat is the kind of content a field holds
+type FieldFormat string
+
+const (
+	NumericFormat FieldFormat = "numeric"
+	AlphaFormat   FieldFormat = "alphanumeric"
+	// DateFormat is a julian date written as CYYDDD
+	DateFormat   FieldFormat = "date"
+	FillerFormat FieldFormat = "filler"
+)
+
+// Padding is how a value shorter than its field is completed when written
+type Padding string
+
+const (
+	ZerosLeft   Padding = "zeros_left"
+	ZerosRight  Padding = "zeros_right"
+	BlanksRight Padding = "blanks_right"
+)
+
+// FieldLayout describes where a field sits in a record and how it is written
+type FieldLayout struct {
+	// Number is the field number in the CPA-005 spec
+	Number int `json:"number"`
+	// Name is the json name of the field, empty for filler
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description"`
+	// Start is the offset of the field in characters from the beginning of the record, it ends before Start+Length
+	Start   int         `json:"start"`
+	Length  int         `json:"length"`
+	Format  FieldFormat `json:"format"`
+	Padding Padding     `json:"padding"`
+	// Optional numeric fields may be left blank, they are read as empty when blank or filled with zeros
+	Optional bool `json:"optional,omitempty"`
+
+	// goName is the struct field holding the value, empty for filler and the fields of the record header
+	goName string
+}
+
+// End returns the offset following the last character of the field
+func (fl FieldLayout) End() int {
+	return fl.Start + fl.Length
+}
+
+// RecordLayout lists the fields of a record type in the order they are written.
+// Header and footer offsets count from the beginning of their line. Transaction offsets count from the beginning of their 240 character segment,
+// segment i of a line starts at 24+240*i after the same record type, record count, originator ID and file creation number the header starts with.
+type RecordLayout struct {
+	RecordType RecordType    `json:"record_type"`
+	Length     int           `json:"length"`
+	Fields     []FieldLayout `json:"fields"`
+}
+
+// Field returns the layout of the field with the json name
+func (rl RecordLayout) Field(name string) (FieldLayout, bool) {
+	for _, f := range rl.Fields {
+		if f.Name == name && name != "" {
+			return f, true
+		}
+	}
+	return FieldLayout{}, false
+}
+
+// FieldAt returns the layout of the field covering offset
+func (rl RecordLayout) FieldAt(offset int) (FieldLayout, bool) {
+	for _, f := range rl.Fields {
+		if offset >= f.Start && offset < f.End() {
+			return f, true
+		}
+	}
+	return FieldLayout{}, false
+}
+
+// LookupRecordLayout returns the layout of a header, footer or transaction record type
+func LookupRecordLayout(recordType RecordType) (RecordLayout, bool) {
+	rl, ok := recordLayouts[recordType]
+	if !ok {
+		return RecordLayout{}, false
+	}
+	rl.Fields = slices.Clone(rl.Fields)
+	return rl, true
+}
+
+// RecordLayouts returns the layout of every supported record type, the header first, then transactions and the footer
+func RecordLayouts() []RecordLayout {
+	layouts := make([]RecordLayout, 0, len(layoutOrder))
+	for _, rt := range layoutOrder {
+		rl, _ := LookupRecordLayout(rt)
+		layouts = append(layouts, rl)
+	}
+	return layouts
+}
+
+var layoutOrder = []RecordType{HeaderRecord, CreditRecord, DebitRecord, CreditReverseRecord, DebitReverseRecord, ReturnCreditRecord, ReturnDebitRecord, FooterRecord}
+
+// layoutFields fills in the offsets of fields written one after the other
+func layoutFields(fields ...FieldLayout) []FieldLayout {
+	start := 0
+	for i := range fields {
+		fields[i].Start = start
+		start += fields[i].Length
+	}
+	return fields
+}
+
+// recordHeaderFields are the first 24 characters of every line, read and written by RecordHeader
+func recordHeaderFields() []FieldLayout {
+	return []FieldLayout{
+		{Number: 1, Name: "type", Description: "logical record type ID", Length: 1, Format: AlphaFormat, Padding: BlanksRight},
+		{Number: 2, Name: "record_count", Description: "logical record count", Length: 9, Format: NumericFormat, Padding: ZerosLeft},
+		{Number: 3, Name: "originator_id", Description: "originator's ID", Length: 10, Format: AlphaFormat, Padding: ZerosLeft},
+		{Number: 4, Name: "file_creation_number", Description: "file creation number", Length: 4, Format: NumericFormat, Padding: ZerosLeft},
+	}
+}
+
+// txnRecordHeaderFields are the first 24 characters of transaction lines, which CPA-005 numbers as the logical record type,
+// the logical record count and the origination control data holding the originator's ID and the file creation number
+var txnRecordHeaderFields = layoutFields(
+	FieldLayout{Number: 1, Name: "type", Description: "logical record type ID", Length: 1, Format: AlphaFormat, Padding: BlanksRight},
+	FieldLayout{Number: 2, Name: "record_count", Description: "logical record count", Length: 9, Format: NumericFormat, Padding: ZerosLeft},
+	FieldLayout{Number: 3, Name: "origination_control_data", Description: "origination control data", Length: 14, Format: AlphaFormat, Padding: BlanksRight},
+)
+
+// txnFieldNames are the json names and descriptions of the fields that depend on the transaction record type
+type txnFieldNames struct {
+	date, dateDesc         string
+	account, accountDesc   string
+	name, nameDesc         string
+	institution, instDesc  string
+	otherAccount, acctDesc string
+	// originalItemTraceNo is empty for credits and debits which have filler in its place
+	originalItemTraceNo string
+}
+
+func txnLayout(recordType RecordType, n txnFieldNames) RecordLayout {
+	// returns carry the invalid data element ID explaining the return and read it as written, other transactions leave it blank
+	isReturn := recordType == ReturnCreditRecord || recordType == ReturnDebitRecord
+	field19 := FieldLayout{Number: 19, Description: "filler", Length: 22, Format: FillerFormat, Padding: BlanksRight}
+	if n.originalItemTraceNo != "" {
+		field19 = FieldLayout{Number: 19, Name: n.originalItemTraceNo, Description: "original item trace number", Length: 22, Format: NumericFormat, Padding: ZerosLeft}
+	}
+	return RecordLayout{
+		RecordType: recordType,
+		Length:     segmentLength,
+		Fields: layoutFields(
+			FieldLayout{Number: 4, Name: "txn_type", Description: "transaction type", Length: 3, Format: NumericFormat, Padding: ZerosLeft},
+			FieldLayout{Number: 5, Name: "amount", Description: "amount", Length: 10, Format: NumericFormat, Padding: ZerosLeft},
+			FieldLayout{Number: 6, Name: n.date, Description: n.dateDesc, Length: 6, Format: DateFormat, Padding: ZerosLeft},
+			FieldLayout{Number: 7, Name: "institution_id", Description: "institution ID", Length: 9, Format: NumericFormat, Padding: ZerosLeft},
+			FieldLayout{Number: 8, Name: n.account, Description: n.accountDesc, Length: 12, Format: NumericFormat, Padding: BlanksRight},
+			FieldLayout{Number: 9, Name: "item_trace_no", Description: "item trace number", Length: 22, Format: NumericFormat, Padding: ZerosLeft},
+			FieldLayout{Number: 10, Name: "stored_txn_type", Description: "stored transaction type", Length: 3, Format: NumericFormat, Padding: ZerosLeft},
+			FieldLayout{Number: 11, Name: "short_name", Description: "originator short name", Length: 15, Format: AlphaFormat, Padding: BlanksRight},
+			FieldLayout{Number: 12, Name: n.name, Description: n.nameDesc, Length: 30, Format: AlphaFormat, Padding: BlanksRight},
+			FieldLayout{Number: 13, Name: "long_name", Description: "originator long name", Length: 30, Format: AlphaFormat, Padding: BlanksRight},
+			FieldLayout{Number: 14, Name: "user_id", Description: "user ID", Length: 10, Format: AlphaFormat, Padding: BlanksRight},
+			FieldLayout{Number: 15, Name: "cross_ref_no", Description: "cross reference number", Length: 19, Format: AlphaFormat, Padding: BlanksRight},
+			FieldLayout{Number: 16, Name: n.institution, Description: n.instDesc, Length: 9, Format: NumericFormat, Padding: ZerosLeft},
+			FieldLayout{Number: 17, Name: n.otherAccount, Description: n.acctDesc, Length: 12, Format: AlphaFormat, Padding: BlanksRight},
+			FieldLayout{Number: 18, Name: "sundry_info", Description: "sundry information", Length: 15, Format: AlphaFormat, Padding: BlanksRight},
+			field19,
+			FieldLayout{Number: 20, Name: "settlement_code", Description: "settlement code", Length: 2, Format: AlphaFormat, Padding: BlanksRight},
+			FieldLayout{Number: 21, Name: "invalid_data_element_id", Description: "invalid data element ID", Length: 11, Format: NumericFormat, Padding: ZerosRight, Optional: !isReturn},
+		),
+	}
+}
+
+var (
+	creditFieldNames = txnFieldNames{
+		date: "date_funds_available", dateDesc: "date funds available",
+		account: "payee_account_no", accountDesc: "payee account number",
+		name: "payee_name", nameDesc: "payee name",
+	}
+	debitFieldNames = txnFieldNames{
+		date: "due_date", dateDesc: "due date",
+		account: "payor_account_no", accountDesc: "payor account number",
+		name: "payor_name", nameDesc: "payor name",
+	}
+)
+
+// withReturnAccount sets fields 16 and 17 to the account returned items are sent to, used by originals and reversals
+func (n txnFieldNames) withReturnAccount() txnFieldNames {
+	n.institution, n.instDesc = "return_institution_id", "return institution ID"
+	n.otherAccount, n.acctDesc = "return_account_no", "return account number"
+	return n
+}
+
+// withOriginalAccount sets fields 16 and 17 to the account of the original item, used by returns
+func (n txnFieldNames) withOriginalAccount() txnFieldNames {
+	n.institution, n.instDesc = "original_institution_id", "original institution ID"
+	n.otherAccount, n.acctDesc = "original_account_no", "original account number"
+	return n
+}
+
+func (n txnFieldNames) withOriginalItemTraceNo() txnFieldNames {
+	n.originalItemTraceNo = "original_item_trace_no"
+	return n
+}
+
+var recordLayouts = map[RecordType]RecordLayout{
+	HeaderRecord: {
+		RecordType: HeaderRecord,
+		Length:     maxLineLength,
+		Fields: layoutFields(append(recordHeaderFields(),
+			FieldLayout{Number: 5, Name: "creation_date", Description: "creation date", Length: 6, Format: DateFormat, Padding: ZerosLeft},
+			FieldLayout{Number: 6, Name: "destination_data_center", Description: "destination data center", Length: 5, Format: NumericFormat, Padding: ZerosLeft},
+			FieldLayout{Number: 7, Name: "communication_area", Description: "direct clearer communication area", Length: 20, Format: AlphaFormat, Padding: BlanksRight},
+			FieldLayout{Number: 8, Name: "currency_code", Description: "currency code", Length: 3, Format: AlphaFormat, Padding: BlanksRight},
+			FieldLayout{Number: 9, Description: "filler", Length: 1406, Format: FillerFormat, Padding: BlanksRight},
+		)...),
+	},
+	CreditRecord:        txnLayout(CreditRecord, creditFieldNames.withReturnAccount()),
+	DebitRecord:         txnLayout(DebitRecord, debitFieldNames.withReturnAccount()),
+	CreditReverseRecord: txnLayout(CreditReverseRecord, creditFieldNames.withReturnAccount().withOriginalItemTraceNo()),
+	DebitReverseRecord:  txnLayout(DebitReverseRecord, debitFieldNames.withReturnAccount().withOriginalItemTraceNo()),
+	ReturnCreditRecord:  txnLayout(ReturnCreditRecord, creditFieldNames.withOriginalAccount().withOriginalItemTraceNo()),
+	ReturnDebitRecord:   txnLayout(ReturnDebitRecord, debitFieldNames.withOriginalAccount().withOriginalItemTraceNo()),
+	FooterRecord: {
+		RecordType: FooterRecord,
+		Length:     maxLineLength,
+		Fields: layoutFields(append(recordHeaderFields(),
+			FieldLayout{Number: 5, Name: "total_value_debit", Description: "total value of debit", Length: 14, Format: NumericFormat, Padding: ZerosLeft},
+			FieldLayout{Number: 6, Name: "total_count_debit", Description: "total count of debit", Length: 8, Format: NumericFormat, Padding: ZerosLeft},
+			FieldLayout{Number: 7, Name: "total_value_credit", Description: "total value of credit", Length: 14, Format: NumericFormat, Padding: ZerosLeft},
+			FieldLayout{Number: 8, Name: "total_count_credit", Description: "total count of credit", Length: 8, Format: NumericFormat, Padding: ZerosLeft},
+			FieldLayout{Number: 9, Name: "total_value_reverse_debit", Description: "total value of E records", Length: 14, Format: NumericFormat, Padding: ZerosLeft, Optional: true},
+			FieldLayout{Number: 10, Name: "total_count_reverse_debit", Description: "total count of E records", Length: 8, Format: NumericFormat, Padding: ZerosLeft, Optional: true},
+			FieldLayout{Number: 11, Name: "total_value_reverse_credit", Description: "total value of F records", Length: 14, Format: NumericFormat, Padding: ZerosLeft, Optional: true},
+			FieldLayout{Number: 12, Name: "total_count_reverse_credit", Description: "total count of F records", Length: 8, Format: NumericFormat, Padding: ZerosLeft, Optional: true},
+			FieldLayout{Number: 13, Description: "filler", Length: 1352, Format: FillerFormat, Padding: BlanksRight},
+		)...),
+	},
+}
+
+// layoutRecords are the structs the fields of every layout are read into and written from
+var layoutRecords = map[RecordType]any{
+	HeaderRecord:        FileHeader{},
+	CreditRecord:        Credit{},
+	DebitRecord:         Debit{},
+	CreditReverseRecord: CreditReverse{},
+	DebitReverseRecord:  DebitReverse{},
+	ReturnCreditRecord:  CreditReturn{},
+	ReturnDebitRecord:   DebitReturn{},
+	FooterRecord:        FileFooter{},
+}
+
+// init resolves the struct field of every layout field from its json name, the record count is the only field without one
+func init() {
+	for rt, rl := range recordLayouts {
+		recordType := reflect.TypeOf(layoutRecords[rt])
+		for i, f := range rl.Fields {
+			if f.Format == FillerFormat {
+				continue
+			}
+			sf, ok := fieldByJSONName(recordType, f.Name)
+			if !ok && !isRecordHeaderField(rt, f) {
+				panic(fmt.Sprintf("cadeft: layout of %s record has unknown field %q", rt, f.Name))
+			}
+			rl.Fields[i].goName = sf.Name
+		}
+	}
+}
+
+// fieldKind returns the kind of the struct field holding f in records of type rt, numeric fields without one are read as int64
+func fieldKind(rt RecordType, f FieldLayout) reflect.Kind {
+	if sf, ok := reflect.TypeOf(layoutRecords[rt]).FieldByName(f.goName); ok && f.goName != "" {
+		return sf.Type.Kind()
+	}
+	if f.Format == NumericFormat {
+		return reflect.Int64
+	}
+	return reflect.String
+}
+
+// isRecordHeaderField reports whether f is read and written by RecordHeader rather than by the layout
+func isRecordHeaderField(rt RecordType, f FieldLayout) bool {
+	return (rt == HeaderRecord || rt == FooterRecord) && f.Start < commonRecordDataLength
+}
+
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for _, sf := range reflect.VisibleFields(t) {
+		jsonName, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if jsonName == name && sf.IsExported() && !sf.Anonymous {
+			return sf, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// decodeFields reads the fields of the layout of rt from rs into record, a pointer to the struct of that record type.
+// Record header fields are left to RecordHeader and trailing filler may be missing.
+func decodeFields(rt RecordType, rs []rune, record any) error {
+	rv := reflect.ValueOf(record).Elem()
+	for _, f := range recordLayouts[rt].Fields {
+		if f.goName == "" || isRecordHeaderField(rt, f) {
+			continue
+		}
+		if f.End() > len(rs) {
+			return newParseError(ErrInvalidRecordLength, msgFieldMissingFromLine, fieldDescription(f))
+		}
+		fv := rv.FieldByName(f.goName)
+		value, err := decodeValue(f, fv.Kind(), string(rs[f.Start:f.End()]))
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(value).Convert(fv.Type()))
+	}
+	return nil
+}
+
+// decodeValue reads the text of a field as a *time.Time for dates, an int64 for fields of that kind and a trimmed string otherwise.
+// A date of all zeros is how a nil date is written and reads back as nil.
+func decodeValue(f FieldLayout, kind reflect.Kind, text string) (any, error) {
+	switch {
+	case f.Format == DateFormat && strings.Trim(text, "0") == "":
+		return (*time.Time)(nil), nil
+	case f.Format == DateFormat:
+		date, err := parseDate(text)
+		if err != nil {
+			return nil, newParseError(err, msgParseField, fieldDescription(f))
+		}
+		return &date, nil
+	case kind == reflect.Int64:
+		if f.Optional && isFillerString(text) {
+			return int64(0), nil
+		}
+		n, err := parseNum(text)
+		if err != nil {
+			return nil, newParseError(err, msgParseField, fieldDescription(f))
+		}
+		return n, nil
+	case f.Optional && isBlankNumeric(text):
+		return "", nil
+	default:
+		return strings.TrimSpace(text), nil
+	}
+}
+
+// encodeFields writes the fields of the layout of rt from record, the struct of that record type, padding every value to the length of its field.
+// Values too long for their field are written in full except for alphanumeric fields that are cut, validation reports both.
+func encodeFields(rt RecordType, record any) (string, error) {
+	rv := reflect.Indirect(reflect.ValueOf(record))
+	var sb strings.Builder
+	sb.Grow(recordLayouts[rt].Length)
+	for _, f := range recordLayouts[rt].Fields {
+		if isRecordHeaderField(rt, f) {
+			continue
+		}
+		if f.goName == "" {
+			sb.WriteString(createFillerString(f.Length))
+			continue
+		}
+		fv := rv.FieldByName(f.goName)
+		switch {
+		case f.Format == DateFormat:
+			date, _ := fv.Interface().(*time.Time)
+			if date == nil {
+				sb.WriteString(padNumericStringWithZeros("", f.Length))
+				continue
+			}
+			s, err := convertTimestampToEftDate(*date)
+			if err != nil {
+				return "", newError(msgFormatField, fieldDescription(f), err)
+			}
+			sb.WriteString(s)
+		case fv.Kind() == reflect.Int64:
+			s, err := formatNumField(fv.Int(), f.Length)
+			if err != nil {
+				return "", newError(msgFormatField, fieldDescription(f), err)
+			}
+			sb.WriteString(s)
+		default:
+			s, err := padField(fv.String(), f)
+			if err != nil {
+				return "", newError(msgFormatField, fieldDescription(f), err)
+			}
+			sb.WriteString(s)
+		}
+	}
+	return sb.String(), nil
+}
+
+func padField(s string, f FieldLayout) (string, error) {
+	switch f.Padding {
+	case ZerosLeft:
+		return padNumericStringWithZeros(s, f.Length), nil
+	case ZerosRight:
+		return padNumericStringWithTrailingZeros(s, f.Length), nil
+	case BlanksRight:
+		return formatName(s, f.Length)
+	}
+	return "", fmt.Errorf("unknown padding %q", f.Padding)
+}
@@ -0,0 +1,94 @@
+package cadeft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordLayouts(t *testing.T) {
+	r := require.New(t)
+	layouts := RecordLayouts()
+	r.Len(layouts, 8)
+	for _, rl := range layouts {
+		offset, number := 0, 0
+		for _, f := range rl.Fields {
+			r.Equal(offset, f.Start, "%s field %d", rl.RecordType, f.Number)
+			r.Greater(f.Number, number, "%s field %d", rl.RecordType, f.Number)
+			r.Equal(f.Format == FillerFormat, f.Name == "", "%s field %d", rl.RecordType, f.Number)
+			offset, number = f.End(), f.Number
+		}
+		r.Equal(rl.Length, offset, "%s", rl.RecordType)
+	}
+
+	credit, ok := LookupRecordLayout(CreditRecord)
+	r.True(ok)
+	payeeName, ok := credit.Field("payee_name")
+	r.True(ok)
+	r.Equal(FieldLayout{Number: 12, Name: "payee_name", Description: "payee name", Start: 80, Length: 30, Format: AlphaFormat, Padding: BlanksRight, goName: "PayeeName"}, payeeName)
+	filler, ok := credit.FieldAt(210)
+	r.True(ok)
+	r.Equal(19, filler.Number)
+	r.Equal(FillerFormat, filler.Format)
+	_, ok = credit.Field("original_item_trace_no")
+	r.False(ok)
+
+	creditReturn, _ := LookupRecordLayout(ReturnCreditRecord)
+	original, ok := creditReturn.FieldAt(210)
+	r.True(ok)
+	r.Equal("original_item_trace_no", original.Name)
+
+	footer, _ := LookupRecordLayout(FooterRecord)
+	count, ok := footer.FieldAt(5)
+	r.True(ok)
+	r.Equal(2, count.Number)
+
+	// layouts are copies
+	credit.Fields[0].Length = 1
+	credit, _ = LookupRecordLayout(CreditRecord)
+	r.Equal(3, credit.Fields[0].Length)
+
+	_, ok = LookupRecordLayout(NoticeOfChangeRecord)
+	r.False(ok)
+}
+
+func TestLayoutRoundTrip(t *testing.T) {
+	date := time.Date(2023, time.October, 2, 0, 0, 0, 0, time.UTC)
+	opts := []BaseTxnOpt{WithUserID("USER"), WithCrossRefNo("REF-1"), WithSundryInfo("SUNDRY"), WithSettlementCode("01"), WithInvalidDataElementID("19"), WithStoredTransactionType("450")}
+	cases := []struct {
+		txn    Transaction
+		parsed Transaction
+	}{
+		{Ptr(NewCredit("450", 100, &date, "000112345", "123456789", "1", "SHORT", "PAYEE", "LONG", "000267890", "987654321", opts...)), &Credit{}},
+		{Ptr(NewDebit("450", 100, &date, "000112345", "123456789", "1", "SHORT", "PAYOR", "LONG", "000267890", "987654321", opts...)), &Debit{}},
+		{Ptr(NewCreditReverse("450", 100, &date, "000112345", "123456789", "1", "SHORT", "PAYEE", "LONG", "000267890", "987654321", "2", opts...)), &CreditReverse{}},
+		{Ptr(NewDebitReverse("450", 100, &date, "000112345", "123456789", "1", "SHORT", "PAYOR", "LONG", "000267890", "987654321", "2", opts...)), &DebitReverse{}},
+		{Ptr(NewCreditReturn("450", 100, &date, "000112345", "123456789", "1", "SHORT", "PAYEE", "LONG", "000267890", "987654321", "2", opts...)), &CreditReturn{}},
+		{Ptr(NewDebitReturn("450", 100, &date, "000112345", "123456789", "1", "SHORT", "PAYOR", "LONG", "000267890", "987654321", "2", opts...)), &DebitReturn{}},
+	}
+	for _, tc := range cases {
+		t.Run(string(tc.txn.GetType()), func(t *testing.T) {
+			r := require.New(t)
+			out, err := tc.txn.Build()
+			r.NoError(err)
+			r.Len(out, segmentLength)
+			r.NoError(tc.parsed.Parse(out))
+
+			base := tc.parsed.GetBaseTxn()
+			r.Equal("0000000000000000000001", base.ItemTraceNo)
+			r.Equal("19000000000", base.InvalidDataElementID)
+			r.Equal("SUNDRY", base.SundryInfo)
+			r.Equal(tc.txn.GetType(), tc.parsed.GetType())
+			r.Equal(tc.txn.GetName(), tc.parsed.GetName())
+			r.Equal(*tc.txn.GetDate(), *tc.parsed.GetDate())
+			if tc.txn.GetOriginalItemTraceNo() != "" || tc.parsed.GetOriginalItemTraceNo() != "" {
+				r.Equal("0000000000000000000002", tc.parsed.GetOriginalItemTraceNo())
+			}
+
+			rebuilt, err := tc.parsed.Build()
+			r.NoError(err)
+			r.Equal(out, rebuilt)
+		})
+	}
+}
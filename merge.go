@@ -0,0 +1,136 @@
+package cadeft
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MergeConflict describes why a file could not be merged, either a header field that differs from the first file
+// or an item trace number already used by another transaction
+type MergeConflict struct {
+	FileIndex int    `json:"file_index"`
+	TxnIndex  *int   `json:"txn_index,omitempty"`
+	Field     string `json:"field"`
+	Value     string `json:"value"`
+	// OtherFileIndex and OtherTxnIndex locate the header or transaction holding OtherValue
+	OtherFileIndex int    `json:"other_file_index"`
+	OtherTxnIndex  *int   `json:"other_txn_index,omitempty"`
+	OtherValue     string `json:"other_value"`
+	Err            error  `json:"-"`
+}
+
+func (c MergeConflict) Error() string {
+	var msg string
+	if c.TxnIndex != nil && c.OtherTxnIndex != nil {
+		msg = fmt.Sprintf("file %d txn %d %s %s is used by file %d txn %d", c.FileIndex, *c.TxnIndex, c.Field, c.Value, c.OtherFileIndex, *c.OtherTxnIndex)
+	} else {
+		msg = fmt.Sprintf("file %d %s %q does not match %q of file %d", c.FileIndex, c.Field, c.Value, c.OtherValue, c.OtherFileIndex)
+	}
+	if c.Err != nil {
+		msg += ": " + c.Err.Error()
+	}
+	return msg
+}
+
+func (c MergeConflict) Unwrap() error {
+	return c.Err
+}
+
+// MergeConflicts is returned by MergeFiles with every conflict found between the files
+type MergeConflicts []MergeConflict
+
+func (mc MergeConflicts) Error() string {
+	msgs := make([]string, 0, len(mc))
+	for _, c := range mc {
+		msgs = append(msgs, c.Error())
+	}
+	return fmt.Sprintf("%d merge conflicts: %s", len(mc), strings.Join(msgs, "; "))
+}
+
+// Unwrap allows errors.Is to match ErrIncompatibleHeaders and ErrDuplicateItemTraceNo
+func (mc MergeConflicts) Unwrap() []error {
+	errs := make([]error, 0, len(mc))
+	for _, c := range mc {
+		errs = append(errs, c)
+	}
+	return errs
+}
+
+// MergeFiles combines files of the same originator into a single submission. Every header must share the OriginatorID, CurrencyCode and
+// DestinationDataCenterNo of the first file, whose header is used for the merged file, and item trace numbers must be unique across all files.
+// Transactions are concatenated in order and the footer is recomputed. All conflicts are returned together as MergeConflicts.
+func MergeFiles(files ...File) (File, error) {
+	if len(files) == 0 {
+		return File{}, newError(msgNoFilesToMerge)
+	}
+	for i, f := range files {
+		if f.Header == nil {
+			return File{}, newError(msgFile, i, ErrMissingHeader)
+		}
+	}
+
+	var conflicts MergeConflicts
+	first := files[0].Header
+	for i, f := range files[1:] {
+		fileIndex := i + 1
+		fields := []struct {
+			name         string
+			value, other string
+		}{
+			{"OriginatorID", f.Header.OriginatorID, first.OriginatorID},
+			{"CurrencyCode", f.Header.CurrencyCode, first.CurrencyCode},
+			{"DestinationDataCenterNo", strconv.FormatInt(f.Header.DestinationDataCenterNo, 10), strconv.FormatInt(first.DestinationDataCenterNo, 10)},
+		}
+		for _, field := range fields {
+			if field.value != field.other {
+				conflicts = append(conflicts, MergeConflict{
+					FileIndex:  fileIndex,
+					Field:      field.name,
+					Value:      field.value,
+					OtherValue: field.other,
+					Err:        ErrIncompatibleHeaders,
+				})
+			}
+		}
+	}
+
+	type location struct{ file, txn int }
+	seen := make(map[string]location)
+	txns := make(Transactions, 0)
+	for fileIndex, f := range files {
+		for txnIndex, t := range f.Txns {
+			txns = append(txns, t)
+			traceNo := t.GetBaseTxn().ItemTraceNo
+			if isBlankNumeric(traceNo) {
+				continue
+			}
+			key := padNumericStringWithZeros(strings.TrimSpace(traceNo), 22)
+			if other, ok := seen[key]; ok {
+				conflicts = append(conflicts, MergeConflict{
+					FileIndex:      fileIndex,
+					TxnIndex:       Ptr(txnIndex),
+					Field:          "ItemTraceNo",
+					Value:          traceNo,
+					OtherFileIndex: other.file,
+					OtherTxnIndex:  Ptr(other.txn),
+					OtherValue:     files[other.file].Txns[other.txn].GetBaseTxn().ItemTraceNo,
+					Err:            ErrDuplicateItemTraceNo,
+				})
+				continue
+			}
+			seen[key] = location{file: fileIndex, txn: txnIndex}
+		}
+	}
+	if len(conflicts) > 0 {
+		return File{}, conflicts
+	}
+
+	header := *first
+	footer := NewFileFooter(RecordHeader{
+		RecordType:      FooterRecord,
+		OriginatorID:    header.OriginatorID,
+		FileCreationNum: header.FileCreationNum,
+	}, txns)
+	return File{Header: &header, Txns: txns, Footer: footer}, nil
+}
@@ -0,0 +1,80 @@
+package cadeft
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeFiles(t *testing.T) {
+	date := time.Date(2023, time.October, 2, 0, 0, 0, 0, time.UTC)
+	credit := func(amount int64, traceNo string) Transaction {
+		return Ptr(NewCredit("450", amount, &date, "123456789", "123456789012", traceNo, "SHORT", "PAYEE", "LONG", "123456789", "210987654321"))
+	}
+	debit := func(amount int64, traceNo string) Transaction {
+		return Ptr(NewDebit("450", amount, &date, "123456789", "123456789012", traceNo, "SHORT", "PAYOR", "LONG", "123456789", "210987654321"))
+	}
+	newFile := func(originatorID, currency string, dataCenter int64, txns ...Transaction) File {
+		return NewFile(NewFileHeader(originatorID, 1, &date, dataCenter, currency), txns)
+	}
+
+	t.Run("merges transactions and recomputes footer", func(t *testing.T) {
+		r := require.New(t)
+		payroll := newFile("0000000001", "CAD", 12345, credit(100, "1"), credit(200, ""))
+		billing := newFile("0000000001", "CAD", 12345, debit(50, "2"), credit(300, ""))
+		merged, err := MergeFiles(payroll, billing)
+		r.NoError(err)
+		r.Len(merged.Txns, 4)
+		r.Equal(int64(600), merged.Footer.TotalValueOfCredit)
+		r.Equal(int64(3), merged.Footer.TotalCountOfCredit)
+		r.Equal(int64(50), merged.Footer.TotalValueOfDebit)
+		r.NotSame(payroll.Header, merged.Header)
+		r.Len(payroll.Txns, 2)
+
+		s, err := merged.Create()
+		r.NoError(err)
+		parsed, err := NewReader(strings.NewReader(s)).ReadFile()
+		r.NoError(err)
+		r.Len(parsed.Txns, 4)
+	})
+
+	t.Run("reports every conflict", func(t *testing.T) {
+		r := require.New(t)
+		a := newFile("0000000001", "CAD", 12345, credit(100, "0001"), credit(200, "0002"))
+		b := newFile("0000000002", "USD", 12345, debit(50, "1"))
+		c := newFile("0000000001", "CAD", 54321, credit(100, "3"), credit(100, "3"))
+		_, err := MergeFiles(a, b, c)
+		r.ErrorIs(err, ErrIncompatibleHeaders)
+		r.ErrorIs(err, ErrDuplicateItemTraceNo)
+
+		var conflicts MergeConflicts
+		r.True(errors.As(err, &conflicts))
+		r.Len(conflicts, 5)
+		r.Equal(MergeConflict{FileIndex: 1, Field: "OriginatorID", Value: "0000000002", OtherValue: "0000000001", Err: ErrIncompatibleHeaders}, conflicts[0])
+		r.Equal("CurrencyCode", conflicts[1].Field)
+		r.Equal("DestinationDataCenterNo", conflicts[2].Field)
+		r.Equal(`file 2 DestinationDataCenterNo "54321" does not match "12345" of file 0: incompatible file headers`, conflicts[2].Error())
+
+		dup := conflicts[3]
+		r.Equal(1, dup.FileIndex)
+		r.Equal(0, *dup.TxnIndex)
+		r.Equal(0, dup.OtherFileIndex)
+		r.Equal(0, *dup.OtherTxnIndex)
+		r.Equal("0001", dup.OtherValue)
+		r.Equal("file 2 txn 1 ItemTraceNo 3 is used by file 2 txn 0: duplicate item trace number", conflicts[4].Error())
+		r.True(strings.HasPrefix(err.Error(), `5 merge conflicts: file 1 OriginatorID "0000000002" does not match "0000000001" of file 0: incompatible file headers; file 1 CurrencyCode`))
+		r.Equal(err.Error(), Translate(err, English))
+		r.Contains(Translate(err, French), "5 conflits de fusion : fichier 1 OriginatorID")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		r := require.New(t)
+		_, err := MergeFiles()
+		r.Error(err)
+		_, err = MergeFiles(newFile("0000000001", "CAD", 12345), File{})
+		r.ErrorIs(err, ErrMissingHeader)
+	})
+}
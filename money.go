@@ -0,0 +1,116 @@
+package cadeft
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Cents is an amount of money in cents, the unit of the amount and total value fields of CPA-005 records
+type Cents int64
+
+// ParseCents reads an amount written with a decimal point and comma thousands separators such as "1,234.56", or with a decimal comma
+// and space thousands separators such as "1 234,56". A leading or trailing $ sign and a leading minus sign are accepted.
+// When a single separator is followed by three digits, as in "1,234", it is taken as a thousands separator.
+func ParseCents(s string) (Cents, error) {
+	in := strings.TrimSpace(s)
+	negative := strings.HasPrefix(in, "-")
+	in = strings.TrimPrefix(in, "-")
+	in = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(in, "$"), "$"))
+	if in == "" {
+		return 0, newError(msgErrQuoted, ErrInvalidMoney, s)
+	}
+
+	units, fraction := in, ""
+	if i := strings.LastIndexAny(in, ".,"); i >= 0 && len(in)-i-1 != 3 {
+		units, fraction = in[:i], in[i+1:]
+		// the decimal separator cannot also be used to group thousands
+		if len(fraction) > 2 || !isDigits(fraction) || strings.ContainsRune(units, rune(in[i])) {
+			return 0, newError(msgErrQuoted, ErrInvalidMoney, s)
+		}
+	}
+	digits, ok := ungroupDigits(units)
+	if !ok {
+		return 0, newError(msgErrQuoted, ErrInvalidMoney, s)
+	}
+	fraction += strings.Repeat("0", 2-len(fraction))
+
+	value, err := strconv.ParseInt(digits+fraction, 10, 64)
+	if err != nil {
+		return 0, newError(msgInvalidMoneyCause, ErrInvalidMoney, s, err)
+	}
+	if negative {
+		value = -value
+	}
+	return Cents(value), nil
+}
+
+// ungroupDigits removes the thousands separators of units checking that every group after the first has three digits
+func ungroupDigits(units string) (string, bool) {
+	var groups []string
+	start := 0
+	for i, r := range units {
+		if r == ',' || r == '.' || r == '\'' || unicode.IsSpace(r) {
+			groups = append(groups, units[start:i])
+			start = i + utf8.RuneLen(r)
+		}
+	}
+	groups = append(groups, units[start:])
+	for i, g := range groups {
+		if !isDigits(g) || (i > 0 && len(g) != 3) || (i == 0 && len(groups) > 1 && len(g) > 3) {
+			return "", false
+		}
+	}
+	return strings.Join(groups, ""), true
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Add returns the sum of c and o or ErrFieldOverflow when it does not fit in an int64
+func (c Cents) Add(o Cents) (Cents, error) {
+	if (o > 0 && c > math.MaxInt64-o) || (o < 0 && c < math.MinInt64-o) {
+		return 0, newError(msgSumOverflow, ErrFieldOverflow, c, o)
+	}
+	return c + o, nil
+}
+
+// Format writes c with two decimals using the conventions of locale, "1,234.56" in English and "1 234,56" in French
+func (c Cents) Format(locale Locale) string {
+	thousands, decimal := ",", "."
+	if lang, _, _ := strings.Cut(strings.ToLower(string(locale)), "-"); strings.HasPrefix(lang, string(French)) {
+		thousands, decimal = " ", ","
+	}
+	sign := ""
+	units := uint64(c)
+	if c < 0 {
+		sign = "-"
+		units = uint64(-(c + 1)) + 1
+	}
+	fraction := units % 100
+	digits := strconv.FormatUint(units/100, 10)
+	var sb strings.Builder
+	sb.WriteString(sign)
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteString(thousands)
+		}
+		sb.WriteRune(d)
+	}
+	fmt.Fprintf(&sb, "%s%02d", decimal, fraction)
+	return sb.String()
+}
+
+// String formats c in English
+func (c Cents) String() string {
+	return c.Format(English)
+}
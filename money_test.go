@@ -0,0 +1,83 @@
+package cadeft
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCents(t *testing.T) {
+	cases := map[string]Cents{
+		"1,234.56":      123456,
+		"1 234,56":      123456,
+		"1 234,56":      123456,
+		"1 234,56":      123456,
+		"1.234,56":      123456,
+		"1234.5":        123450,
+		"1,234":         123400,
+		"$1,234,567.89": 123456789,
+		"12,50 $":       1250,
+		"-0.01":         -1,
+		"0":             0,
+		"12.345":        1234500,
+	}
+	for in, expected := range cases {
+		t.Run(in, func(t *testing.T) {
+			c, err := ParseCents(in)
+			require.NoError(t, err)
+			require.Equal(t, expected, c)
+		})
+	}
+
+	for _, in := range []string{"", "abc", "1,23,456.00", "1.234.56", "1.", "1,2345.00", ",50", "1.2.3", "92233720368547758.08"} {
+		t.Run("invalid "+in, func(t *testing.T) {
+			_, err := ParseCents(in)
+			require.ErrorIs(t, err, ErrInvalidMoney)
+		})
+	}
+}
+
+func TestFormatCents(t *testing.T) {
+	r := require.New(t)
+	r.Equal("1,234.56", Cents(123456).String())
+	r.Equal("1 234,56", Cents(123456).Format(French))
+	r.Equal("0.05", Cents(5).Format(English))
+	r.Equal("-999,999.99", Cents(-99999999).Format(English))
+	r.Equal("-92,233,720,368,547,758.08", Cents(math.MinInt64).Format(English))
+	for _, c := range []Cents{0, 1, 99, 100, 123456789, -123456} {
+		for _, locale := range []Locale{English, French} {
+			parsed, err := ParseCents(c.Format(locale))
+			r.NoError(err)
+			r.Equal(c, parsed)
+		}
+	}
+}
+
+func TestCentsAdd(t *testing.T) {
+	r := require.New(t)
+	sum, err := Cents(100).Add(250)
+	r.NoError(err)
+	r.Equal(Cents(350), sum)
+	_, err = Cents(math.MaxInt64).Add(1)
+	r.ErrorIs(err, ErrFieldOverflow)
+	_, err = Cents(math.MinInt64).Add(-1)
+	r.ErrorIs(err, ErrFieldOverflow)
+}
+
+func TestBuildTxnAmountOverflow(t *testing.T) {
+	date := time.Date(2023, time.October, 2, 0, 0, 0, 0, time.UTC)
+	txns := Transactions{
+		Ptr(NewCredit("450", 10000000000, &date, "123456789", "123456789012", "", "SHORT", "PAYEE", "LONG", "123456789", "210987654321")),
+		Ptr(NewDebit("450", -1, &date, "123456789", "123456789012", "", "SHORT", "PAYOR", "LONG", "123456789", "210987654321")),
+		Ptr(NewCreditReturn("450", 10000000000, &date, "123456789", "123456789012", "", "SHORT", "PAYEE", "LONG", "123456789", "210987654321", "1")),
+		Ptr(NewDebitReturn("450", 10000000000, &date, "123456789", "123456789012", "", "SHORT", "PAYOR", "LONG", "123456789", "210987654321", "1")),
+		Ptr(NewCreditReverse("450", 10000000000, &date, "123456789", "123456789012", "", "SHORT", "PAYEE", "LONG", "123456789", "210987654321", "1")),
+		Ptr(NewDebitReverse("450", 10000000000, &date, "123456789", "123456789012", "", "SHORT", "PAYOR", "LONG", "123456789", "210987654321", "1")),
+	}
+	for _, txn := range txns {
+		_, err := txn.Build()
+		require.ErrorIs(t, err, ErrFieldOverflow, "%T", txn)
+	}
+}
@@ -0,0 +1,522 @@
+// Package nacha converts cadeft files to and from NACHA ACH files of PPD or CCD entries.
+// Institution IDs are carried as 9 digit ABA routing numbers. CPA-005 and NACHA do not carry
+// the same fields, whatever cannot be converted as is is reported as a Warning alongside the
+// converted file.
+package nacha
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/moov-io/cadeft"
+)
+
+// Standard entry class codes of the entries converted
+const (
+	PPD = "PPD"
+	CCD = "CCD"
+)
+
+// DefaultTxnType is the CPA transaction type of imported entries, NACHA entries do not carry one
+const DefaultTxnType cadeft.TransactionType = "450"
+
+// Transaction codes of checking account entries
+const (
+	checkingCreditReturn = 21
+	checkingCredit       = 22
+	checkingDebitReturn  = 26
+	checkingDebit        = 27
+)
+
+// Service class codes of batches
+const (
+	mixedBatch  = 200
+	creditBatch = 220
+	debitBatch  = 225
+)
+
+// Warning is a field that could not be converted as is, it was dropped, truncated or defaulted
+type Warning struct {
+	// Index is the position of the transaction in File.Txns or of the entry in the NACHA file, -1 for file and batch fields
+	Index  int    `json:"index"`
+	Field  string `json:"field"`
+	Value  string `json:"value,omitempty"`
+	Reason string `json:"reason"`
+}
+
+func (w Warning) String() string {
+	if w.Index < 0 {
+		return fmt.Sprintf("%s %q: %s", w.Field, w.Value, w.Reason)
+	}
+	return fmt.Sprintf("txn %d: %s %q: %s", w.Index, w.Field, w.Value, w.Reason)
+}
+
+type warnings []Warning
+
+func (ws *warnings) add(index int, field, value, reason string) {
+	*ws = append(*ws, Warning{Index: index, Field: field, Value: value, Reason: reason})
+}
+
+// fit truncates value to the length of a field and reports it when it is cut
+func (ws *warnings) fit(index int, field, value string, length int) string {
+	value = strings.TrimSpace(value)
+	if utf8.RuneCountInString(value) <= length {
+		return value
+	}
+	ws.add(index, field, value, fmt.Sprintf("truncated to %d characters", length))
+	return string([]rune(value)[:length])
+}
+
+type config struct {
+	secCode             string
+	entryDescription    string
+	destination         string
+	destinationName     string
+	origin              string
+	originName          string
+	txnType             cadeft.TransactionType
+	returnInstitutionID string
+	returnAccountNo     string
+}
+
+type Opt func(*config)
+
+// WithSECCode sets the standard entry class code of the batches written, PPD by default
+func WithSECCode(code string) Opt {
+	return func(c *config) {
+		c.secCode = code
+	}
+}
+
+// WithEntryDescription sets the company entry description of the batches written, PAYMENT by default
+func WithEntryDescription(description string) Opt {
+	return func(c *config) {
+		c.entryDescription = description
+	}
+}
+
+// WithImmediateDestination sets the routing number and name of the file header, the institution of the first batch by default
+func WithImmediateDestination(routing, name string) Opt {
+	return func(c *config) {
+		c.destination, c.destinationName = routing, name
+	}
+}
+
+// WithImmediateOrigin sets the origin and its name of the file header, the originator ID and long name by default
+func WithImmediateOrigin(origin, name string) Opt {
+	return func(c *config) {
+		c.origin, c.originName = origin, name
+	}
+}
+
+// WithTxnType sets the CPA transaction type of imported entries, DefaultTxnType by default
+func WithTxnType(txnType cadeft.TransactionType) Opt {
+	return func(c *config) {
+		c.txnType = txnType
+	}
+}
+
+// WithReturnAccount sets the return account of imported entries, or the original account of imported returns, which NACHA does not carry.
+// The return institution defaults to the originating DFI of the batch.
+func WithReturnAccount(institutionID, accountNo string) Opt {
+	return func(c *config) {
+		c.returnInstitutionID, c.returnAccountNo = institutionID, accountNo
+	}
+}
+
+func newConfig(opts []Opt) config {
+	cfg := config{secCode: PPD, entryDescription: "PAYMENT", txnType: DefaultTxnType}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// FileToNACHA converts the credits, debits and returns of f to a NACHA file. Credits and debits become checking account entries,
+// returned credits and debits (I and J records) return entries with a return addenda whose R code is the one of the CPA return reason
+// carried in the invalid data element ID. Entries are batched by date, originator and originating DFI, the originator short name is
+// written as company name, its long name as company discretionary data, the cross reference number as individual ID and the sundry
+// information as payment related information. Item trace numbers that do not fit the 15 digits of a NACHA trace number are replaced.
+// Reversals and fields without a NACHA counterpart are reported as warnings.
+func FileToNACHA(f cadeft.File, opts ...Opt) ([]byte, []Warning, error) {
+	if f.Header == nil {
+		return nil, nil, fmt.Errorf("file header is missing")
+	}
+	cfg := newConfig(opts)
+	if cfg.secCode != PPD && cfg.secCode != CCD {
+		return nil, nil, fmt.Errorf("unsupported standard entry class code %q", cfg.secCode)
+	}
+	var ws warnings
+	if f.Header.CurrencyCode != "USD" {
+		ws.add(-1, "currency_code", f.Header.CurrencyCode, "NACHA amounts are in USD, amounts are copied as is")
+	}
+	out := file{
+		destination:     cfg.destination,
+		destinationName: cfg.destinationName,
+		origin:          cfg.origin,
+		originName:      cfg.originName,
+		fileIDModifier:  "A",
+		created:         time.Now(),
+	}
+	if f.Header.CreationDate != nil {
+		out.created = *f.Header.CreationDate
+	}
+	if out.origin == "" {
+		out.origin = f.Header.OriginatorID
+	}
+	index := map[string]int{}
+	for i, t := range f.Txns {
+		e, ok, err := newEntry(&ws, i, t)
+		if err != nil {
+			return nil, ws, err
+		}
+		if !ok {
+			continue
+		}
+		b := t.GetBaseTxn()
+		odfi := e.odfi
+		key := strings.Join([]string{formatDate(t.GetDate()), b.OriginatorShortName, b.OriginatorLongName, odfi, fmt.Sprint(e.returned())}, "/")
+		n, ok := index[key]
+		if !ok {
+			n = len(out.batches)
+			index[key] = n
+			companyName := b.OriginatorShortName
+			if strings.TrimSpace(companyName) == "" {
+				companyName = b.OriginatorLongName
+			}
+			out.batches = append(out.batches, batch{
+				companyName:          ws.fit(i, "short_name", companyName, 16),
+				companyDiscretionary: ws.fit(i, "long_name", b.OriginatorLongName, 20),
+				companyID:            f.Header.OriginatorID,
+				secCode:              cfg.secCode,
+				entryDescription:     cfg.entryDescription,
+				effectiveDate:        *t.GetDate(),
+				odfi:                 odfi[:8],
+				number:               n + 1,
+			})
+			if out.destination == "" {
+				out.destination = odfi
+			}
+			if out.originName == "" {
+				out.originName = strings.TrimSpace(b.OriginatorLongName)
+			}
+		}
+		bt := &out.batches[n]
+		if e.traceNo == "" {
+			e.traceNo = fmt.Sprintf("%s%07d", bt.odfi, len(bt.entries)+1)
+		}
+		if b.TxnType != cfg.txnType {
+			ws.add(i, "txn_type", string(b.TxnType), "no NACHA counterpart, dropped")
+		}
+		bt.entries = append(bt.entries, e.entry)
+	}
+	for n := range out.batches {
+		out.batches[n].serviceClass = serviceClass(out.batches[n].entries)
+	}
+	return []byte(out.build()), ws, nil
+}
+
+// outgoingEntry is an entry and the routing number of the originating DFI of its batch
+type outgoingEntry struct {
+	entry
+	odfi string
+}
+
+// newEntry converts the transaction at index i, transactions without a NACHA counterpart are reported and skipped
+func newEntry(ws *warnings, i int, t cadeft.Transaction) (outgoingEntry, bool, error) {
+	var e outgoingEntry
+	var err error
+	b := t.GetBaseTxn()
+	switch t.GetType() {
+	case cadeft.CreditRecord:
+		e.txnCode = checkingCredit
+	case cadeft.DebitRecord:
+		e.txnCode = checkingDebit
+	case cadeft.ReturnCreditRecord:
+		e.txnCode = checkingCreditReturn
+	case cadeft.ReturnDebitRecord:
+		e.txnCode = checkingDebitReturn
+	case cadeft.CreditReverseRecord, cadeft.DebitReverseRecord:
+		ws.add(i, "type", string(t.GetType()), "reversals have no NACHA counterpart, skipped")
+		return e, false, nil
+	default:
+		ws.add(i, "type", string(t.GetType()), "unsupported record type, skipped")
+		return e, false, nil
+	}
+	if t.GetDate() == nil {
+		return e, false, fmt.Errorf("txn %d: date is missing", i)
+	}
+	e.accountNo = ws.fit(i, "account_no", t.GetAccountNo(), 17)
+	e.amount = b.Amount
+	e.individualID = ws.fit(i, "cross_ref_no", b.CrossRefNo, 15)
+	e.name = ws.fit(i, "name", t.GetName(), 22)
+
+	if !e.returned() {
+		if e.rdfi, err = routing(ws, i, "institution_id", b.InstitutionID); err != nil {
+			return e, false, err
+		}
+		if e.odfi, err = routing(ws, i, "return_institution_id", t.GetReturnInstitutionID()); err != nil {
+			return e, false, err
+		}
+		if sundry := strings.TrimSpace(b.SundryInfo); sundry != "" {
+			e.paymentInfo = []string{sundry}
+		}
+		if acct := strings.TrimSpace(t.GetReturnAccountNo()); acct != "" {
+			ws.add(i, "return_account_no", acct, "no NACHA counterpart, dropped")
+		}
+		if traceNo, ok := nachaTraceNo(b.ItemTraceNo); ok {
+			e.traceNo = traceNo
+		} else if strings.Trim(b.ItemTraceNo, "0 ") != "" {
+			ws.add(i, "item_trace_no", b.ItemTraceNo, "does not fit a NACHA trace number, replaced")
+		}
+		reportUnmapped(ws, i, b, false)
+		return e, true, nil
+	}
+
+	// a return is sent by the receiving DFI of the original entry back to its originating DFI
+	if e.rdfi, err = routing(ws, i, "original_institution_id", t.GetOriginalInstitutionID()); err != nil {
+		return e, false, err
+	}
+	if e.odfi, err = routing(ws, i, "institution_id", b.InstitutionID); err != nil {
+		return e, false, err
+	}
+	if acct := strings.TrimSpace(t.GetOriginalAccountNo()); acct != "" {
+		ws.add(i, "original_account_no", acct, "no NACHA counterpart, dropped")
+	}
+	if sundry := strings.TrimSpace(b.SundryInfo); sundry != "" {
+		ws.add(i, "sundry_info", sundry, "return entries carry no payment related information, dropped")
+	}
+	if traceNo, ok := nachaTraceNo(b.ItemTraceNo); ok {
+		e.traceNo = traceNo
+	} else if strings.Trim(b.ItemTraceNo, "0 ") != "" {
+		ws.add(i, "item_trace_no", b.ItemTraceNo, "does not fit a NACHA trace number, replaced")
+	}
+	e.ret = &returnAddenda{originalRDFI: e.odfi[:8], reasonCode: DefaultRCode}
+	originalTraceNo, ok := nachaTraceNo(t.GetOriginalItemTraceNo())
+	if !ok {
+		originalTraceNo = lastDigits(strings.TrimSpace(t.GetOriginalItemTraceNo()), 15)
+		ws.add(i, "original_item_trace_no", t.GetOriginalItemTraceNo(), "does not fit a NACHA trace number, last 15 digits kept")
+	}
+	e.ret.originalTraceNo = originalTraceNo
+	cpaCode := b.ReturnReasonCode()
+	if c, ok := LookupRCode(cpaCode); ok {
+		e.ret.reasonCode = c.RCode
+	} else {
+		ws.add(i, "invalid_data_element_id", cpaCode, "no NACHA return reason code, returned as "+DefaultRCode)
+	}
+	if cpaCode != "" {
+		// the CPA reason is kept in the addenda information so that reasons sharing an R code convert back unchanged
+		e.ret.info = cpaInfoPrefix + cpaCode
+	}
+	reportUnmapped(ws, i, b, true)
+	return e, true, nil
+}
+
+// cpaInfoPrefix precedes the CPA return reason code written in the addenda information of return entries
+const cpaInfoPrefix = "CPA "
+
+// routing checks that an institution ID is a 9 digit routing number and reports a wrong check digit
+func routing(ws *warnings, i int, field, institutionID string) (string, error) {
+	institutionID = strings.TrimSpace(institutionID)
+	if len(institutionID) != 9 || !isDigits(institutionID) {
+		return "", fmt.Errorf("txn %d: %s %q is not a 9 digit routing number", i, field, institutionID)
+	}
+	if !validABA(institutionID) {
+		ws.add(i, field, institutionID, "ABA check digit does not match")
+	}
+	return institutionID, nil
+}
+
+// nachaTraceNo returns an item trace number as a 15 digit trace number when its leading digits are zeros
+func nachaTraceNo(itemTraceNo string) (string, bool) {
+	itemTraceNo = strings.TrimSpace(itemTraceNo)
+	if !isDigits(itemTraceNo) || strings.Trim(itemTraceNo, "0") == "" {
+		return "", false
+	}
+	trimmed := strings.TrimLeft(itemTraceNo, "0")
+	if len(trimmed) > 15 {
+		return "", false
+	}
+	return fmt.Sprintf("%015s", trimmed), true
+}
+
+// reportUnmapped reports the transaction fields that have no counterpart in NACHA entries. The invalid data element ID of returns
+// is their return reason and their stored transaction type the type of the original transaction, neither is reported for them.
+func reportUnmapped(ws *warnings, i int, b cadeft.BaseTxn, returned bool) {
+	unmapped := []struct{ field, value string }{
+		{"user_id", b.UserID},
+		{"settlement_code", b.SettlementCode},
+	}
+	if !returned {
+		unmapped = append(unmapped,
+			struct{ field, value string }{"invalid_data_element_id", strings.Trim(b.InvalidDataElementID, "0 ")},
+			struct{ field, value string }{"stored_txn_type", strings.Trim(string(b.StoredTransactionType), "0 ")},
+		)
+	}
+	for _, u := range unmapped {
+		if strings.TrimSpace(u.value) != "" {
+			ws.add(i, u.field, strings.TrimSpace(u.value), "no NACHA counterpart, dropped")
+		}
+	}
+}
+
+func serviceClass(entries []entry) int {
+	var credits, debits bool
+	for _, e := range entries {
+		if e.credit() {
+			credits = true
+		} else {
+			debits = true
+		}
+	}
+	switch {
+	case credits && debits:
+		return mixedBatch
+	case credits:
+		return creditBatch
+	}
+	return debitBatch
+}
+
+// NACHAToFile converts the PPD and CCD entries of a NACHA file to a file under header, which provides the originator ID, file creation
+// number and currency. Checking and savings account entries become credits and debits, return entries returned credits and debits (I and
+// J records) whose invalid data element ID is the CPA return reason of their R code and whose stored transaction type is their own
+// transaction type, as cadeft.SemanticRule expects. Batch and file control totals must match their
+// entries. NACHA does not carry the return account of entries, it is taken from WithReturnAccount. Prenotifications, notifications of
+// change, other entries and values too long for their CPA-005 field are reported as warnings. The resulting file is not validated.
+func NACHAToFile(data []byte, header *cadeft.FileHeader, opts ...Opt) (cadeft.File, []Warning, error) {
+	if header == nil {
+		return cadeft.File{}, nil, fmt.Errorf("file header is missing")
+	}
+	in, err := parse(data)
+	if err != nil {
+		return cadeft.File{}, nil, fmt.Errorf("failed to read NACHA file: %w", err)
+	}
+	cfg := newConfig(opts)
+	var ws warnings
+	h := *header
+	out := cadeft.NewFile(&h, nil)
+	if header.CurrencyCode != "USD" {
+		ws.add(-1, "currency_code", header.CurrencyCode, "NACHA amounts are in USD, amounts are copied as is")
+	}
+	if strings.TrimSpace(cfg.returnAccountNo) == "" {
+		ws.add(-1, "return_account_no", "", "not carried by NACHA, left blank")
+	}
+	i := -1
+	for _, b := range in.batches {
+		if b.companyID != strings.TrimSpace(header.OriginatorID) {
+			ws.add(-1, "originator_id", b.companyID, "company ID differs from the originator ID of the header, using "+header.OriginatorID)
+		}
+		longName := b.companyDiscretionary
+		if longName == "" {
+			longName = in.originName
+		}
+		if longName == "" {
+			longName = b.companyName
+		}
+		for _, e := range b.entries {
+			i++
+			if b.secCode != PPD && b.secCode != CCD {
+				ws.add(i, "sec_code", b.secCode, "unsupported standard entry class, skipped")
+				continue
+			}
+			if t, ok := newTransaction(cfg, &ws, i, b, e, longName); ok {
+				out.Txns = append(out.Txns, t)
+			}
+		}
+	}
+	return out, ws, nil
+}
+
+// newTransaction converts the entry at index i of a batch, entries without a CPA-005 counterpart are reported and skipped
+func newTransaction(cfg config, ws *warnings, i int, b batch, e entry, longName string) (cadeft.Transaction, bool) {
+	switch {
+	case e.txnCode/10 != 2 && e.txnCode/10 != 3:
+		ws.add(i, "txn_code", fmt.Sprint(e.txnCode), "only checking and savings account entries are converted, skipped")
+		return nil, false
+	case e.prenote():
+		ws.add(i, "txn_code", fmt.Sprint(e.txnCode), "prenotifications and zero dollar entries are not converted, skipped")
+		return nil, false
+	case e.returned() && e.ret == nil:
+		ws.add(i, "txn_code", fmt.Sprint(e.txnCode), "notifications of change are not converted, skipped")
+		return nil, false
+	}
+	if e.txnCode/10 == 3 {
+		ws.add(i, "txn_code", fmt.Sprint(e.txnCode), "savings account type has no CPA-005 counterpart")
+	}
+	if e.otherAddenda > 0 {
+		ws.add(i, "addenda", fmt.Sprint(e.otherAddenda), "addenda records dropped")
+	}
+	if !validABA(e.rdfi) {
+		ws.add(i, "rdfi", e.rdfi, "ABA check digit does not match")
+	}
+	date := b.effectiveDate
+	traceNo := fmt.Sprintf("%022s", e.traceNo)
+	accountNo := ws.fit(i, "account_no", e.accountNo, 12)
+	shortName := ws.fit(i, "short_name", b.companyName, 15)
+	name := ws.fit(i, "name", e.name, 30)
+	longName = ws.fit(i, "long_name", longName, 30)
+	opts := []cadeft.BaseTxnOpt{cadeft.WithCrossRefNo(e.individualID)}
+	if !e.returned() {
+		opts = append(opts, cadeft.WithSundryInfo(ws.fit(i, "sundry_info", strings.Join(e.paymentInfo, " "), 15)))
+		returnInstitutionID := cfg.returnInstitutionID
+		if returnInstitutionID == "" {
+			returnInstitutionID = withCheckDigit(b.odfi)
+		}
+		if e.credit() {
+			t := cadeft.NewCredit(cfg.txnType, e.amount, &date, e.rdfi, accountNo, traceNo, shortName, name, longName, returnInstitutionID, cfg.returnAccountNo, opts...)
+			return &t, true
+		}
+		t := cadeft.NewDebit(cfg.txnType, e.amount, &date, e.rdfi, accountNo, traceNo, shortName, name, longName, returnInstitutionID, cfg.returnAccountNo, opts...)
+		return &t, true
+	}
+
+	if len(e.paymentInfo) > 0 {
+		ws.add(i, "sundry_info", strings.Join(e.paymentInfo, " "), "returns carry no sundry information, dropped")
+	}
+	opts = append(opts, cadeft.WithStoredTransactionType(string(cfg.txnType)), cadeft.WithInvalidDataElementID(cpaReturnCode(ws, i, *e.ret)))
+	institutionID := withCheckDigit(e.ret.originalRDFI)
+	originalTraceNo := fmt.Sprintf("%022s", e.ret.originalTraceNo)
+	if e.credit() {
+		t := cadeft.NewCreditReturn(cfg.txnType, e.amount, &date, institutionID, accountNo, traceNo, shortName, name, longName, e.rdfi, cfg.returnAccountNo, originalTraceNo, opts...)
+		return &t, true
+	}
+	t := cadeft.NewDebitReturn(cfg.txnType, e.amount, &date, institutionID, accountNo, traceNo, shortName, name, longName, e.rdfi, cfg.returnAccountNo, originalTraceNo, opts...)
+	return &t, true
+}
+
+// cpaReturnCode returns the CPA return reason of a return addenda, the one written by FileToNACHA when it matches the R code
+func cpaReturnCode(ws *warnings, i int, r returnAddenda) string {
+	c, ok := LookupCPAReturnCode(r.reasonCode)
+	if !ok {
+		ws.add(i, "return_reason_code", r.reasonCode, "no CPA return reason, returned as "+DefaultCPAReturnCode)
+		return DefaultCPAReturnCode
+	}
+	if code, found := strings.CutPrefix(r.info, cpaInfoPrefix); found {
+		if rc, ok := LookupRCode(code); ok && rc.RCode == c.RCode {
+			return code
+		}
+		if _, known := LookupRCode(code); !known && c.RCode == DefaultRCode && len(code) == 3 && isDigits(code) {
+			return code
+		}
+	}
+	return c.CPACode
+}
+
+// withCheckDigit completes the 8 digit identification of a DFI with its ABA check digit
+func withCheckDigit(dfi string) string {
+	d, ok := checkDigit(dfi)
+	if !ok {
+		return dfi
+	}
+	return dfi[:8] + string(d)
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
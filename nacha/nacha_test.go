@@ -0,0 +1,249 @@
+package nacha
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moov-io/cadeft"
+	"github.com/stretchr/testify/require"
+)
+
+func testHeader() *cadeft.FileHeader {
+	return cadeft.NewFileHeader("0000099999", 12, cadeft.Ptr(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)), 86900, "USD")
+}
+
+func testFile() cadeft.File {
+	date := cadeft.Ptr(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+	return cadeft.NewFile(testHeader(), []cadeft.Transaction{
+		cadeft.Ptr(cadeft.NewCredit("450", 12345, date, "021000021", "123456789", "0000000011000010000001", "ACME", "JANE DOE", "ACME PAYROLL INC", "011000015", "987654", cadeft.WithCrossRefNo("INV-1001"), cadeft.WithSundryInfo("MARCH PAY"))),
+		cadeft.Ptr(cadeft.NewDebit("450", 2599, date, "091000019", "55555", "0000000011000010000002", "ACME", "JOHN ROE", "ACME PAYROLL INC", "011000015", "987654")),
+		cadeft.Ptr(cadeft.NewCreditReturn("450", 500, date, "021000021", "123456789", "0000000021000020000001", "ACME", "JANE DOE", "ACME PAYROLL INC", "011000015", "", "0000000011000010000001", cadeft.WithStoredTransactionType("450"), cadeft.WithInvalidDataElementID("905"))),
+		cadeft.Ptr(cadeft.NewDebitReturn("450", 2599, date, "091000019", "55555", "0000000091000010000001", "ACME", "JOHN ROE", "ACME PAYROLL INC", "011000015", "", "0000000011000010000002", cadeft.WithStoredTransactionType("450"), cadeft.WithInvalidDataElementID("906"))),
+	})
+}
+
+func TestLookupReturnCodes(t *testing.T) {
+	r := require.New(t)
+	c, ok := LookupRCode(" 901")
+	r.True(ok)
+	r.Equal("R01", c.RCode)
+	c, ok = LookupRCode("910")
+	r.True(ok)
+	r.Equal("R14", c.RCode)
+	_, ok = LookupRCode("906")
+	r.False(ok)
+
+	c, ok = LookupCPAReturnCode("r15")
+	r.True(ok)
+	r.Equal("910", c.CPACode)
+	_, ok = LookupCPAReturnCode("R05")
+	r.False(ok)
+}
+
+func TestFileToNACHA(t *testing.T) {
+	r := require.New(t)
+	out, warnings, err := FileToNACHA(testFile(), WithImmediateDestination("011000015", "FIRST BANK"))
+	r.NoError(err)
+
+	records := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	r.Len(records, 20)
+	for _, rec := range records {
+		r.Len(rec, recordLength)
+	}
+	r.Equal("101 0110000150000099999260309", records[0][:29])
+	r.Equal("FIRST BANK", field(records[0], 41, 63))
+	r.Equal("ACME PAYROLL INC", field(records[0], 64, 86))
+
+	// credits and debits are batched apart from returns
+	r.Equal("5200ACME            ACME PAYROLL INC    0000099999PPDPAYMENT", records[1][:60])
+	r.Equal("260310", records[1][69:75])
+	r.Equal("622021000021123456789        0000012345INV-1001       JANE DOE                1011000010000001", records[2])
+	r.Equal("705MARCH PAY", strings.TrimSpace(records[3][:83]))
+	r.Equal("627091000019", records[4][:12])
+	r.Equal("820000000300112000030000000025990000000123450000099999", records[5][:54])
+
+	r.Equal("5220", records[6][:4])
+	r.Equal("02100002", field(records[6], 80, 87))
+	r.Equal("621011000015123456789", records[7][:21])
+	r.Equal("799R02011000010000001      02100002CPA 905", strings.TrimSpace(records[8][:79]))
+	r.Equal("799R17011000010000002      09100001CPA 906", strings.TrimSpace(records[12][:79]))
+	r.Equal("9000003000002000000070", records[14][:22])
+	r.Equal(strings.Repeat("9", recordLength), records[19])
+
+	r.Equal([]Warning{
+		{Index: 0, Field: "return_account_no", Value: "987654", Reason: "no NACHA counterpart, dropped"},
+		{Index: 1, Field: "return_account_no", Value: "987654", Reason: "no NACHA counterpart, dropped"},
+		{Index: 3, Field: "invalid_data_element_id", Value: "906", Reason: "no NACHA return reason code, returned as R17"},
+	}, warnings)
+}
+
+func TestFileToNACHAWarningsAndErrors(t *testing.T) {
+	date := cadeft.Ptr(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+	header := cadeft.NewFileHeader("0000099999", 12, date, 86900, "CAD")
+
+	t.Run("warnings", func(t *testing.T) {
+		r := require.New(t)
+		f := cadeft.NewFile(header, []cadeft.Transaction{
+			cadeft.Ptr(cadeft.NewCredit("200", 100, date, "021000022", "42", "0001123450000000000001", "A VERY LONG SHORT", "PAYEE", "ACME", "011000015", "", cadeft.WithUserID("USER"))),
+			cadeft.Ptr(cadeft.NewCreditReverse("450", 100, date, "021000021", "42", "", "ACME", "PAYEE", "ACME", "011000015", "1", "0001123450000000000001")),
+		})
+		out, warnings, err := FileToNACHA(f, WithSECCode(CCD))
+		r.NoError(err)
+		r.Contains(string(out), "CCDPAYMENT")
+		r.Contains(string(out), "01100001"+"0000001\n")
+		r.Equal([]Warning{
+			{Index: -1, Field: "currency_code", Value: "CAD", Reason: "NACHA amounts are in USD, amounts are copied as is"},
+			{Index: 0, Field: "institution_id", Value: "021000022", Reason: "ABA check digit does not match"},
+			{Index: 0, Field: "item_trace_no", Value: "0001123450000000000001", Reason: "does not fit a NACHA trace number, replaced"},
+			{Index: 0, Field: "user_id", Value: "USER", Reason: "no NACHA counterpart, dropped"},
+			{Index: 0, Field: "short_name", Value: "A VERY LONG SHORT", Reason: "truncated to 16 characters"},
+			{Index: 0, Field: "txn_type", Value: "200", Reason: "no NACHA counterpart, dropped"},
+			{Index: 1, Field: "type", Value: "E", Reason: "reversals have no NACHA counterpart, skipped"},
+		}, warnings)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, _, err := FileToNACHA(cadeft.File{})
+		require.Error(t, err)
+		_, _, err = FileToNACHA(testFile(), WithSECCode("WEB"))
+		require.Error(t, err)
+		f := cadeft.NewFile(header, []cadeft.Transaction{
+			cadeft.Ptr(cadeft.NewCredit("450", 100, date, "000112345", "42", "", "ACME", "PAYEE", "ACME", "1234", "1")),
+		})
+		_, _, err = FileToNACHA(f)
+		require.ErrorContains(t, err, "return_institution_id")
+	})
+}
+
+func TestNACHARoundTrip(t *testing.T) {
+	r := require.New(t)
+	in := testFile()
+	out, _, err := FileToNACHA(in)
+	r.NoError(err)
+
+	back, warnings, err := NACHAToFile(out, testHeader(), WithReturnAccount("", "987654"))
+	r.NoError(err)
+	r.Empty(warnings)
+	r.Len(back.Txns, len(in.Txns))
+	r.Equal(in.Txns[0], back.Txns[0])
+	r.Equal(in.Txns[1], back.Txns[1])
+
+	// returns carry the original account NACHA does not, taken from WithReturnAccount
+	creditReturn := *in.Txns[2].(*cadeft.CreditReturn)
+	creditReturn.OriginalAccountNo = "987654"
+	r.Equal(&creditReturn, back.Txns[2])
+	debitReturn := *in.Txns[3].(*cadeft.DebitReturn)
+	debitReturn.OriginalAccountNo = "987654"
+	r.Equal(&debitReturn, back.Txns[3])
+	// the return reason comes back in the invalid data element ID and the stored transaction type is the original type
+	r.NoError(back.ValidateWith(cadeft.SemanticRule))
+
+	serialized, err := back.Create()
+	r.NoError(err)
+	r.NotEmpty(serialized)
+}
+
+func testNACHAFile(entries ...entry) file {
+	return file{
+		destination:     "011000015",
+		origin:          "0000099999",
+		created:         time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC),
+		fileIDModifier:  "A",
+		destinationName: "FIRST BANK",
+		originName:      "ACME PAYROLL INC",
+		batches: []batch{{
+			serviceClass:     mixedBatch,
+			companyName:      "ACME",
+			companyID:        "0000099999",
+			secCode:          PPD,
+			entryDescription: "PAYMENT",
+			effectiveDate:    time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+			odfi:             "01100001",
+			number:           1,
+			entries:          entries,
+		}},
+	}
+}
+
+func TestNACHAToFile(t *testing.T) {
+	t.Run("entries", func(t *testing.T) {
+		r := require.New(t)
+		in := testNACHAFile(
+			entry{txnCode: 22, rdfi: "021000021", accountNo: "123456789", amount: 12345, individualID: "INV-1001", name: "JANE DOE", traceNo: "011000010000001"},
+			entry{txnCode: 33, rdfi: "021000021", accountNo: "123456789", name: "PRENOTE", traceNo: "011000010000002"},
+			entry{txnCode: 27, rdfi: "021000022", accountNo: "12345678901234567", amount: 1000, name: "SAVER", traceNo: "011000010000003"},
+			entry{txnCode: 47, rdfi: "021000021", accountNo: "1", amount: 1000, name: "GL", traceNo: "011000010000004"},
+			entry{txnCode: 26, rdfi: "021000021", accountNo: "55555", amount: 2599, name: "JOHN ROE", traceNo: "091000010000001",
+				ret: &returnAddenda{reasonCode: "R10", originalTraceNo: "011000010000002", originalRDFI: "09100001"}},
+			entry{txnCode: 21, rdfi: "021000021", accountNo: "123456789", name: "NOC", traceNo: "021000020000001"},
+		)
+		f, warnings, err := NACHAToFile([]byte(in.build()), testHeader(), WithTxnType("200"))
+		r.NoError(err)
+		r.Len(f.Txns, 3)
+
+		credit := f.Txns[0].(*cadeft.Credit)
+		r.Equal(cadeft.TransactionType("200"), credit.TxnType)
+		r.Equal(int64(12345), credit.Amount)
+		r.Equal("021000021", credit.InstitutionID)
+		r.Equal("123456789", credit.PayeeAccountNo)
+		r.Equal("INV-1001", credit.CrossRefNo)
+		r.Equal("0000000011000010000001", credit.ItemTraceNo)
+		r.Equal("ACME", credit.OriginatorShortName)
+		r.Equal("ACME PAYROLL INC", credit.OriginatorLongName)
+		r.Equal("011000015", credit.ReturnInstitutionID)
+		r.Equal(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), *credit.DateFundsAvailable)
+
+		r.Equal("123456789012", f.Txns[1].GetAccountNo())
+
+		debitReturn := f.Txns[2].(*cadeft.DebitReturn)
+		r.Equal("916", debitReturn.InvalidDataElementID)
+		r.Equal(cadeft.TransactionType("200"), debitReturn.StoredTransactionType)
+		r.Equal("091000019", debitReturn.InstitutionID)
+		r.Equal("021000021", debitReturn.OriginalInstitutionID)
+		r.Equal("0000000011000010000002", debitReturn.OriginalItemTraceNo)
+
+		r.Equal([]Warning{
+			{Index: -1, Field: "return_account_no", Reason: "not carried by NACHA, left blank"},
+			{Index: 1, Field: "txn_code", Value: "33", Reason: "prenotifications and zero dollar entries are not converted, skipped"},
+			{Index: 2, Field: "rdfi", Value: "021000022", Reason: "ABA check digit does not match"},
+			{Index: 2, Field: "account_no", Value: "12345678901234567", Reason: "truncated to 12 characters"},
+			{Index: 3, Field: "txn_code", Value: "47", Reason: "only checking and savings account entries are converted, skipped"},
+			{Index: 5, Field: "txn_code", Value: "21", Reason: "notifications of change are not converted, skipped"},
+		}, warnings)
+	})
+
+	t.Run("savings and unknown return reason", func(t *testing.T) {
+		r := require.New(t)
+		in := testNACHAFile(entry{txnCode: 31, rdfi: "021000021", accountNo: "123456789", amount: 500, name: "JANE DOE", traceNo: "091000010000002",
+			ret: &returnAddenda{reasonCode: "R05", originalTraceNo: "011000010000001", originalRDFI: "02100002"}})
+		f, warnings, err := NACHAToFile([]byte(in.build()), testHeader(), WithReturnAccount("", "987654"))
+		r.NoError(err)
+		r.Equal(cadeft.ReturnCreditRecord, f.Txns[0].GetType())
+		r.Equal(DefaultCPAReturnCode, f.Txns[0].GetBaseTxn().ReturnReasonCode())
+		r.Equal([]Warning{
+			{Index: 0, Field: "txn_code", Value: "31", Reason: "savings account type has no CPA-005 counterpart"},
+			{Index: 0, Field: "return_reason_code", Value: "R05", Reason: "no CPA return reason, returned as 900"},
+		}, warnings)
+	})
+
+	t.Run("control totals", func(t *testing.T) {
+		in := testNACHAFile(entry{txnCode: 22, rdfi: "021000021", accountNo: "1", amount: 100, name: "A", traceNo: "011000010000001"})
+		data := in.build()
+		batchControl := strings.Split(data, "\n")[3]
+		r := require.New(t)
+		r.Equal(byte('8'), batchControl[0])
+		for name, tampered := range map[string]string{
+			"entry/addenda count": batchControl[:4] + "000002" + batchControl[10:],
+			"entry hash":          batchControl[:10] + "0000000001" + batchControl[20:],
+			"total credit amount": batchControl[:32] + "000000000101" + batchControl[44:],
+		} {
+			_, _, err := NACHAToFile([]byte(strings.Replace(data, batchControl, tampered, 1)), testHeader())
+			r.ErrorContains(err, name)
+		}
+		_, _, err := NACHAToFile([]byte(data[:200]), testHeader())
+		r.Error(err)
+		_, _, err = NACHAToFile([]byte(data), nil)
+		r.Error(err)
+	})
+}
@@ -0,0 +1,394 @@
+package nacha
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	recordLength   = 94
+	blockingFactor = 10
+	dateFormat     = "060102"
+)
+
+// file is the subset of a NACHA file converted to and from CPA-005
+type file struct {
+	destination     string
+	origin          string
+	created         time.Time
+	fileIDModifier  string
+	destinationName string
+	originName      string
+	batches         []batch
+}
+
+type batch struct {
+	serviceClass int
+	companyName  string
+	// companyDiscretionary is the company discretionary data, the long name of the originator
+	companyDiscretionary string
+	companyID            string
+	secCode              string
+	entryDescription     string
+	effectiveDate        time.Time
+	odfi                 string
+	number               int
+	entries              []entry
+}
+
+type entry struct {
+	txnCode int
+	// rdfi is the 8 digit receiving DFI identification followed by its check digit
+	rdfi          string
+	accountNo     string
+	amount        int64
+	individualID  string
+	name          string
+	discretionary string
+	traceNo       string
+	paymentInfo   []string
+	ret           *returnAddenda
+	// otherAddenda counts the addenda records that are neither payment related information nor returns
+	otherAddenda int
+}
+
+type returnAddenda struct {
+	reasonCode      string
+	originalTraceNo string
+	// originalRDFI is the 8 digit receiving DFI identification of the returned entry
+	originalRDFI string
+	info         string
+}
+
+// credit reports whether the transaction code is one of a credit, returns of credits included
+func (e entry) credit() bool {
+	return e.txnCode%10 >= 1 && e.txnCode%10 <= 4
+}
+
+// returned reports whether the transaction code is one of a return or notification of change
+func (e entry) returned() bool {
+	return e.txnCode%10 == 1 || e.txnCode%10 == 6
+}
+
+// prenote reports whether the transaction code is one of a prenotification or zero dollar entry
+func (e entry) prenote() bool {
+	d := e.txnCode % 10
+	return d == 3 || d == 4 || d == 8 || d == 9
+}
+
+// field returns the characters of a record between the 1 based positions start and end included
+func field(record string, start, end int) string {
+	return strings.TrimSpace(record[start-1 : end])
+}
+
+func numericField(record string, start, end int) (int64, error) {
+	s := field(record, start, end)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("positions %d-%d: %q is not numeric", start, end, s)
+	}
+	return n, nil
+}
+
+// splitRecords returns the records of a NACHA file written one per line or as a single stream of 94 character records
+func splitRecords(data []byte) ([]string, error) {
+	var records []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if len(line)%recordLength != 0 {
+			return nil, fmt.Errorf("line %d: records are %d characters long, got %d", n, recordLength, len(line))
+		}
+		for i := 0; i < len(line); i += recordLength {
+			records = append(records, line[i:i+recordLength])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read NACHA file: %w", err)
+	}
+	return records, nil
+}
+
+// parse reads a NACHA file and checks its batch and file control totals
+func parse(data []byte) (file, error) {
+	var f file
+	records, err := splitRecords(data)
+	if err != nil {
+		return f, err
+	}
+	var current *batch
+	var seenHeader, seenControl bool
+	var entries, hash, debits, credits int64
+	for n, r := range records {
+		var err error
+		switch r[0] {
+		case '1':
+			seenHeader = true
+			f.destination, f.origin = field(r, 4, 13), field(r, 14, 23)
+			f.fileIDModifier = field(r, 34, 34)
+			f.destinationName, f.originName = field(r, 41, 63), field(r, 64, 86)
+			if f.created, err = time.Parse(dateFormat+"1504", r[23:33]); err != nil {
+				f.created, err = time.Parse(dateFormat, r[23:29])
+			}
+		case '5':
+			if current != nil {
+				return f, fmt.Errorf("record %d: batch %d has no batch control record", n+1, current.number)
+			}
+			current, err = parseBatchHeader(r)
+			if err == nil {
+				f.batches = append(f.batches, *current)
+				current = &f.batches[len(f.batches)-1]
+			}
+		case '6':
+			if current == nil {
+				return f, fmt.Errorf("record %d: entry outside of a batch", n+1)
+			}
+			var e entry
+			if e, err = parseEntry(r); err == nil {
+				current.entries = append(current.entries, e)
+			}
+		case '7':
+			if current == nil || len(current.entries) == 0 {
+				return f, fmt.Errorf("record %d: addenda without an entry", n+1)
+			}
+			parseAddenda(r, &current.entries[len(current.entries)-1])
+		case '8':
+			if current == nil {
+				return f, fmt.Errorf("record %d: batch control outside of a batch", n+1)
+			}
+			var c control
+			if c, err = parseControl(r, 5, 11, 21, 33); err == nil {
+				err = current.control().check(c)
+				sum := current.control()
+				entries, hash, debits, credits = entries+sum.entries, hash+sum.hash, debits+sum.debits, credits+sum.credits
+			}
+			current = nil
+		case '9':
+			if seenControl {
+				// the records of 9's padding the last block
+				continue
+			}
+			seenControl = true
+			var c control
+			if c, err = parseControl(r, 14, 22, 32, 44); err == nil {
+				err = control{entries: entries, hash: hash % 1e10, debits: debits, credits: credits}.check(c)
+			}
+		default:
+			err = fmt.Errorf("unknown record type %q", r[0])
+		}
+		if err != nil {
+			return f, fmt.Errorf("record %d: %w", n+1, err)
+		}
+	}
+	if !seenHeader || !seenControl {
+		return f, fmt.Errorf("missing file header or file control record")
+	}
+	if current != nil {
+		return f, fmt.Errorf("batch %d has no batch control record", current.number)
+	}
+	return f, nil
+}
+
+func parseBatchHeader(r string) (*batch, error) {
+	b := &batch{
+		companyName:          field(r, 5, 20),
+		companyDiscretionary: field(r, 21, 40),
+		companyID:            field(r, 41, 50),
+		secCode:              field(r, 51, 53),
+		entryDescription:     field(r, 54, 63),
+		odfi:                 field(r, 80, 87),
+	}
+	serviceClass, err := numericField(r, 2, 4)
+	if err != nil {
+		return nil, fmt.Errorf("service class code: %w", err)
+	}
+	b.serviceClass = int(serviceClass)
+	if b.effectiveDate, err = time.Parse(dateFormat, r[69:75]); err != nil {
+		return nil, fmt.Errorf("effective entry date %q: %w", r[69:75], err)
+	}
+	number, err := numericField(r, 88, 94)
+	if err != nil {
+		return nil, fmt.Errorf("batch number: %w", err)
+	}
+	b.number = int(number)
+	return b, nil
+}
+
+func parseEntry(r string) (entry, error) {
+	e := entry{
+		rdfi:          r[3:12],
+		accountNo:     field(r, 13, 29),
+		individualID:  field(r, 40, 54),
+		name:          field(r, 55, 76),
+		discretionary: field(r, 77, 78),
+		traceNo:       field(r, 80, 94),
+	}
+	txnCode, err := numericField(r, 2, 3)
+	if err != nil {
+		return e, fmt.Errorf("transaction code: %w", err)
+	}
+	e.txnCode = int(txnCode)
+	if e.amount, err = numericField(r, 30, 39); err != nil {
+		return e, fmt.Errorf("amount: %w", err)
+	}
+	return e, nil
+}
+
+func parseAddenda(r string, e *entry) {
+	switch typeCode := field(r, 2, 3); typeCode {
+	case "05":
+		e.paymentInfo = append(e.paymentInfo, field(r, 4, 83))
+	case "99":
+		e.ret = &returnAddenda{
+			reasonCode:      field(r, 4, 6),
+			originalTraceNo: field(r, 7, 21),
+			originalRDFI:    field(r, 28, 35),
+			info:            field(r, 36, 79),
+		}
+	default:
+		// notifications of change and other addenda carry nothing converted to CPA-005
+		e.otherAddenda++
+	}
+}
+
+// control is the totals of a batch or file control record
+type control struct {
+	entries, hash, debits, credits int64
+}
+
+func parseControl(r string, entriesAt, hashAt, debitsAt, creditsAt int) (control, error) {
+	var c control
+	var err error
+	if c.entries, err = numericField(r, entriesAt, hashAt-1); err != nil {
+		return c, fmt.Errorf("entry/addenda count: %w", err)
+	}
+	if c.hash, err = numericField(r, hashAt, hashAt+9); err != nil {
+		return c, fmt.Errorf("entry hash: %w", err)
+	}
+	if c.debits, err = numericField(r, debitsAt, debitsAt+11); err != nil {
+		return c, fmt.Errorf("total debit amount: %w", err)
+	}
+	if c.credits, err = numericField(r, creditsAt, creditsAt+11); err != nil {
+		return c, fmt.Errorf("total credit amount: %w", err)
+	}
+	return c, nil
+}
+
+func (c control) check(got control) error {
+	switch {
+	case c.entries != got.entries:
+		return fmt.Errorf("entry/addenda count %d does not match the %d records", got.entries, c.entries)
+	case c.hash != got.hash:
+		return fmt.Errorf("entry hash %d does not match %d", got.hash, c.hash)
+	case c.debits != got.debits:
+		return fmt.Errorf("total debit amount %d does not match %d", got.debits, c.debits)
+	case c.credits != got.credits:
+		return fmt.Errorf("total credit amount %d does not match %d", got.credits, c.credits)
+	}
+	return nil
+}
+
+// control computes the totals of the batch control record
+func (b batch) control() control {
+	var c control
+	for _, e := range b.entries {
+		c.entries++
+		if e.ret != nil {
+			c.entries++
+		}
+		c.entries += int64(len(e.paymentInfo) + e.otherAddenda)
+		routing, _ := strconv.ParseInt(e.rdfi[:8], 10, 64)
+		c.hash += routing
+		if e.credit() {
+			c.credits += e.amount
+		} else {
+			c.debits += e.amount
+		}
+	}
+	c.hash %= 1e10
+	return c
+}
+
+// build writes the file with its control records and the padding of its last block
+func (f file) build() string {
+	var records []string
+	records = append(records, fmt.Sprintf("101 %-9.9s%-10.10s%s%s%-1.1s094101%-23.23s%-23.23s%8s",
+		f.destination, f.origin, f.created.Format(dateFormat), f.created.Format("1504"), f.fileIDModifier, f.destinationName, f.originName, ""))
+	var total control
+	for _, b := range f.batches {
+		records = append(records, fmt.Sprintf("5%03d%-16.16s%-20.20s%-10.10s%-3.3s%-10.10s%6s%s%3s1%-8.8s%07d",
+			b.serviceClass, b.companyName, b.companyDiscretionary, b.companyID, b.secCode, b.entryDescription, "", b.effectiveDate.Format(dateFormat), "", b.odfi, b.number))
+		for _, e := range b.entries {
+			addenda := 0
+			if e.ret != nil || len(e.paymentInfo) > 0 {
+				addenda = 1
+			}
+			records = append(records, fmt.Sprintf("6%02d%-9.9s%-17.17s%010d%-15.15s%-22.22s%-2.2s%d%-15.15s",
+				e.txnCode, e.rdfi, e.accountNo, e.amount, e.individualID, e.name, e.discretionary, addenda, e.traceNo))
+			for i, info := range e.paymentInfo {
+				records = append(records, fmt.Sprintf("705%-80.80s%04d%-7.7s", info, i+1, lastDigits(e.traceNo, 7)))
+			}
+			if r := e.ret; r != nil {
+				records = append(records, fmt.Sprintf("799%-3.3s%-15.15s%6s%-8.8s%-44.44s%-15.15s",
+					r.reasonCode, r.originalTraceNo, "", r.originalRDFI, r.info, e.traceNo))
+			}
+		}
+		c := b.control()
+		records = append(records, fmt.Sprintf("8%03d%06d%010d%012d%012d%-10.10s%19s%6s%-8.8s%07d",
+			b.serviceClass, c.entries, c.hash, c.debits, c.credits, b.companyID, "", "", b.odfi, b.number))
+		total.entries, total.hash, total.debits, total.credits = total.entries+c.entries, total.hash+c.hash, total.debits+c.debits, total.credits+c.credits
+	}
+	blocks := (len(records) + 1 + blockingFactor - 1) / blockingFactor
+	records = append(records, fmt.Sprintf("9%06d%06d%08d%010d%012d%012d%39s",
+		len(f.batches), blocks, total.entries, total.hash%1e10, total.debits, total.credits, ""))
+	for len(records)%blockingFactor != 0 {
+		records = append(records, strings.Repeat("9", recordLength))
+	}
+	return strings.Join(records, "\n") + "\n"
+}
+
+func lastDigits(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[len(s)-n:]
+}
+
+// checkDigit computes the check digit of the first 8 digits of an ABA routing number
+func checkDigit(routing string) (byte, bool) {
+	if len(routing) < 8 || !isDigits(routing[:8]) {
+		return 0, false
+	}
+	weights := [8]int{3, 7, 1, 3, 7, 1, 3, 7}
+	sum := 0
+	for i, w := range weights {
+		sum += int(routing[i]-'0') * w
+	}
+	return byte('0' + (10-sum%10)%10), true
+}
+
+// validABA reports whether routing is 9 digits ending with the ABA check digit of the first 8
+func validABA(routing string) bool {
+	d, ok := checkDigit(routing)
+	return ok && len(routing) == 9 && isDigits(routing) && routing[8] == d
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
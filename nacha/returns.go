@@ -0,0 +1,57 @@
+package nacha
+
+import "strings"
+
+// ReturnCode pairs a NACHA return reason code with the CPA return reason code closest to it
+type ReturnCode struct {
+	RCode       string `json:"r_code"`
+	CPACode     string `json:"cpa_code"`
+	Description string `json:"description"`
+}
+
+// returnCodes maps every supported R code to a CPA return reason, the first R code of a CPA reason is the one it converts to
+var returnCodes = []ReturnCode{
+	{RCode: "R01", CPACode: "901", Description: "Insufficient funds"},
+	{RCode: "R02", CPACode: "905", Description: "Account closed"},
+	{RCode: "R03", CPACode: "902", Description: "No account/unable to locate account"},
+	{RCode: "R04", CPACode: "912", Description: "Invalid account number"},
+	{RCode: "R07", CPACode: "917", Description: "Authorization revoked by customer"},
+	{RCode: "R08", CPACode: "903", Description: "Payment stopped"},
+	{RCode: "R09", CPACode: "908", Description: "Uncollected funds"},
+	{RCode: "R10", CPACode: "916", Description: "Customer advises not authorized"},
+	{RCode: "R14", CPACode: "910", Description: "Representative payee deceased"},
+	{RCode: "R15", CPACode: "910", Description: "Beneficiary or account holder deceased"},
+	{RCode: "R16", CPACode: "911", Description: "Account frozen"},
+	{RCode: "R17", CPACode: "900", Description: "File record edit criteria"},
+	{RCode: "R20", CPACode: "907", Description: "Non-transaction account"},
+	{RCode: "R23", CPACode: "915", Description: "Credit entry refused by receiver"},
+	{RCode: "R29", CPACode: "919", Description: "Corporate customer advises not authorized"},
+}
+
+// DefaultRCode is the return reason of CPA returns whose reason has no NACHA counterpart
+const DefaultRCode = "R17"
+
+// DefaultCPAReturnCode is the return reason of NACHA returns whose R code has no CPA counterpart
+const DefaultCPAReturnCode = "900"
+
+// LookupRCode returns the NACHA return reason code a CPA return reason code converts to
+func LookupRCode(cpaCode string) (ReturnCode, bool) {
+	cpaCode = strings.TrimSpace(cpaCode)
+	for _, c := range returnCodes {
+		if c.CPACode == cpaCode {
+			return c, true
+		}
+	}
+	return ReturnCode{}, false
+}
+
+// LookupCPAReturnCode returns the CPA return reason code a NACHA return reason code converts to
+func LookupCPAReturnCode(rCode string) (ReturnCode, bool) {
+	rCode = strings.ToUpper(strings.TrimSpace(rCode))
+	for _, c := range returnCodes {
+		if c.RCode == rCode {
+			return c, true
+		}
+	}
+	return ReturnCode{}, false
+}
@@ -0,0 +1,300 @@
+package cadeft
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// WithRawPreservation keeps the text every record was parsed from along with blank and unrecognized lines.
+// File.Create then writes an unmodified File back byte for byte, only records edited since they were read are rebuilt.
+func WithRawPreservation() ReadOpt {
+	return func(rc *readConfig) {
+		rc.preserve = true
+	}
+}
+
+// rawRecord is the text a record was parsed from in preservation mode
+type rawRecord struct {
+	// line is the line holding the record as read, without its line ending
+	line string
+	// text is the record itself, the whole line of a header or footer and the segment of a transaction
+	text string
+	// fingerprint is the record serialized to JSON right after parsing, a different serialization means the record was edited
+	fingerprint []byte
+}
+
+func newRawRecord(line, text string, record any) *rawRecord {
+	fingerprint, err := json.Marshal(record)
+	if err != nil {
+		return nil
+	}
+	return &rawRecord{line: line, text: text, fingerprint: fingerprint}
+}
+
+// unchanged reports whether record still holds the values it was parsed with
+func (r *rawRecord) unchanged(record any) bool {
+	if r == nil {
+		return false
+	}
+	fingerprint, err := json.Marshal(record)
+	return err == nil && bytes.Equal(fingerprint, r.fingerprint)
+}
+
+// RawLine returns the line the transaction was read from when the file was read WithRawPreservation
+func (b BaseTxn) RawLine() string {
+	if b.raw == nil {
+		return ""
+	}
+	return b.raw.line
+}
+
+// RawSegment returns the 240 character segment the transaction was parsed from when the file was read WithRawPreservation
+func (b BaseTxn) RawSegment() string {
+	if b.raw == nil {
+		return ""
+	}
+	return b.raw.text
+}
+
+func (b *BaseTxn) setRaw(r *rawRecord) {
+	b.raw = r
+}
+
+// RawLine returns the line the header was parsed from when the file was read WithRawPreservation
+func (fh FileHeader) RawLine() string {
+	if fh.raw == nil {
+		return ""
+	}
+	return fh.raw.line
+}
+
+// RawLine returns the line the footer was parsed from when the file was read WithRawPreservation
+func (ff FileFooter) RawLine() string {
+	if ff.raw == nil {
+		return ""
+	}
+	return ff.raw.line
+}
+
+// UnrecognizedLines returns the lines that are not a header, transaction or footer record when the file was read WithRawPreservation
+func (f File) UnrecognizedLines() []string {
+	if f.layout == nil {
+		return nil
+	}
+	var lines []string
+	for _, l := range f.layout.lines {
+		if l.recordType == "" {
+			lines = append(lines, l.raw)
+		}
+	}
+	return lines
+}
+
+// fileLayout is every line of a file read in preservation mode in the order they were read
+type fileLayout struct {
+	lines []rawLine
+	// recordHeader is the header as parsed, transaction lines are rebuilt when its originator or file creation number change
+	recordHeader *RecordHeader
+	txnCount     int
+}
+
+type rawLine struct {
+	// recordType is empty for blank and unrecognized lines
+	recordType RecordType
+	// raw is the line as read and text its normalized form the records were parsed from
+	raw, text string
+	eol       string
+	record    *rawRecord
+	segments  []rawSegment
+}
+
+type rawSegment struct {
+	// txn is the index of the transaction in File.Txns, -1 for filler
+	txn    int
+	text   string
+	record *rawRecord
+}
+
+type rawRecorder interface {
+	setRaw(*rawRecord)
+}
+
+// preserveLine records a line just read, txnStart is the index of the first transaction it was parsed into
+func (r *Reader) preserveLine(recordType RecordType, raw, line, eol string, txnStart int) {
+	l := rawLine{recordType: recordType, raw: raw, text: line, eol: eol}
+	switch recordType {
+	case HeaderRecord:
+		l.record = newRawRecord(raw, line, r.File.Header)
+		r.File.Header.raw = l.record
+		r.File.layout.recordHeader = Ptr(r.File.Header.RecordHeader)
+	case FooterRecord:
+		l.record = newRawRecord(raw, line, r.File.Footer)
+		r.File.Footer.raw = l.record
+	case CreditRecord, DebitRecord, CreditReverseRecord, DebitReverseRecord, ReturnCreditRecord, ReturnDebitRecord:
+		body := []rune(line)[commonRecordDataLength:]
+		txn := txnStart
+		for i := 0; i+segmentLength <= len(body); i += segmentLength {
+			seg := rawSegment{txn: -1, text: string(body[i : i+segmentLength])}
+			if !isFillerString(seg.text) {
+				t := r.File.Txns[txn]
+				seg.txn, seg.record = txn, newRawRecord(raw, seg.text, t)
+				if rec, ok := t.(rawRecorder); ok {
+					rec.setRaw(seg.record)
+				}
+				txn++
+			}
+			l.segments = append(l.segments, seg)
+		}
+		r.File.layout.txnCount = txn
+	case NoticeOfChangeRecord, NoticeOfChangeHeader, NoticeOfChangeFooter:
+		l.recordType = ""
+	}
+	r.File.layout.lines = append(r.File.layout.lines, l)
+}
+
+// matches reports whether f still has the records of the file it was read from, possibly edited, so that it can be written line by line
+func (l *fileLayout) matches(f File) bool {
+	if l == nil || l.recordHeader == nil || f.Header == nil || len(f.Txns) != l.txnCount {
+		return false
+	}
+	for _, line := range l.lines {
+		for _, seg := range line.segments {
+			if seg.txn >= 0 && f.Txns[seg.txn].GetType() != line.recordType {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// createPreserved writes every line as it was read unless a record on it was edited,
+// edited lines are rebuilt from the normalized text of their unedited records and filler is kept as is
+func (f *File) createPreserved() (string, error) {
+	l := f.layout
+	headerEdited := !f.Header.raw.unchanged(f.Header)
+	// a header or footer superseded by a later one of the same type is not part of the file anymore and is written as is
+	superseded := func(line rawLine, current *rawRecord) bool {
+		return current != nil && line.record != current
+	}
+	prefixEdited := f.Header.OriginatorID != l.recordHeader.OriginatorID || f.Header.FileCreationNum != l.recordHeader.FileCreationNum
+	var sb strings.Builder
+	recordCount := 0
+	for _, line := range l.lines {
+		if line.recordType != "" {
+			recordCount++
+		}
+		switch line.recordType {
+		case "":
+			sb.WriteString(line.raw)
+		case HeaderRecord:
+			if superseded(line, f.Header.raw) || !headerEdited {
+				sb.WriteString(line.raw)
+				break
+			}
+			header := *f.Header
+			header.recordCount = int64(recordCount)
+			serializedHeader, err := header.buildHeader(recordCount)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(serializedHeader)
+		case FooterRecord:
+			if f.Footer == nil {
+				f.Footer = NewFileFooter(RecordHeader{
+					RecordType:      FooterRecord,
+					OriginatorID:    f.Header.OriginatorID,
+					FileCreationNum: f.Header.FileCreationNum,
+				}, f.Txns)
+			}
+			if superseded(line, f.Footer.raw) || f.Footer.raw.unchanged(f.Footer) {
+				sb.WriteString(line.raw)
+				break
+			}
+			f.Footer.recordCount = int64(recordCount)
+			serializedFooter, err := f.Footer.Build()
+			if err != nil {
+				return "", fmt.Errorf("failed to build footer: %w", err)
+			}
+			sb.WriteString(serializedFooter)
+		case CreditRecord, DebitRecord, CreditReverseRecord, DebitReverseRecord, ReturnCreditRecord, ReturnDebitRecord:
+			serializedLine, err := f.buildPreservedTxnLine(line, recordCount, prefixEdited)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(serializedLine)
+		case NoticeOfChangeRecord, NoticeOfChangeHeader, NoticeOfChangeFooter:
+			return "", newError(msgUnexpectedRecord, line.recordType)
+		}
+		sb.WriteString(line.eol)
+	}
+	return sb.String(), nil
+}
+
+func (f *File) buildPreservedTxnLine(line rawLine, recordCount int, prefixEdited bool) (string, error) {
+	edited := prefixEdited
+	segments := make([]string, 0, len(line.segments))
+	for _, seg := range line.segments {
+		if seg.txn < 0 {
+			segments = append(segments, seg.text)
+			continue
+		}
+		txn := f.Txns[seg.txn]
+		if txn.GetBaseTxn().raw == seg.record && seg.record.unchanged(txn) {
+			segments = append(segments, seg.text)
+			continue
+		}
+		serializedTxn, err := txn.Build()
+		if err != nil {
+			return "", fmt.Errorf("transaction[%d]: failed to build transaction: %w", seg.txn, err)
+		}
+		segments = append(segments, serializedTxn)
+		edited = true
+	}
+	if !edited {
+		return line.raw, nil
+	}
+	prefix := string([]rune(line.text)[:commonRecordDataLength])
+	if prefixEdited {
+		var err error
+		recordHeader := RecordHeader{
+			RecordType:      line.recordType,
+			OriginatorID:    f.Header.OriginatorID,
+			FileCreationNum: f.Header.FileCreationNum,
+			recordCount:     int64(recordCount),
+		}
+		if prefix, err = recordHeader.buildRecordHeader(); err != nil {
+			return "", fmt.Errorf("failed to create Txn RecordHeader: %w", err)
+		}
+	}
+	return prefix + strings.Join(segments, ""), nil
+}
+
+// cutLineEnding splits the line ending off a line read with scanRawLines
+func cutLineEnding(line string) (string, string) {
+	if s, ok := strings.CutSuffix(line, "\r\n"); ok {
+		return s, "\r\n"
+	}
+	if s, ok := strings.CutSuffix(line, "\n"); ok {
+		return s, "\n"
+	}
+	if s, ok := strings.CutSuffix(line, "\r"); ok {
+		return s, "\r"
+	}
+	return line, ""
+}
+
+// scanRawLines is bufio.ScanLines keeping the line endings so that they can be written back
+func scanRawLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i+1], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
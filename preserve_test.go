@@ -0,0 +1,109 @@
+package cadeft
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawPreservation(t *testing.T) {
+	sample, err := os.ReadFile("sample_files/CO14821.txt")
+	require.NoError(t, err)
+	lines := strings.Split(string(sample), "\r\n")
+	require.Len(t, lines, 4)
+
+	// content the parser drops: text in the filler of the first credit, a blank line, an unknown record and a missing final line ending
+	credit := []rune(lines[1])
+	copy(credit[commonRecordDataLength+205:], []rune("AUDIT NOTE"))
+	input := strings.Join([]string{lines[0], string(credit), "", "X unknown record", lines[2], lines[3]}, "\r\n")
+
+	read := func(t *testing.T) File {
+		t.Helper()
+		file, err := NewReader(strings.NewReader(input), WithRawPreservation()).ReadFile()
+		require.NoError(t, err)
+		return file
+	}
+
+	t.Run("unmodified file is written byte for byte", func(t *testing.T) {
+		r := require.New(t)
+		file := read(t)
+		out, err := file.Create()
+		r.NoError(err)
+		r.Equal(input, out)
+
+		plain, err := NewReader(strings.NewReader(input)).ReadFile()
+		r.NoError(err)
+		out, err = plain.Create()
+		r.NoError(err)
+		r.NotEqual(input, out)
+	})
+
+	t.Run("raw text of records", func(t *testing.T) {
+		r := require.New(t)
+		file := read(t)
+		r.Equal(lines[0], file.Header.RawLine())
+		r.Equal(lines[3], file.Footer.RawLine())
+		r.Equal(string(credit), file.Txns[0].GetBaseTxn().RawLine())
+		r.Equal(string(credit[commonRecordDataLength:commonRecordDataLength+segmentLength]), file.Txns[0].GetBaseTxn().RawSegment())
+		r.Contains(file.Txns[0].GetBaseTxn().RawSegment(), "AUDIT NOTE")
+		r.Equal([]string{"", "X unknown record"}, file.UnrecognizedLines())
+
+		plain, err := NewReader(strings.NewReader(input)).ReadFile()
+		r.NoError(err)
+		r.Empty(plain.Txns[0].GetBaseTxn().RawSegment())
+		r.Nil(plain.UnrecognizedLines())
+	})
+
+	t.Run("only edited records are rebuilt", func(t *testing.T) {
+		r := require.New(t)
+		file := read(t)
+		edited := file.Txns[7].(*Debit)
+		edited.Amount++
+		out, err := file.Create()
+		r.NoError(err)
+
+		outLines := strings.Split(out, "\r\n")
+		inLines := strings.Split(input, "\r\n")
+		r.Len(outLines, len(inLines))
+		for i := range inLines {
+			if i != 4 {
+				r.Equal(inLines[i], outLines[i], "line %d", i)
+			}
+		}
+		debitLine := []rune(outLines[4])
+		r.Equal(string([]rune(inLines[4])[:commonRecordDataLength+segmentLength]), string(debitLine[:commonRecordDataLength+segmentLength]))
+		rebuilt, err := edited.Build()
+		r.NoError(err)
+		r.Equal(rebuilt, string(debitLine[commonRecordDataLength+segmentLength:commonRecordDataLength+2*segmentLength]))
+		r.Equal(string([]rune(inLines[4])[commonRecordDataLength+2*segmentLength:]), string(debitLine[commonRecordDataLength+2*segmentLength:]))
+	})
+
+	t.Run("header edits rebuild the record headers of transaction lines", func(t *testing.T) {
+		r := require.New(t)
+		file := read(t)
+		file.Header.FileCreationNum = 2
+		out, err := file.Create()
+		r.NoError(err)
+		outLines := strings.Split(out, "\r\n")
+		r.Equal("A000000001", outLines[0][:10])
+		r.Equal("0002", outLines[0][20:24])
+		r.Equal("0002", outLines[1][20:24])
+		r.Contains(outLines[1], "AUDIT NOTE")
+		r.Equal("0002", outLines[4][20:24])
+		r.Equal("X unknown record", outLines[3])
+		// the footer was not edited so it is kept as read
+		r.Equal(lines[3], outLines[5])
+	})
+
+	t.Run("added transactions rebuild the whole file", func(t *testing.T) {
+		r := require.New(t)
+		file := read(t)
+		file.Txns = append(file.Txns, Ptr(*file.Txns[0].(*Credit)))
+		out, err := file.Create()
+		r.NoError(err)
+		r.NotContains(out, "X unknown record")
+		r.NotContains(out, "\r\n")
+	})
+}
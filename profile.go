@@ -0,0 +1,259 @@
+package cadeft
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// anyOriginator is the AllowedCurrencies key applied to originators that are not listed
+const anyOriginator = "*"
+
+// ValidationProfile is a named set of requirements a direct clearer layers on top of the CPA-005 spec.
+// A ValidationProfile is a Rule so it can be passed to File.ValidateWith, or registered and referred to by name with WithProfile.
+type ValidationProfile struct {
+	Name string `json:"name" yaml:"name"`
+	// AllowedCurrencies lists the currencies each originator may send keyed by originator ID, the "*" key applies to originators that are not listed
+	AllowedCurrencies map[string][]string `json:"allowed_currencies,omitempty" yaml:"allowed_currencies,omitempty"`
+	// Fields sets requirements on header and transaction fields keyed by their json name
+	Fields map[string]FieldRequirement `json:"fields,omitempty" yaml:"fields,omitempty"`
+	// MaxItems is the maximum number of transactions in a file, 0 means no limit
+	MaxItems int `json:"max_items,omitempty" yaml:"max_items,omitempty"`
+	// MaxAmount is the maximum amount in cents of a single transaction, 0 means no limit
+	MaxAmount int64 `json:"max_amount,omitempty" yaml:"max_amount,omitempty"`
+	// DateWindows, when set, are checked with File.ValidateDateWindows
+	DateWindows *DateWindowRules `json:"date_windows,omitempty" yaml:"date_windows,omitempty"`
+
+	patterns map[string]*regexp.Regexp
+}
+
+// FieldRequirement restricts the content of a single field. Empty fields are only checked by Required.
+type FieldRequirement struct {
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+	// Forbidden rejects any content in the field, for example a direct clearer that does not accept sundry information
+	Forbidden bool `json:"forbidden,omitempty" yaml:"forbidden,omitempty"`
+	// Pattern is a regular expression the field must match
+	Pattern   string   `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Values    []string `json:"values,omitempty" yaml:"values,omitempty"`
+	MaxLength int      `json:"max_length,omitempty" yaml:"max_length,omitempty"`
+}
+
+// profileConfig is the layout of a profile configuration file
+type profileConfig struct {
+	Profiles []ValidationProfile `json:"profiles" yaml:"profiles"`
+}
+
+// LoadValidationProfiles reads the profiles defined in a JSON file, when path has a .json extension, or a YAML file.
+// Every profile is checked for unknown fields and invalid patterns, but not registered.
+func LoadValidationProfiles(path string) ([]ValidationProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, newError(msgReadProfiles, err)
+	}
+	var config profileConfig
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &config)
+	} else {
+		err = yaml.Unmarshal(data, &config)
+	}
+	if err != nil {
+		return nil, newError(msgDecodeProfiles, err)
+	}
+	for i := range config.Profiles {
+		if err := config.Profiles[i].compile(); err != nil {
+			return nil, err
+		}
+	}
+	return config.Profiles, nil
+}
+
+var (
+	profileMu          sync.RWMutex
+	validationProfiles = map[string]ValidationProfile{}
+)
+
+// RegisterValidationProfile stores a profile so that it can be referred to by name with WithProfile
+func RegisterValidationProfile(p ValidationProfile) error {
+	if p.Name == "" {
+		return newError(msgProfileNoName, ErrInvalidValidationProfile)
+	}
+	if err := p.compile(); err != nil {
+		return err
+	}
+	profileMu.Lock()
+	defer profileMu.Unlock()
+	validationProfiles[p.Name] = p
+	return nil
+}
+
+// LookupValidationProfile returns the profile registered under name
+func LookupValidationProfile(name string) (ValidationProfile, bool) {
+	profileMu.RLock()
+	defer profileMu.RUnlock()
+	p, ok := validationProfiles[name]
+	return p, ok
+}
+
+// compile checks that every field requirement refers to a known field and compiles its pattern
+func (p *ValidationProfile) compile() error {
+	p.patterns = make(map[string]*regexp.Regexp)
+	for name, req := range p.Fields {
+		if !isProfileField(name) {
+			return newError(msgProfileUnknownField, ErrInvalidValidationProfile, p.Name, name)
+		}
+		if req.Pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile(req.Pattern)
+		if err != nil {
+			return newError(msgProfileFieldError, ErrInvalidValidationProfile, p.Name, name, err)
+		}
+		p.patterns[name] = re
+	}
+	return nil
+}
+
+func (p ValidationProfile) ValidateHeader(header FileHeader) error {
+	var errs ValidationErrors
+	allowed, ok := p.AllowedCurrencies[header.OriginatorID]
+	if !ok {
+		allowed, ok = p.AllowedCurrencies[anyOriginator]
+	}
+	if ok && !slices.Contains(allowed, header.CurrencyCode) {
+		errs = append(errs, NewFieldError(header, "CurrencyCode", "currency", strings.Join(allowed, " "), ErrInvalidCurrencyCode))
+	}
+	errs = append(errs, p.validateFields(header)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func (p ValidationProfile) ValidateTxn(txn Transaction) error {
+	var errs ValidationErrors
+	if p.MaxAmount > 0 {
+		if err := MaxAmountRule(p.MaxAmount).ValidateTxn(txn); err != nil {
+			errs = appendValidationErrors(errs, err, nil, txn.GetType())
+		}
+	}
+	errs = append(errs, p.validateFields(txn)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func (p ValidationProfile) ValidateFile(file File) error {
+	var errs ValidationErrors
+	if p.MaxItems > 0 && len(file.Txns) > p.MaxItems {
+		errs = append(errs, &ValidationError{
+			Rule:  "max_items",
+			Param: strconv.Itoa(p.MaxItems),
+			Value: len(file.Txns),
+			Err:   newError(msgProfileTooManyItems, ErrTooManyItems, len(file.Txns), p.Name, p.MaxItems),
+		})
+	}
+	// a missing creation date is reported by the validation of the header
+	if p.DateWindows != nil && file.Header != nil && file.Header.CreationDate != nil {
+		for i, t := range file.Txns {
+			if err := p.DateWindows.check(*file.Header.CreationDate, t); err != nil {
+				window, _ := p.DateWindows.windowFor(t.GetType())
+				v := NewFieldError(t, txnDateField(t.GetType()), "date_window", fmt.Sprintf("%d %d", window.DaysBefore, window.DaysAfter), err)
+				v.TxnIndex = Ptr(i)
+				errs = append(errs, v)
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// validateFields applies the field requirements of the profile to the fields record has
+func (p ValidationProfile) validateFields(record any) ValidationErrors {
+	var errs ValidationErrors
+	rv := reflect.Indirect(reflect.ValueOf(record))
+	names := make([]string, 0, len(p.Fields))
+	for name := range p.Fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		req := p.Fields[name]
+		field, value, ok := jsonFieldValue(rv, name)
+		if !ok {
+			continue
+		}
+		fail := func(rule, param string) {
+			errs = append(errs, NewFieldError(record, field, rule, param, fieldSentinel(field, rule)))
+		}
+		switch {
+		case value == "":
+			if req.Required {
+				fail("required", "")
+			}
+		case req.Forbidden:
+			fail("forbidden", "")
+		case req.MaxLength > 0 && len([]rune(value)) > req.MaxLength:
+			fail("max", strconv.Itoa(req.MaxLength))
+		case len(req.Values) > 0 && !slices.Contains(req.Values, value):
+			fail("oneof", strings.Join(req.Values, " "))
+		case req.Pattern != "" && !p.pattern(name).MatchString(value):
+			fail("pattern", req.Pattern)
+		}
+	}
+	return errs
+}
+
+func (p ValidationProfile) pattern(name string) *regexp.Regexp {
+	if re, ok := p.patterns[name]; ok {
+		return re
+	}
+	// profiles built without RegisterValidationProfile or LoadValidationProfiles are compiled on use, an invalid pattern never matches
+	re, err := regexp.Compile(p.Fields[name].Pattern)
+	if err != nil {
+		return regexp.MustCompile(`$^`)
+	}
+	return re
+}
+
+// profileRecords are the records whose fields can be restricted by a profile
+var profileRecords = []any{FileHeader{}, Credit{}, Debit{}, CreditReverse{}, DebitReverse{}, CreditReturn{}, DebitReturn{}}
+
+func isProfileField(name string) bool {
+	for _, r := range profileRecords {
+		if _, _, ok := jsonFieldValue(reflect.ValueOf(r), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonFieldValue returns the Go name and the content as a string of the string or integer field of rv tagged with the json name
+func jsonFieldValue(rv reflect.Value, name string) (string, string, bool) {
+	sf, ok := fieldByJSONName(rv.Type(), name)
+	if !ok {
+		return "", "", false
+	}
+	fv := rv.FieldByIndex(sf.Index)
+	switch fv.Kind() {
+	case reflect.String:
+		return sf.Name, strings.TrimSpace(fv.String()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if fv.Int() == 0 {
+			return sf.Name, "", true
+		}
+		return sf.Name, strconv.FormatInt(fv.Int(), 10), true
+	}
+	return "", "", false
+}
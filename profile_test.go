@@ -0,0 +1,124 @@
+package cadeft
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationProfile(t *testing.T) {
+	r := require.New(t)
+	profiles, err := LoadValidationProfiles(filepath.Join("testdata", "profiles.yaml"))
+	r.NoError(err)
+	r.Len(profiles, 1)
+	r.Equal("example-bank", profiles[0].Name)
+	r.Equal(DateWindow{DaysBefore: 5, DaysAfter: 30}, profiles[0].DateWindows.Transactions)
+	r.NoError(RegisterValidationProfile(profiles[0]))
+
+	date := time.Date(2023, time.October, 2, 0, 0, 0, 0, time.UTC)
+	newFile := func(originatorID, currency, communicationArea string, txns ...Transaction) File {
+		header := NewFileHeader(originatorID, 1, &date, 12345, currency, WithDirectClearerCommunicationArea(communicationArea))
+		return NewFile(header, txns)
+	}
+	credit := NewCredit("450", 500000, &date, "123456789", "123456789012", "", "SHORT-NAME", "ACME CORP", "LONG-NAME", "123456789", "210987654321")
+
+	t.Run("valid file", func(t *testing.T) {
+		r := require.New(t)
+		file := newFile("0000000001", "USD", "EXB0001 BATCH", &credit)
+		r.NoError(file.Validate(WithProfile("example-bank")))
+	})
+
+	t.Run("violations", func(t *testing.T) {
+		r := require.New(t)
+		big := credit
+		big.Amount = 2000000
+		big.SundryInfo = "NOTE"
+		file := newFile("0000000002", "USD", "", &credit, &big, &credit)
+		r.NoError(file.Validate())
+
+		err := file.Validate(WithProfile("example-bank"))
+		r.ErrorIs(err, ErrInvalidCurrencyCode)
+		r.ErrorIs(err, ErrInvalidDirectClearerCommunicationArea)
+		r.ErrorIs(err, ErrInvalidAmount)
+		r.ErrorIs(err, ErrInvalidSundryInfo)
+		r.ErrorIs(err, ErrTooManyItems)
+
+		var vErrs ValidationErrors
+		r.True(errors.As(err, &vErrs))
+		r.Len(vErrs, 5)
+		r.Equal("currency", vErrs[0].Rule)
+		r.Equal("required", vErrs[1].Rule)
+		r.Equal(7, vErrs[1].FieldNumber)
+		r.Equal(1, *vErrs[2].TxnIndex)
+		r.Equal("max_amount", vErrs[2].Rule)
+		r.Equal(1, *vErrs[3].TxnIndex)
+		r.Equal("forbidden", vErrs[3].Rule)
+		r.Equal("sundry_info", vErrs[3].JSONField)
+		r.Equal("max_items", vErrs[4].Rule)
+
+		file = newFile("0000000001", "CAD", "BATCH", &credit)
+		err = file.Validate(WithProfile("example-bank"))
+		r.ErrorIs(err, ErrInvalidDirectClearerCommunicationArea)
+		r.True(errors.As(err, &vErrs))
+		r.Equal("pattern", vErrs[0].Rule)
+	})
+
+	t.Run("date windows", func(t *testing.T) {
+		r := require.New(t)
+		early := credit
+		early.DateFundsAvailable = Ptr(date.AddDate(0, 0, -10))
+		file := newFile("0000000001", "CAD", "EXB0001", &credit, &early)
+		err := file.Validate(WithProfile("example-bank"))
+		r.ErrorIs(err, ErrDateOutsideWindow)
+		var vErrs ValidationErrors
+		r.True(errors.As(err, &vErrs))
+		r.Len(vErrs, 1)
+		r.Equal(1, *vErrs[0].TxnIndex)
+		r.Equal(CreditRecord, vErrs[0].RecordType)
+		r.Equal("DateFundsAvailable", vErrs[0].Field)
+		r.Equal("date_funds_available", vErrs[0].JSONField)
+		r.Equal(6, vErrs[0].FieldNumber)
+		r.Equal("date_window", vErrs[0].Rule)
+		r.Equal("5 30", vErrs[0].Param)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		file := newFile("0000000001", "CAD", "EXB0001", &credit)
+		require.ErrorIs(t, file.Validate(WithProfile("missing")), ErrUnknownValidationProfile)
+	})
+}
+
+func TestLoadValidationProfiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("json", func(t *testing.T) {
+		r := require.New(t)
+		profiles, err := LoadValidationProfiles(write("profiles.json", `{"profiles":[{"name":"json-bank","max_items":10,"fields":{"cross_ref_no":{"values":["A","B"]}}}]}`))
+		r.NoError(err)
+		r.Equal(10, profiles[0].MaxItems)
+		r.Equal([]string{"A", "B"}, profiles[0].Fields["cross_ref_no"].Values)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := LoadValidationProfiles(write("unknown.yaml", "profiles:\n  - name: bad\n    fields:\n      nope:\n        required: true\n"))
+		require.ErrorIs(t, err, ErrInvalidValidationProfile)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := LoadValidationProfiles(write("pattern.yaml", "profiles:\n  - name: bad\n    fields:\n      user_id:\n        pattern: \"(\"\n"))
+		require.ErrorIs(t, err, ErrInvalidValidationProfile)
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		require.ErrorIs(t, RegisterValidationProfile(ValidationProfile{}), ErrInvalidValidationProfile)
+	})
+}
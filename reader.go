@@ -2,18 +2,56 @@ package cadeft
 
 import (
 	"bufio"
-	"fmt"
 	"io"
+	"time"
 )
 
 type Reader struct {
 	File    File
 	scanner *bufio.Scanner
+	cfg     readConfig
 }
 
-func NewReader(in io.Reader) *Reader {
+func NewReader(in io.Reader, opts ...ReadOpt) *Reader {
 	return &Reader{
 		scanner: bufio.NewScanner(in),
+		cfg:     newReadConfig(opts),
+	}
+}
+
+type ReadOpt func(*readConfig)
+
+type readConfig struct {
+	location *time.Location
+	preserve bool
+}
+
+func newReadConfig(opts []ReadOpt) readConfig {
+	cfg := readConfig{location: time.UTC}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	return cfg
+}
+
+// WithLocation places parsed dates at midnight in loc instead of midnight UTC, for example America/Toronto where clearing takes place
+func WithLocation(loc *time.Location) ReadOpt {
+	return func(rc *readConfig) {
+		if loc != nil {
+			rc.location = loc
+		}
+	}
+}
+
+func (rc readConfig) localizeHeader(fh *FileHeader) {
+	if fh.CreationDate != nil && rc.location != time.UTC {
+		fh.CreationDate = Ptr(inLocation(*fh.CreationDate, rc.location))
+	}
+}
+
+func (rc readConfig) localizeTxn(t Transaction) {
+	if date := t.GetDate(); date != nil && rc.location != time.UTC {
+		setTxnDate(t, Ptr(inLocation(*date, rc.location)))
 	}
 }
 
@@ -25,28 +63,41 @@ func (r *Reader) ReadFile() (File, error) {
 	// 005 lines are at most 1,464 chars; in UTF-8 with French chars they
 	// can stretch a bit past that. 1 MiB is plenty.
 	r.scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	if r.cfg.preserve {
+		r.scanner.Split(scanRawLines)
+		r.File.layout = &fileLayout{}
+	}
 
 	for r.scanner.Scan() {
-		line, err := normalize(r.scanner.Text())
-		if err != nil {
-			return File{}, fmt.Errorf("failed to read line: %w", err)
+		raw, eol := r.scanner.Text(), ""
+		if r.cfg.preserve {
+			raw, eol = cutLineEnding(raw)
 		}
-		if line == "" {
-			continue
+		line, err := normalize(raw)
+		if err != nil {
+			return File{}, newError(msgReadLine, err)
 		}
-		recordType := string([]rune(line)[:1])
+		var parsedType RecordType
+		txnStart := len(r.File.Txns)
+		recordType := string([]rune(line + " ")[:1])
 		if recordType == string(HeaderRecord) {
 			if err := r.parseARecord(line); err != nil {
-				return File{}, fmt.Errorf("failed to parse header: %w", err)
+				return File{}, newError(msgParseHeader, err)
 			}
+			parsedType = HeaderRecord
 		} else if isTxnRecord(recordType) {
 			if err := r.parseTxnRecord(line); err != nil {
-				return File{}, fmt.Errorf("failed to parse txn: %w", err)
+				return File{}, newError(msgParseTxn, err)
 			}
+			parsedType = RecordType(recordType)
 		} else if recordType == string(FooterRecord) {
 			if err := r.parseZRecord(line); err != nil {
-				return File{}, fmt.Errorf("failed to parse footer: %w", err)
+				return File{}, newError(msgParseFooter, err)
 			}
+			parsedType = FooterRecord
+		}
+		if r.cfg.preserve {
+			r.preserveLine(parsedType, raw, line, eol, txnStart)
 		}
 	}
 	return r.File, nil
@@ -54,12 +105,13 @@ func (r *Reader) ReadFile() (File, error) {
 
 func (r *Reader) parseARecord(data string) error {
 	if len([]rune(data)) < aRecordMinLength {
-		return fmt.Errorf("record type A is not required length")
+		return newError(msgHeaderLength)
 	}
 	fHeader := &FileHeader{}
 	if err := fHeader.parse(data); err != nil {
-		return fmt.Errorf("failed to parse file header: %w", err)
+		return newError(msgParseFileHeader, err)
 	}
+	r.cfg.localizeHeader(fHeader)
 	r.File.Header = fHeader
 	return nil
 }
@@ -67,17 +119,17 @@ func (r *Reader) parseARecord(data string) error {
 func (r *Reader) parseTxnRecord(data string) error {
 	rs := []rune(data)
 	if len(rs) < commonRecordDataLength {
-		return fmt.Errorf("txn record shorter than common header length %d: got %d", commonRecordDataLength, len(rs))
+		return newError(msgTxnRecordShort, commonRecordDataLength, len(rs))
 	}
 	if len(rs[commonRecordDataLength:])%segmentLength != 0 {
-		return fmt.Errorf("record length is not valid multiple of %d, partial txn: %d", segmentLength, len(rs[commonRecordDataLength:]))
+		return newError(msgPartialTxn, segmentLength, len(rs[commonRecordDataLength:]))
 	}
 	body := rs[commonRecordDataLength:]
 	numSegments := len(body) / segmentLength
 
 	recType, err := parseRecordType(string(rs[:1]))
 	if err != nil {
-		return fmt.Errorf("failed to parse transaction: %w", err)
+		return newError(msgParseTransaction, err)
 	}
 
 	for i := 0; i < numSegments; i++ {
@@ -85,58 +137,61 @@ func (r *Reader) parseTxnRecord(data string) error {
 		if isFillerString(seg) {
 			continue
 		}
+		var txn Transaction
 		switch recType {
 		case DebitRecord:
 			debit := Debit{}
 			if err := debit.Parse(seg); err != nil {
-				return fmt.Errorf("failed to parse debit transaction: %w", err)
+				return newError(msgParseDebit, err)
 			}
-			r.File.Txns = append(r.File.Txns, &debit)
+			txn = &debit
 		case CreditRecord:
 			credit := Credit{}
 			if err := credit.Parse(seg); err != nil {
-				return fmt.Errorf("failed to parse credit transaction: %w", err)
+				return newError(msgParseCredit, err)
 			}
-			r.File.Txns = append(r.File.Txns, &credit)
+			txn = &credit
 		case ReturnDebitRecord:
 			debitReturn := DebitReturn{}
 			if err := debitReturn.Parse(seg); err != nil {
-				return fmt.Errorf("failed to parse debit return transaction: %w", err)
+				return newError(msgParseDebitReturn, err)
 			}
-			r.File.Txns = append(r.File.Txns, &debitReturn)
+			txn = &debitReturn
 		case ReturnCreditRecord:
 			creditReturns := CreditReturn{}
 			if err := creditReturns.Parse(seg); err != nil {
-				return fmt.Errorf("failed to parse credit return transaction: %w", err)
+				return newError(msgParseCreditReturn, err)
 			}
-			r.File.Txns = append(r.File.Txns, &creditReturns)
+			txn = &creditReturns
 		case CreditReverseRecord:
 			creditReverse := CreditReverse{}
 			if err := creditReverse.Parse(seg); err != nil {
-				return fmt.Errorf("failed to parse credit reverse transaction: %w", err)
+				return newError(msgParseCreditReverse, err)
 			}
-			r.File.Txns = append(r.File.Txns, &creditReverse)
+			txn = &creditReverse
 		case DebitReverseRecord:
 			debitReverseRecord := DebitReverse{}
 			if err := debitReverseRecord.Parse(seg); err != nil {
-				return fmt.Errorf("failed to parse debit reverse transaction: %w", err)
+				return newError(msgParseDebitReverse, err)
 			}
-			r.File.Txns = append(r.File.Txns, &debitReverseRecord)
+			txn = &debitReverseRecord
 		case HeaderRecord, FooterRecord, NoticeOfChangeRecord, NoticeOfChangeHeader, NoticeOfChangeFooter:
-			return fmt.Errorf("unexpected %s record", recType)
+			return newError(msgUnexpectedRecord, recType)
 		}
+		r.cfg.localizeTxn(txn)
+		r.File.Txns = append(r.File.Txns, txn)
 	}
 	return nil
 }
 
 func (r *Reader) parseZRecord(data string) error {
 	if len([]rune(data)) < zRecordMinLength {
-		return fmt.Errorf("z record does not contain minimum amount of data")
+		return newError(msgFooterMinimum)
 	}
 
 	footer := &FileFooter{}
 	if err := footer.Parse(data); err != nil {
-		return fmt.Errorf("failed to parse file footer: %w", err)
+		return newError(msgParseFileFooter, err)
 	}
 	r.File.Footer = footer
 	return nil
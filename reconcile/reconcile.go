@@ -0,0 +1,290 @@
+// Package reconcile matches inbound returns and reversals (I, J, E and F records) against the
+// outbound EFT files they refer to using the original item trace number.
+package reconcile
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/moov-io/cadeft"
+)
+
+const itemTraceNoLength = 22
+
+// Discrepancy describes a field that differs between a return or reversal and its original item
+type Discrepancy string
+
+const (
+	AmountMismatch     Discrepancy = "amount"
+	AccountMismatch    Discrepancy = "account"
+	RecordTypeMismatch Discrepancy = "record_type"
+)
+
+// Item is a ledger friendly summary of a single transaction
+type Item struct {
+	RecordType          cadeft.RecordType      `json:"type"`
+	TxnType             cadeft.TransactionType `json:"txn_type"`
+	ItemTraceNo         string                 `json:"item_trace_no"`
+	OriginalItemTraceNo string                 `json:"original_item_trace_no,omitempty"`
+	Amount              int64                  `json:"amount"`
+	InstitutionID       string                 `json:"institution_id"`
+	AccountNo           string                 `json:"account_no"`
+	Name                string                 `json:"name"`
+	Date                *time.Time             `json:"date,omitempty"`
+	CrossRefNo          string                 `json:"cross_ref_no,omitempty"`
+}
+
+// Match pairs an inbound return or reversal with the outbound item it refers to
+type Match struct {
+	Original      Item          `json:"original"`
+	Inbound       Item          `json:"inbound"`
+	Discrepancies []Discrepancy `json:"discrepancies,omitempty"`
+}
+
+// Summary contains the counts and amounts of each section of a Report
+type Summary struct {
+	MatchedCount      int   `json:"matched_count"`
+	MatchedAmount     int64 `json:"matched_amount"`
+	MismatchedCount   int   `json:"mismatched_count"`
+	MismatchedAmount  int64 `json:"mismatched_amount"`
+	UnmatchedCount    int   `json:"unmatched_count"`
+	UnmatchedAmount   int64 `json:"unmatched_amount"`
+	DuplicateCount    int   `json:"duplicate_count"`
+	DuplicateAmount   int64 `json:"duplicate_amount"`
+	OutstandingCount  int   `json:"outstanding_count"`
+	OutstandingAmount int64 `json:"outstanding_amount"`
+}
+
+// Report is the outcome of reconciling inbound returns and reversals against outbound files.
+// Matched contains returns that agree with their original on amount, account and record type,
+// Mismatched contains returns whose original was found but differs, Unmatched contains returns
+// whose original item trace number is unknown and Duplicates contains any return that refers to an
+// original that was already returned or reversed. Outstanding lists the outbound items that have not
+// been returned or reversed.
+type Report struct {
+	Summary     Summary `json:"summary"`
+	Matched     []Match `json:"matched"`
+	Mismatched  []Match `json:"mismatched"`
+	Unmatched   []Item  `json:"unmatched"`
+	Duplicates  []Match `json:"duplicates"`
+	Outstanding []Item  `json:"outstanding"`
+}
+
+// AmountMismatches returns the mismatched returns whose amount differs from the original
+func (r Report) AmountMismatches() []Match {
+	out := make([]Match, 0)
+	for _, m := range r.Mismatched {
+		for _, d := range m.Discrepancies {
+			if d == AmountMismatch {
+				out = append(out, m)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// JSON serializes the report for consumption by a ledger
+func (r Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+type original struct {
+	txn      cadeft.Transaction
+	returned bool
+}
+
+// Reconciler indexes outbound files by item trace number and matches inbound returns and reversals against them.
+// Reconciler is not safe for concurrent use.
+type Reconciler struct {
+	originals map[string]*original
+	order     []string
+	inbound   []cadeft.Transaction
+}
+
+func NewReconciler() *Reconciler {
+	return &Reconciler{
+		originals: make(map[string]*original),
+	}
+}
+
+// AddOutbound indexes the C and D records of a previously sent file by their item trace number.
+// Items without an item trace number cannot be reconciled and are skipped, an error is returned for
+// any item trace number that has already been indexed.
+func (r *Reconciler) AddOutbound(f cadeft.File) error {
+	var err error
+	for i, t := range f.Txns {
+		if t.GetType() != cadeft.CreditRecord && t.GetType() != cadeft.DebitRecord {
+			continue
+		}
+		traceNo := t.GetBaseTxn().ItemTraceNo
+		if isBlank(traceNo) {
+			continue
+		}
+		key := normalizeTraceNo(traceNo)
+		if _, ok := r.originals[key]; ok {
+			err = multierror.Append(err, fmt.Errorf("txn %d: duplicate outbound item trace number %s", i, key))
+			continue
+		}
+		r.originals[key] = &original{txn: t}
+		r.order = append(r.order, key)
+	}
+	return err
+}
+
+// AddInbound queues the I, J, E and F records of a returns or reversal file for reconciliation.
+// Any other record types are ignored.
+func (r *Reconciler) AddInbound(f cadeft.File) {
+	for _, t := range f.Txns {
+		switch t.GetType() {
+		case cadeft.ReturnCreditRecord, cadeft.ReturnDebitRecord, cadeft.CreditReverseRecord, cadeft.DebitReverseRecord:
+			r.inbound = append(r.inbound, t)
+		case cadeft.HeaderRecord, cadeft.CreditRecord, cadeft.DebitRecord, cadeft.NoticeOfChangeRecord, cadeft.NoticeOfChangeHeader, cadeft.NoticeOfChangeFooter, cadeft.FooterRecord:
+		}
+	}
+}
+
+// Report reconciles every inbound item added so far against the outbound index.
+// Report can be called repeatedly, each call reconciles from scratch.
+func (r *Reconciler) Report() Report {
+	for _, o := range r.originals {
+		o.returned = false
+	}
+	report := Report{
+		Matched:     make([]Match, 0),
+		Mismatched:  make([]Match, 0),
+		Unmatched:   make([]Item, 0),
+		Duplicates:  make([]Match, 0),
+		Outstanding: make([]Item, 0),
+	}
+	for _, in := range r.inbound {
+		item := newItem(in)
+		orig, ok := r.originals[normalizeTraceNo(in.GetOriginalItemTraceNo())]
+		if !ok {
+			report.Unmatched = append(report.Unmatched, item)
+			report.Summary.UnmatchedCount++
+			report.Summary.UnmatchedAmount += item.Amount
+			continue
+		}
+		m := Match{
+			Original:      newItem(orig.txn),
+			Inbound:       item,
+			Discrepancies: compare(orig.txn, in),
+		}
+		switch {
+		case orig.returned:
+			report.Duplicates = append(report.Duplicates, m)
+			report.Summary.DuplicateCount++
+			report.Summary.DuplicateAmount += item.Amount
+		case len(m.Discrepancies) > 0:
+			report.Mismatched = append(report.Mismatched, m)
+			report.Summary.MismatchedCount++
+			report.Summary.MismatchedAmount += item.Amount
+		default:
+			report.Matched = append(report.Matched, m)
+			report.Summary.MatchedCount++
+			report.Summary.MatchedAmount += item.Amount
+		}
+		orig.returned = true
+	}
+	for _, key := range r.order {
+		if o := r.originals[key]; !o.returned {
+			item := newItem(o.txn)
+			report.Outstanding = append(report.Outstanding, item)
+			report.Summary.OutstandingCount++
+			report.Summary.OutstandingAmount += item.Amount
+		}
+	}
+	return report
+}
+
+// Reconcile is a convenience wrapper that reconciles inbound files against outbound files in a single call
+func Reconcile(outbound []cadeft.File, inbound []cadeft.File) (Report, error) {
+	r := NewReconciler()
+	for _, f := range outbound {
+		if err := r.AddOutbound(f); err != nil {
+			return Report{}, fmt.Errorf("failed to index outbound file: %w", err)
+		}
+	}
+	for _, f := range inbound {
+		r.AddInbound(f)
+	}
+	return r.Report(), nil
+}
+
+func newItem(t cadeft.Transaction) Item {
+	base := t.GetBaseTxn()
+	institutionID, accountNo := account(t)
+	return Item{
+		RecordType:          t.GetType(),
+		TxnType:             base.TxnType,
+		ItemTraceNo:         base.ItemTraceNo,
+		OriginalItemTraceNo: t.GetOriginalItemTraceNo(),
+		Amount:              t.GetAmount(),
+		InstitutionID:       institutionID,
+		AccountNo:           accountNo,
+		Name:                t.GetName(),
+		Date:                t.GetDate(),
+		CrossRefNo:          base.CrossRefNo,
+	}
+}
+
+// account returns the payor/payee institution and account the item was sent to.
+// Returns carry the original destination in their original institution and account fields
+// while reversals keep the destination of the item they reverse.
+func account(t cadeft.Transaction) (string, string) {
+	switch t.GetType() {
+	case cadeft.ReturnCreditRecord, cadeft.ReturnDebitRecord:
+		return t.GetOriginalInstitutionID(), t.GetOriginalAccountNo()
+	case cadeft.HeaderRecord, cadeft.CreditRecord, cadeft.DebitRecord, cadeft.CreditReverseRecord, cadeft.DebitReverseRecord, cadeft.NoticeOfChangeRecord, cadeft.NoticeOfChangeHeader, cadeft.NoticeOfChangeFooter, cadeft.FooterRecord:
+	}
+	return t.GetBaseTxn().InstitutionID, t.GetAccountNo()
+}
+
+// originalRecordType returns the record type that an inbound return or reversal refers to
+func originalRecordType(t cadeft.RecordType) cadeft.RecordType {
+	switch t {
+	case cadeft.ReturnCreditRecord, cadeft.CreditReverseRecord:
+		return cadeft.CreditRecord
+	case cadeft.ReturnDebitRecord, cadeft.DebitReverseRecord:
+		return cadeft.DebitRecord
+	case cadeft.HeaderRecord, cadeft.CreditRecord, cadeft.DebitRecord, cadeft.NoticeOfChangeRecord, cadeft.NoticeOfChangeHeader, cadeft.NoticeOfChangeFooter, cadeft.FooterRecord:
+	}
+	return ""
+}
+
+func compare(orig, in cadeft.Transaction) []Discrepancy {
+	var d []Discrepancy
+	if orig.GetType() != originalRecordType(in.GetType()) {
+		d = append(d, RecordTypeMismatch)
+	}
+	if orig.GetAmount() != in.GetAmount() {
+		d = append(d, AmountMismatch)
+	}
+	origInstitution, origAccount := account(orig)
+	inInstitution, inAccount := account(in)
+	if normalizeNum(origInstitution) != normalizeNum(inInstitution) || strings.TrimSpace(origAccount) != strings.TrimSpace(inAccount) {
+		d = append(d, AccountMismatch)
+	}
+	return d
+}
+
+// normalizeTraceNo left pads trace numbers with zeros as they would be written to a file
+func normalizeTraceNo(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= itemTraceNoLength {
+		return s
+	}
+	return strings.Repeat("0", itemTraceNoLength-len(s)) + s
+}
+
+func normalizeNum(s string) string {
+	return strings.TrimLeft(strings.TrimSpace(s), "0")
+}
+
+func isBlank(s string) bool {
+	return strings.Trim(s, " 0") == ""
+}
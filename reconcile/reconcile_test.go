@@ -0,0 +1,99 @@
+package reconcile
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/moov-io/cadeft"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcile(t *testing.T) {
+	r := require.New(t)
+	date := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	header := cadeft.NewFileHeader("0000000001", 1, &date, 12345, "CAD")
+	outbound := cadeft.NewFile(header, cadeft.Transactions{
+		cadeft.Ptr(cadeft.NewCredit("450", 1000, &date, "123456789", "12345", "1", "short name", "payee one", "someone", "1231", "12345")),
+		cadeft.Ptr(cadeft.NewCredit("450", 2000, &date, "123456789", "22222", "2", "short name", "payee two", "someone", "1231", "12345")),
+		cadeft.Ptr(cadeft.NewDebit("400", 3000, &date, "987654321", "33333", "3", "short name", "payor three", "someone", "1231", "12345")),
+		cadeft.Ptr(cadeft.NewDebit("400", 4000, &date, "987654321", "44444", "4", "short name", "payor four", "someone", "1231", "12345")),
+	})
+	inbound := cadeft.NewFile(header, cadeft.Transactions{
+		// matches the first credit
+		cadeft.Ptr(cadeft.NewCreditReturn("450", 1000, &date, "1231", "12345", "9000000000000000000001", "short name", "payee one", "someone", "123456789", "12345", "0000000000000000000001")),
+		// returned again
+		cadeft.Ptr(cadeft.NewCreditReturn("450", 1000, &date, "1231", "12345", "9000000000000000000002", "short name", "payee one", "someone", "123456789", "12345", "1")),
+		// wrong amount
+		cadeft.Ptr(cadeft.NewDebitReturn("400", 2500, &date, "1231", "12345", "9000000000000000000003", "short name", "payor three", "someone", "987654321", "33333", "3")),
+		// unknown original
+		cadeft.Ptr(cadeft.NewDebitReturn("400", 100, &date, "1231", "12345", "9000000000000000000004", "short name", "payor", "someone", "987654321", "55555", "99")),
+		// reversal of the last debit
+		cadeft.Ptr(cadeft.NewDebitReverse("400", 4000, &date, "987654321", "44444", "9000000000000000000005", "short name", "payor four", "someone", "1231", "12345", "4")),
+	})
+
+	report, err := Reconcile([]cadeft.File{outbound}, []cadeft.File{inbound})
+	r.NoError(err)
+
+	r.Len(report.Matched, 2)
+	r.Equal("1", report.Matched[0].Original.ItemTraceNo)
+	r.Equal(cadeft.DebitReverseRecord, report.Matched[1].Inbound.RecordType)
+
+	r.Len(report.Duplicates, 1)
+	r.Equal("9000000000000000000002", report.Duplicates[0].Inbound.ItemTraceNo)
+
+	r.Len(report.Mismatched, 1)
+	r.Equal([]Discrepancy{AmountMismatch}, report.Mismatched[0].Discrepancies)
+	r.Len(report.AmountMismatches(), 1)
+
+	r.Len(report.Unmatched, 1)
+	r.Equal("99", report.Unmatched[0].OriginalItemTraceNo)
+
+	r.Len(report.Outstanding, 1)
+	r.Equal("2", report.Outstanding[0].ItemTraceNo)
+
+	r.Equal(Summary{
+		MatchedCount:      2,
+		MatchedAmount:     5000,
+		MismatchedCount:   1,
+		MismatchedAmount:  2500,
+		UnmatchedCount:    1,
+		UnmatchedAmount:   100,
+		DuplicateCount:    1,
+		DuplicateAmount:   1000,
+		OutstandingCount:  1,
+		OutstandingAmount: 2000,
+	}, report.Summary)
+
+	out, err := report.JSON()
+	r.NoError(err)
+	var decoded Report
+	r.NoError(json.Unmarshal(out, &decoded))
+	r.Equal(report.Summary, decoded.Summary)
+}
+
+func TestReconcileMismatchedAccountAndType(t *testing.T) {
+	r := require.New(t)
+	date := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	header := cadeft.NewFileHeader("0000000001", 1, &date, 12345, "CAD")
+	outbound := cadeft.NewFile(header, cadeft.Transactions{
+		cadeft.Ptr(cadeft.NewCredit("450", 1000, &date, "123456789", "12345", "1", "short name", "payee one", "someone", "1231", "12345")),
+	})
+	inbound := cadeft.NewFile(header, cadeft.Transactions{
+		cadeft.Ptr(cadeft.NewDebitReturn("450", 1000, &date, "1231", "12345", "9", "short name", "payee one", "someone", "123456789", "99999", "1")),
+	})
+	report, err := Reconcile([]cadeft.File{outbound}, []cadeft.File{inbound})
+	r.NoError(err)
+	r.Len(report.Mismatched, 1)
+	r.Equal([]Discrepancy{RecordTypeMismatch, AccountMismatch}, report.Mismatched[0].Discrepancies)
+}
+
+func TestAddOutboundDuplicateTraceNo(t *testing.T) {
+	r := require.New(t)
+	date := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	header := cadeft.NewFileHeader("0000000001", 1, &date, 12345, "CAD")
+	txn := cadeft.Ptr(cadeft.NewCredit("450", 1000, &date, "123456789", "12345", "1", "short name", "payee one", "someone", "1231", "12345"))
+	rec := NewReconciler()
+	r.NoError(rec.AddOutbound(cadeft.NewFile(header, cadeft.Transactions{txn})))
+	r.Error(rec.AddOutbound(cadeft.NewFile(header, cadeft.Transactions{txn})))
+}
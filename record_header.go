@@ -1,7 +1,6 @@
 package cadeft
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -9,9 +8,9 @@ import (
 // The contents of this field are the RecordTpye the row count and originator id.
 // This field is mostly internal and used for writing the file.
 type RecordHeader struct {
-	RecordType      RecordType `json:"type" validate:"rec_type"`
-	OriginatorID    string     `json:"originator_id" validate:"required,eft_alpha,len=10"`
-	FileCreationNum int64      `json:"file_creation_number" validate:"required,max=9999"`
+	RecordType      RecordType `json:"type" validate:"rec_type" spec:"1"`
+	OriginatorID    string     `json:"originator_id" validate:"required,eft_alpha,len=10" spec:"3"`
+	FileCreationNum int64      `json:"file_creation_number" validate:"required,max=9999" spec:"4"`
 	recordCount     int64      `json:"-"`
 }
 
@@ -19,19 +18,19 @@ func (rh *RecordHeader) parse(line string) error {
 	var err error
 	rs := []rune(line)
 	if len(rs) < 24 {
-		return fmt.Errorf("record header line too short")
+		return newError(msgRecordHeaderShort)
 	}
 	if rh.RecordType, err = convertRecordType(string(rs[:1])); err != nil {
-		return fmt.Errorf("faield to parse RecordHeader: %w", err)
+		return newError(msgParseRecordType, err)
 	}
 
 	if rh.recordCount, err = parseNum(string(rs[1:10])); err != nil {
-		return fmt.Errorf("failed to parse RecordCount: %w", err)
+		return newError(msgParseRecordCount, err)
 	}
 
 	rh.OriginatorID = strings.TrimSpace(string(rs[10:20]))
 	if rh.FileCreationNum, err = parseNum(string(rs[20:24])); err != nil {
-		return fmt.Errorf("failed to parse FileCreationNum: %w", err)
+		return newError(msgParseFileCreationNum, err)
 	}
 
 	return nil
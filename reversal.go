@@ -0,0 +1,131 @@
+package cadeft
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// DefaultReversalWindow is the maximum age of an original item, measured from its due date or date funds available,
+// that NewReversalFile will reverse unless overridden with WithReversalWindow.
+const DefaultReversalWindow = 5 * 24 * time.Hour
+
+// ReversalFilter decides whether an item of the original file should be included in a reversal file.
+type ReversalFilter func(Transaction) bool
+
+type reversalConfig struct {
+	filter ReversalFilter
+	window time.Duration
+}
+
+type ReversalOpt func(*reversalConfig)
+
+// WithReversalFilter only reverses the original items for which filter returns true, by default every item is reversed
+func WithReversalFilter(filter ReversalFilter) ReversalOpt {
+	return func(rc *reversalConfig) {
+		rc.filter = filter
+	}
+}
+
+// WithReversalWindow overrides DefaultReversalWindow
+func WithReversalWindow(window time.Duration) ReversalOpt {
+	return func(rc *reversalConfig) {
+		rc.window = window
+	}
+}
+
+// NewReversalFile builds a file of E and F records that recalls the C and D records of a previously sent file.
+// The header of the original file is reused with the provided file creation number and creation date.
+// Every reversal references its original item through OriginalItemTraceNo and is left without an ItemTraceNo
+// so that one can be assigned before sending. Items that are not C or D records,
+// or whose date is older than the reversal window relative to creationDate, are refused and reported in the returned error.
+func NewReversalFile(original File, fileCreationNum int64, creationDate *time.Time, opts ...ReversalOpt) (File, error) {
+	if original.Header == nil {
+		return File{}, errors.New("original file header is missing")
+	}
+	if creationDate == nil {
+		return File{}, errors.New("creation date is missing")
+	}
+	cfg := reversalConfig{window: DefaultReversalWindow}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	header := *original.Header
+	header.FileCreationNum = fileCreationNum
+	header.CreationDate = creationDate
+	header.recordCount = 1
+
+	var err error
+	txns := make(Transactions, 0, len(original.Txns))
+	for i, t := range original.Txns {
+		if cfg.filter != nil && !cfg.filter(t) {
+			continue
+		}
+		reversal, revErr := newReversal(t, *creationDate, cfg.window)
+		if revErr != nil {
+			err = multierror.Append(err, newError(msgTxn, i, revErr))
+			continue
+		}
+		txns = append(txns, reversal)
+	}
+	if err != nil {
+		return File{}, err
+	}
+	return NewFile(&header, txns), nil
+}
+
+func newReversal(t Transaction, creationDate time.Time, window time.Duration) (Transaction, error) {
+	date := t.GetDate()
+	if date == nil {
+		return nil, errors.New("original item has no date")
+	}
+	if creationDate.Sub(*date) > window {
+		return nil, newError(msgDated, ErrReversalWindowExceeded, date.Format(time.DateOnly))
+	}
+	base := t.GetBaseTxn()
+	opts := []BaseTxnOpt{
+		WithUserID(base.UserID),
+		WithCrossRefNo(base.CrossRefNo),
+		WithSundryInfo(base.SundryInfo),
+		WithSettlementCode(base.SettlementCode),
+	}
+	switch t.GetType() {
+	case CreditRecord:
+		return Ptr(NewCreditReverse(
+			base.TxnType,
+			base.Amount,
+			date,
+			base.InstitutionID,
+			t.GetAccountNo(),
+			"",
+			base.OriginatorShortName,
+			t.GetName(),
+			base.OriginatorLongName,
+			t.GetReturnInstitutionID(),
+			t.GetReturnAccountNo(),
+			base.ItemTraceNo,
+			opts...,
+		)), nil
+	case DebitRecord:
+		return Ptr(NewDebitReverse(
+			base.TxnType,
+			base.Amount,
+			date,
+			base.InstitutionID,
+			t.GetAccountNo(),
+			"",
+			base.OriginatorShortName,
+			t.GetName(),
+			base.OriginatorLongName,
+			t.GetReturnInstitutionID(),
+			t.GetReturnAccountNo(),
+			base.ItemTraceNo,
+			opts...,
+		)), nil
+	case HeaderRecord, CreditReverseRecord, DebitReverseRecord, ReturnCreditRecord, ReturnDebitRecord, NoticeOfChangeRecord, NoticeOfChangeHeader, NoticeOfChangeFooter, FooterRecord:
+		return nil, newError(msgErrGot, ErrNotReversible, t.GetType())
+	}
+	return nil, newError(msgErrGot, ErrNotReversible, t.GetType())
+}
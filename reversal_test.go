@@ -0,0 +1,73 @@
+package cadeft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReversalFile(t *testing.T) {
+	date := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	creationDate := time.Date(2023, 10, 4, 0, 0, 0, 0, time.UTC)
+	header := NewFileHeader("0000000001", 1, &date, 12345, "CAD")
+	credit := Ptr(NewCredit("450", 1000, &date, "123456789", "12345", "1111111111111111111111", "short name", "payee name", "someone", "1231", "12345", WithCrossRefNo("ref1")))
+	debit := Ptr(NewDebit("400", 2000, &date, "987654321", "1234", "2222222222222222222222", "Short name", "payor name", "my long name", "123456789", "1111111"))
+	original := NewFile(header, Transactions{credit, debit})
+
+	t.Run("reverse all items", func(t *testing.T) {
+		r := require.New(t)
+		reversal, err := NewReversalFile(original, 2, &creationDate)
+		r.NoError(err)
+		r.Equal(int64(2), reversal.Header.FileCreationNum)
+		r.Equal(&creationDate, reversal.Header.CreationDate)
+		r.Equal(int64(1), original.Header.FileCreationNum)
+		r.Len(reversal.Txns, 2)
+
+		cr, ok := reversal.Txns[0].(*CreditReverse)
+		r.True(ok)
+		r.Equal(CreditReverseRecord, cr.RecordType)
+		r.Equal(credit.ItemTraceNo, cr.OriginalItemTraceNo)
+		r.Equal(credit.Amount, cr.Amount)
+		r.Equal(credit.PayeeAccountNo, cr.PayeeAccountNo)
+		r.Equal("ref1", cr.CrossRefNo)
+		r.Empty(cr.ItemTraceNo)
+
+		dr, ok := reversal.Txns[1].(*DebitReverse)
+		r.True(ok)
+		r.Equal(debit.ItemTraceNo, dr.OriginalItemTraceNo)
+		r.Equal(debit.ReturnInstitutionID, dr.ReturnInstitutionID)
+
+		_, err = reversal.Create()
+		r.NoError(err)
+		r.Equal(int64(1000), reversal.Footer.TotalValueOfERecords)
+		r.Equal(int64(2000), reversal.Footer.TotalValueOfFRecords)
+	})
+
+	t.Run("filter items", func(t *testing.T) {
+		r := require.New(t)
+		reversal, err := NewReversalFile(original, 2, &creationDate, WithReversalFilter(func(t Transaction) bool {
+			return t.GetType() == DebitRecord
+		}))
+		r.NoError(err)
+		r.Len(reversal.Txns, 1)
+		r.Equal(DebitReverseRecord, reversal.Txns[0].GetType())
+	})
+
+	t.Run("outside reversal window", func(t *testing.T) {
+		r := require.New(t)
+		late := creationDate.AddDate(0, 0, 10)
+		_, err := NewReversalFile(original, 2, &late)
+		r.ErrorIs(err, ErrReversalWindowExceeded)
+
+		_, err = NewReversalFile(original, 2, &late, WithReversalWindow(30*24*time.Hour))
+		r.NoError(err)
+	})
+
+	t.Run("returns are not reversible", func(t *testing.T) {
+		r := require.New(t)
+		ret := Ptr(NewCreditReturn("900", 1000, &date, "12345", "1234567", "2222222222222222222222", "sender", "receiver", "sender full name", "54321", "7654321", "3333333333333333333333"))
+		_, err := NewReversalFile(NewFile(header, Transactions{ret}), 2, &creationDate)
+		r.ErrorIs(err, ErrNotReversible)
+	})
+}
@@ -0,0 +1,103 @@
+package cadeft
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Rule is a custom validation check run by File.ValidateWith on top of the checks of the spec.
+// Returning a ValidationError, for example one built with NewFieldError, gives the caller the field that failed,
+// any other error is reported as is.
+type Rule interface {
+	ValidateHeader(header FileHeader) error
+	ValidateTxn(txn Transaction) error
+	ValidateFile(file File) error
+}
+
+// HeaderRuleFunc is a Rule that only checks the FileHeader
+type HeaderRuleFunc func(header FileHeader) error
+
+func (fn HeaderRuleFunc) ValidateHeader(header FileHeader) error { return fn(header) }
+func (fn HeaderRuleFunc) ValidateTxn(Transaction) error          { return nil }
+func (fn HeaderRuleFunc) ValidateFile(File) error                { return nil }
+
+// TxnRuleFunc is a Rule that checks every Transaction of a file
+type TxnRuleFunc func(txn Transaction) error
+
+func (fn TxnRuleFunc) ValidateHeader(FileHeader) error   { return nil }
+func (fn TxnRuleFunc) ValidateTxn(txn Transaction) error { return fn(txn) }
+func (fn TxnRuleFunc) ValidateFile(File) error           { return nil }
+
+// FileRuleFunc is a Rule that checks the whole file at once
+type FileRuleFunc func(file File) error
+
+func (fn FileRuleFunc) ValidateHeader(FileHeader) error { return nil }
+func (fn FileRuleFunc) ValidateTxn(Transaction) error   { return nil }
+func (fn FileRuleFunc) ValidateFile(file File) error    { return fn(file) }
+
+// NewFieldError returns a ValidationError for the named field of record, a FileHeader or Transaction,
+// filling in the record type, json name, spec field number and value of the field.
+func NewFieldError(record any, field, rule, param string, err error) *ValidationError {
+	v := &ValidationError{
+		Field: field,
+		Rule:  rule,
+		Param: param,
+		Err:   err,
+	}
+	switch r := record.(type) {
+	case FileHeader, *FileHeader:
+		v.RecordType = HeaderRecord
+	case Transaction:
+		v.RecordType = r.GetType()
+	}
+	rv := reflect.Indirect(reflect.ValueOf(record))
+	if rv.Kind() != reflect.Struct {
+		return v
+	}
+	v.JSONField, v.FieldNumber = specField(rv.Type(), field)
+	if fv := rv.FieldByName(field); fv.IsValid() && fv.CanInterface() {
+		v.Value = fv.Interface()
+	}
+	return v
+}
+
+// MaxAmountRule rejects transactions with an amount, in cents, above max
+func MaxAmountRule(max int64) Rule {
+	param := strconv.FormatInt(max, 10)
+	return TxnRuleFunc(func(txn Transaction) error {
+		if txn.GetAmount() > max {
+			return NewFieldError(txn, "Amount", "max_amount", param, ErrInvalidAmount)
+		}
+		return nil
+	})
+}
+
+// BannedNamesRule rejects transactions whose payor or payee name is one of names, ignoring case and surrounding spaces
+func BannedNamesRule(names ...string) Rule {
+	banned := make(map[string]struct{}, len(names))
+	for _, n := range names {
+		banned[strings.ToUpper(strings.TrimSpace(n))] = struct{}{}
+	}
+	return TxnRuleFunc(func(txn Transaction) error {
+		if _, ok := banned[strings.ToUpper(strings.TrimSpace(txn.GetName()))]; !ok {
+			return nil
+		}
+		field := "PayeeName"
+		if _, ok := reflect.Indirect(reflect.ValueOf(txn)).Type().FieldByName("PayorName"); ok {
+			field = "PayorName"
+		}
+		return NewFieldError(txn, field, "banned_name", "", ErrInvalidName)
+	})
+}
+
+// CrossRefNoFormatRule requires the CrossRefNo of every transaction to match pattern
+func CrossRefNoFormatRule(pattern *regexp.Regexp) Rule {
+	return TxnRuleFunc(func(txn Transaction) error {
+		if pattern.MatchString(txn.GetBaseTxn().CrossRefNo) {
+			return nil
+		}
+		return NewFieldError(txn, "CrossRefNo", "cross_ref_no_format", pattern.String(), ErrInvalidCrossRefNo)
+	})
+}
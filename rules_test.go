@@ -0,0 +1,97 @@
+package cadeft
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWith(t *testing.T) {
+	date := time.Date(2023, time.October, 2, 0, 0, 0, 0, time.UTC)
+	newFile := func() File {
+		credit := NewCredit("450", 500000, &date, "123456789", "123456789012", "", "SHORT-NAME", "ACME CORP", "LONG-NAME", "123456789", "210987654321", WithCrossRefNo("INV-0001"))
+		debit := NewDebit("450", 100, &date, "123456789", "123456789012", "", "SHORT-NAME", "JANE DOE", "LONG-NAME", "123456789", "210987654321", WithCrossRefNo("1234"))
+		return NewFile(NewFileHeader("0000000001", 1, &date, 12345, "CAD"), Transactions{&credit, &debit})
+	}
+
+	t.Run("no rules matches Validate", func(t *testing.T) {
+		r := require.New(t)
+		file := newFile()
+		r.NoError(file.ValidateWith())
+		r.NoError(file.Validate())
+	})
+
+	t.Run("transaction rules", func(t *testing.T) {
+		r := require.New(t)
+		file := newFile()
+		err := file.ValidateWith(
+			MaxAmountRule(100000),
+			BannedNamesRule(" jane doe"),
+			CrossRefNoFormatRule(regexp.MustCompile(`^INV-\d{4}$`)),
+		)
+		r.ErrorIs(err, ErrInvalidAmount)
+		r.ErrorIs(err, ErrInvalidName)
+		r.ErrorIs(err, ErrInvalidCrossRefNo)
+
+		var vErrs ValidationErrors
+		r.True(errors.As(err, &vErrs))
+		r.Len(vErrs, 3)
+
+		amount := vErrs[0]
+		r.Equal(0, *amount.TxnIndex)
+		r.Equal(CreditRecord, amount.RecordType)
+		r.Equal("amount", amount.JSONField)
+		r.Equal(5, amount.FieldNumber)
+		r.Equal("max_amount", amount.Rule)
+		r.Equal("100000", amount.Param)
+		r.Equal(int64(500000), amount.Value)
+
+		name := vErrs[1]
+		r.Equal(1, *name.TxnIndex)
+		r.Equal("PayorName", name.Field)
+		r.Equal(12, name.FieldNumber)
+		r.Equal("JANE DOE", name.Value)
+
+		crossRef := vErrs[2]
+		r.Equal(1, *crossRef.TxnIndex)
+		r.Equal("cross_ref_no", crossRef.JSONField)
+		r.Equal(15, crossRef.FieldNumber)
+	})
+
+	t.Run("header and file rules combined with built-in checks", func(t *testing.T) {
+		r := require.New(t)
+		file := newFile()
+		file.Header.CurrencyCode = "EUR"
+		errTooManyItems := errors.New("too many items")
+		err := file.ValidateWith(
+			HeaderRuleFunc(func(h FileHeader) error {
+				if h.DestinationDataCenterNo != 12345 {
+					return nil
+				}
+				return NewFieldError(h, "DestinationDataCenterNo", "data_center", "", ErrInvalidDestinationDataCenterNo)
+			}),
+			FileRuleFunc(func(f File) error {
+				if len(f.Txns) > 1 {
+					return fmt.Errorf("%w: %d", errTooManyItems, len(f.Txns))
+				}
+				return nil
+			}),
+		)
+		r.ErrorIs(err, ErrInvalidCurrencyCode)
+		r.ErrorIs(err, ErrInvalidDestinationDataCenterNo)
+		r.ErrorIs(err, errTooManyItems)
+
+		var vErrs ValidationErrors
+		r.True(errors.As(err, &vErrs))
+		r.Len(vErrs, 3)
+		r.Equal(HeaderRecord, vErrs[1].RecordType)
+		r.Equal(6, vErrs[1].FieldNumber)
+		r.Equal(int64(12345), vErrs[1].Value)
+		r.Nil(vErrs[2].TxnIndex)
+		r.Equal("too many items: 2", vErrs[2].Error())
+	})
+}
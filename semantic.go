@@ -0,0 +1,121 @@
+package cadeft
+
+import "strings"
+
+// semanticValidator is implemented by the records that have rules spanning several fields
+type semanticValidator interface {
+	ValidateSemantics() error
+}
+
+// SemanticRule is a Rule running ValidateSemantics on every return and reversal of a file,
+// use it with File.ValidateWith or WithRules to check inter-field rules along with the spec.
+var SemanticRule Rule = TxnRuleFunc(func(txn Transaction) error {
+	if s, ok := txn.(semanticValidator); ok {
+		return s.ValidateSemantics()
+	}
+	return nil
+})
+
+// ValidateSemantics checks the rules between fields of a CreditReturn: the stored transaction type, the type of the original transaction,
+// belongs to the same family as the transaction type, the invalid data element ID carrying the CPA return reason read by ReturnReasonCode is present
+// and the original institution and account differ from the ones the return is made from.
+func (c CreditReturn) ValidateSemantics() error {
+	return validateReturnSemantics(&c, "PayeeAccountNo", c.PayeeAccountNo, c.OriginalInstitutionID, c.OriginalAccountNo, c.OriginalItemTraceNo)
+}
+
+// ValidateSemantics checks the rules between fields of a DebitReturn: the stored transaction type, the type of the original transaction,
+// belongs to the same family as the transaction type, the invalid data element ID carrying the CPA return reason read by ReturnReasonCode is present
+// and the original institution and account differ from the ones the return is made from.
+func (d DebitReturn) ValidateSemantics() error {
+	return validateReturnSemantics(&d, "PayorAccountNo", d.PayorAccountNo, d.OriginalInstitutionID, d.OriginalAccountNo, d.OriginalItemTraceNo)
+}
+
+// ValidateSemantics checks the rules between fields of a CreditReverse: the return institution and account differ from the ones of the payee,
+// the original item trace number differs from the item trace number and no invalid data element ID is set.
+func (c CreditReverse) ValidateSemantics() error {
+	return validateReversalSemantics(&c, "PayeeAccountNo", c.PayeeAccountNo, c.ReturnInstitutionID, c.ReturnAccountNo, c.OriginalItemTraceNo)
+}
+
+// ValidateSemantics checks the rules between fields of a DebitReverse: the return institution and account differ from the ones of the payor,
+// the original item trace number differs from the item trace number and no invalid data element ID is set.
+func (d DebitReverse) ValidateSemantics() error {
+	return validateReversalSemantics(&d, "PayorAccountNo", d.PayorAccountNo, d.ReturnInstitutionID, d.ReturnAccountNo, d.OriginalItemTraceNo)
+}
+
+// validateReturnSemantics checks a CreditReturn or DebitReturn. Field 10 StoredTransactionType is the original transaction type
+// and field 21 InvalidDataElementID the return reason: converters reading or writing return reasons go through ReturnReasonCode.
+func validateReturnSemantics(txn Transaction, accountField, accountNo, originalInstitutionID, originalAccountNo, originalItemTraceNo string) error {
+	base := txn.GetBaseTxn()
+	var errs ValidationErrors
+	switch stored := string(base.StoredTransactionType); {
+	case isBlankNumeric(stored):
+		errs = append(errs, NewFieldError(txn, "StoredTransactionType", "required", "", ErrInvalidStoredTxnType))
+	case !sameTxnTypeFamily(base.TxnType, base.StoredTransactionType):
+		errs = append(errs, NewFieldError(txn, "StoredTransactionType", "txn_type_family", string(base.TxnType), ErrInvalidStoredTxnType))
+	}
+	if isBlankNumeric(base.InvalidDataElementID) {
+		errs = append(errs, NewFieldError(txn, "InvalidDataElementID", "required", "", ErrInvalidInvalidDataElementID))
+	}
+	if sameInstitution(base.InstitutionID, originalInstitutionID) {
+		errs = append(errs, NewFieldError(txn, "OriginalInstitutionID", "nefield", "InstitutionID", ErrInvalidOriginalInstitutionID))
+		if sameAccountNo(accountNo, originalAccountNo) {
+			errs = append(errs, NewFieldError(txn, "OriginalAccountNo", "nefield", accountField, ErrInvalidOriginalAccountNo))
+		}
+	}
+	if sameItemTraceNo(base.ItemTraceNo, originalItemTraceNo) {
+		errs = append(errs, NewFieldError(txn, "OriginalItemTraceNo", "nefield", "ItemTraceNo", ErrInvalidOriginalItemTraceNo))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func validateReversalSemantics(txn Transaction, accountField, accountNo, returnInstitutionID, returnAccountNo, originalItemTraceNo string) error {
+	base := txn.GetBaseTxn()
+	var errs ValidationErrors
+	if !isBlankNumeric(base.InvalidDataElementID) {
+		errs = append(errs, NewFieldError(txn, "InvalidDataElementID", "excluded", "", ErrInvalidInvalidDataElementID))
+	}
+	if sameInstitution(base.InstitutionID, returnInstitutionID) {
+		errs = append(errs, NewFieldError(txn, "ReturnInstitutionID", "nefield", "InstitutionID", ErrInvalidReturnInstitutionID))
+		if sameAccountNo(accountNo, returnAccountNo) {
+			errs = append(errs, NewFieldError(txn, "ReturnAccountNo", "nefield", accountField, ErrInvalidReturnAccountNo))
+		}
+	}
+	if sameItemTraceNo(base.ItemTraceNo, originalItemTraceNo) {
+		errs = append(errs, NewFieldError(txn, "OriginalItemTraceNo", "nefield", "ItemTraceNo", ErrInvalidOriginalItemTraceNo))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// sameTxnTypeFamily reports whether both transaction codes share the same hundreds digit, for example 450 and 470 are both miscellaneous payments
+func sameTxnTypeFamily(a, b TransactionType) bool {
+	a, b = TransactionType(padNumericStringWithZeros(string(a), 3)), TransactionType(padNumericStringWithZeros(string(b), 3))
+	return len(a) == 3 && len(b) == 3 && a[0] == b[0]
+}
+
+// sameInstitution compares institution IDs ignoring the zero padding of parsed records
+func sameInstitution(a, b string) bool {
+	return strings.TrimLeft(a, "0") == strings.TrimLeft(b, "0")
+}
+
+// sameAccountNo compares account numbers ignoring the space padding of parsed records, they only identify the same account within the same institution
+func sameAccountNo(a, b string) bool {
+	return strings.TrimSpace(a) == strings.TrimSpace(b)
+}
+
+func sameItemTraceNo(itemTraceNo, originalItemTraceNo string) bool {
+	if isBlankNumeric(itemTraceNo) {
+		return false
+	}
+	return strings.TrimLeft(itemTraceNo, "0") == strings.TrimLeft(originalItemTraceNo, "0")
+}
+
+// isBlankNumeric reports whether a numeric field is empty or only holds the zeros written in place of a missing value
+func isBlankNumeric(s string) bool {
+	return strings.Trim(s, "0 ") == ""
+}
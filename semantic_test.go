@@ -0,0 +1,109 @@
+package cadeft
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSemantics(t *testing.T) {
+	date := time.Date(2023, time.October, 2, 0, 0, 0, 0, time.UTC)
+	type testCase struct {
+		txn      semanticValidator
+		expected []error
+		rules    []string
+	}
+	cases := map[string]testCase{
+		"valid credit return": {
+			txn: Ptr(NewCreditReturn("450", 100, &date, "000112345", "123456789", "", "SHORT", "PAYEE", "LONG", "000267890", "987654321", "1234", WithStoredTransactionType("470"), WithInvalidDataElementID("19"))),
+		},
+		"credit return with mismatched family and no reason": {
+			txn:      Ptr(NewCreditReturn("450", 100, &date, "000112345", "123456789", "", "SHORT", "PAYEE", "LONG", "000267890", "987654321", "1234", WithStoredTransactionType("200"))),
+			expected: []error{ErrInvalidStoredTxnType, ErrInvalidInvalidDataElementID},
+			rules:    []string{"txn_type_family", "required"},
+		},
+		"debit return with blank stored type pointing at itself": {
+			txn:      Ptr(NewDebitReturn("450", 100, &date, "000112345", "123456789", "0001123450001000000001", "SHORT", "PAYOR", "LONG", "112345", "123456789", "1123450001000000001", WithStoredTransactionType("000"), WithInvalidDataElementID("00000000019"))),
+			expected: []error{ErrInvalidStoredTxnType, ErrInvalidOriginalInstitutionID, ErrInvalidOriginalAccountNo, ErrInvalidOriginalItemTraceNo},
+			rules:    []string{"required", "nefield", "nefield", "nefield"},
+		},
+		"valid debit reverse": {
+			txn: Ptr(NewDebitReverse("450", 100, &date, "000112345", "123456789", "", "SHORT", "PAYOR", "LONG", "000267890", "987654321", "1234", WithInvalidDataElementID("00000000000"))),
+		},
+		"credit reverse with reason and same return account": {
+			txn:      Ptr(NewCreditReverse("450", 100, &date, "000112345", "123456789", "1234", "SHORT", "PAYEE", "LONG", "000112345", "123456789", "1234", WithInvalidDataElementID("19"))),
+			expected: []error{ErrInvalidInvalidDataElementID, ErrInvalidReturnInstitutionID, ErrInvalidReturnAccountNo, ErrInvalidOriginalItemTraceNo},
+			rules:    []string{"excluded", "nefield", "nefield", "nefield"},
+		},
+		"credit return to the same institution with a different account": {
+			txn:      Ptr(NewCreditReturn("450", 100, &date, "000112345", "123456789", "", "SHORT", "PAYEE", "LONG", "112345", "555555555", "1234", WithStoredTransactionType("470"), WithInvalidDataElementID("19"))),
+			expected: []error{ErrInvalidOriginalInstitutionID},
+			rules:    []string{"nefield"},
+		},
+		"debit reverse to the same institution with a different account": {
+			txn:      Ptr(NewDebitReverse("450", 100, &date, "000112345", "123456789", "", "SHORT", "PAYOR", "LONG", "000112345", "555555555", "1234")),
+			expected: []error{ErrInvalidReturnInstitutionID},
+			rules:    []string{"nefield"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			err := tc.txn.ValidateSemantics()
+			if len(tc.expected) == 0 {
+				r.NoError(err)
+				return
+			}
+			var vErrs ValidationErrors
+			r.True(errors.As(err, &vErrs))
+			r.Len(vErrs, len(tc.expected))
+			for i, expected := range tc.expected {
+				r.ErrorIs(vErrs[i], expected)
+				r.Equal(tc.rules[i], vErrs[i].Rule)
+				r.NotZero(vErrs[i].FieldNumber)
+			}
+		})
+	}
+}
+
+func TestSemanticRule(t *testing.T) {
+	r := require.New(t)
+	date := time.Date(2023, time.October, 2, 0, 0, 0, 0, time.UTC)
+	credit := NewCredit("450", 100, &date, "000112345", "123456789", "", "SHORT", "PAYEE", "LONG", "000267890", "987654321")
+	ret := NewCreditReturn("450", 100, &date, "000112345", "123456789", "", "SHORT", "PAYEE", "LONG", "000267890", "987654321", "1234", WithInvalidDataElementID("19"))
+	file := NewFile(NewFileHeader("0000000001", 1, &date, 12345, "CAD"), Transactions{&credit, &ret})
+	r.NoError(file.Validate())
+
+	err := file.Validate(WithRules(SemanticRule))
+	r.ErrorIs(err, ErrInvalidStoredTxnType)
+	var vErr *ValidationError
+	r.True(errors.As(err, &vErr))
+	r.Equal(1, *vErr.TxnIndex)
+	r.Equal(ReturnCreditRecord, vErr.RecordType)
+	r.Equal(10, vErr.FieldNumber)
+}
+
+// TestReturnReasonRoundTrip writes returns as CPA-005 records and checks the parsed records keep their return reason and pass SemanticRule
+func TestReturnReasonRoundTrip(t *testing.T) {
+	r := require.New(t)
+	date := time.Date(2023, time.October, 2, 0, 0, 0, 0, time.UTC)
+	credit := NewCreditReturn("450", 100, &date, "000112345", "123456789", "0002678900000000000001", "SHORT", "PAYEE", "LONG", "000267890", "987654321", "0001123450000000000001", WithStoredTransactionType("450"), WithInvalidDataElementID("905"))
+	debit := NewDebitReturn("470", 200, &date, "000112345", "123456789", "0002678900000000000002", "SHORT", "PAYOR", "LONG", "000267890", "987654321", "0001123450000000000002", WithStoredTransactionType("450"), WithInvalidDataElementID("901"))
+	written := NewFile(NewFileHeader("0000000001", 1, &date, 12345, "CAD"), Transactions{&credit, &debit})
+	contents, err := written.Create()
+	r.NoError(err)
+
+	file, err := NewReader(strings.NewReader(contents)).ReadFile()
+	r.NoError(err)
+	r.NoError(file.ValidateWith(SemanticRule))
+	r.Len(file.Txns, 2)
+	r.Equal("90500000000", file.Txns[0].GetBaseTxn().InvalidDataElementID)
+	r.Equal("905", file.Txns[0].GetBaseTxn().ReturnReasonCode())
+	r.Equal(TransactionType("450"), file.Txns[0].GetBaseTxn().StoredTransactionType)
+	r.Equal("901", file.Txns[1].GetBaseTxn().ReturnReasonCode())
+	r.Empty(BaseTxn{InvalidDataElementID: "00000000000"}.ReturnReasonCode())
+}
@@ -0,0 +1,231 @@
+package cadeft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	minFileCreationNum = 1
+	maxFileCreationNum = 9999
+	// DefaultCreationNumberReuseWindow is the period during which a file creation number may not be reused by the same originator
+	DefaultCreationNumberReuseWindow = 30 * 24 * time.Hour
+)
+
+// CreationNumberSequencer hands out file creation numbers that are unique per originator.
+// Numbers increment from 0001 to 9999 and wrap around back to 0001.
+type CreationNumberSequencer interface {
+	// Next returns the next file creation number for originatorID and records it as used
+	Next(originatorID string) (int64, error)
+	// Use records num as used by originatorID and returns ErrFileCreationNumReused if it was already used within the reuse window
+	Use(originatorID string, num int64) error
+}
+
+type SequencerOpt func(*sequencerConfig)
+
+type sequencerConfig struct {
+	window time.Duration
+	now    func() time.Time
+}
+
+// WithReuseWindow overrides DefaultCreationNumberReuseWindow
+func WithReuseWindow(window time.Duration) SequencerOpt {
+	return func(sc *sequencerConfig) {
+		sc.window = window
+	}
+}
+
+// WithSequencerClock overrides the clock used to timestamp file creation numbers, mainly used for testing
+func WithSequencerClock(now func() time.Time) SequencerOpt {
+	return func(sc *sequencerConfig) {
+		sc.now = now
+	}
+}
+
+func newSequencerConfig(opts []SequencerOpt) sequencerConfig {
+	cfg := sequencerConfig{
+		window: DefaultCreationNumberReuseWindow,
+		now:    time.Now,
+	}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	return cfg
+}
+
+// sequenceState is the persisted state of a single originator
+type sequenceState struct {
+	Last int64               `json:"last"`
+	Used map[int64]time.Time `json:"used"`
+}
+
+func (s *sequenceState) next(cfg sequencerConfig) (int64, error) {
+	num := s.Last + 1
+	if num > maxFileCreationNum || num < minFileCreationNum {
+		num = minFileCreationNum
+	}
+	if err := s.use(num, cfg); err != nil {
+		return 0, err
+	}
+	return num, nil
+}
+
+func (s *sequenceState) use(num int64, cfg sequencerConfig) error {
+	if num < minFileCreationNum || num > maxFileCreationNum {
+		return newError(msgErrNumber, ErrInvalidFileCreationNum, num)
+	}
+	now := cfg.now()
+	if s.Used == nil {
+		s.Used = make(map[int64]time.Time)
+	}
+	// forget numbers that have fallen out of the reuse window
+	for n, usedAt := range s.Used {
+		if now.Sub(usedAt) >= cfg.window {
+			delete(s.Used, n)
+		}
+	}
+	if usedAt, ok := s.Used[num]; ok {
+		return newError(msgCreationNumUsed, ErrFileCreationNumReused, num, usedAt.Format(time.RFC3339))
+	}
+	s.Used[num] = now
+	s.Last = num
+	return nil
+}
+
+// MemorySequencer is an in-memory CreationNumberSequencer that is safe for concurrent use.
+// State is lost when the process exits, use FileSequencer to persist file creation numbers.
+type MemorySequencer struct {
+	mu     sync.Mutex
+	cfg    sequencerConfig
+	states map[string]*sequenceState
+}
+
+func NewMemorySequencer(opts ...SequencerOpt) *MemorySequencer {
+	return &MemorySequencer{
+		cfg:    newSequencerConfig(opts),
+		states: make(map[string]*sequenceState),
+	}
+}
+
+func (m *MemorySequencer) Next(originatorID string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state(originatorID).next(m.cfg)
+}
+
+func (m *MemorySequencer) Use(originatorID string, num int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state(originatorID).use(num, m.cfg)
+}
+
+func (m *MemorySequencer) state(originatorID string) *sequenceState {
+	s, ok := m.states[originatorID]
+	if !ok {
+		s = &sequenceState{}
+		m.states[originatorID] = s
+	}
+	return s
+}
+
+// FileSequencer is a CreationNumberSequencer that persists the state of every originator to a JSON file.
+// Access to the file is serialized with an exclusive lock on a sibling ".lock" file so that several
+// processes can share the same FileSequencer path.
+type FileSequencer struct {
+	mu   sync.Mutex
+	path string
+	cfg  sequencerConfig
+}
+
+func NewFileSequencer(path string, opts ...SequencerOpt) *FileSequencer {
+	return &FileSequencer{
+		path: path,
+		cfg:  newSequencerConfig(opts),
+	}
+}
+
+func (fs *FileSequencer) Next(originatorID string) (int64, error) {
+	var num int64
+	err := fs.update(originatorID, func(s *sequenceState) error {
+		var err error
+		num, err = s.next(fs.cfg)
+		return err
+	})
+	return num, err
+}
+
+func (fs *FileSequencer) Use(originatorID string, num int64) error {
+	return fs.update(originatorID, func(s *sequenceState) error {
+		return s.use(num, fs.cfg)
+	})
+}
+
+func (fs *FileSequencer) update(originatorID string, fn func(*sequenceState) error) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	lock, err := os.OpenFile(fs.path+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open sequencer lock: %w", err)
+	}
+	defer lock.Close()
+	if err := lockFile(lock); err != nil {
+		return fmt.Errorf("failed to lock sequencer: %w", err)
+	}
+	defer func() {
+		_ = unlockFile(lock)
+	}()
+
+	states := make(map[string]*sequenceState)
+	contents, err := os.ReadFile(fs.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read sequencer state: %w", err)
+	}
+	if len(contents) > 0 {
+		if err := json.Unmarshal(contents, &states); err != nil {
+			return fmt.Errorf("failed to decode sequencer state: %w", err)
+		}
+	}
+	s, ok := states[originatorID]
+	if !ok {
+		s = &sequenceState{}
+		states[originatorID] = s
+	}
+	if err := fn(s); err != nil {
+		return err
+	}
+
+	contents, err = json.Marshal(states)
+	if err != nil {
+		return fmt.Errorf("failed to encode sequencer state: %w", err)
+	}
+	// write to a temporary file first so a crash never leaves a truncated state file behind
+	tmp := fs.path + ".tmp"
+	if err := os.WriteFile(tmp, contents, 0o600); err != nil {
+		return fmt.Errorf("failed to write sequencer state: %w", err)
+	}
+	if err := os.Rename(tmp, fs.path); err != nil {
+		return fmt.Errorf("failed to write sequencer state: %w", err)
+	}
+	return nil
+}
+
+// NewSequencedFileHeader creates a FileHeader like NewFileHeader whose file creation number is the next number handed out by seq for originatorID.
+// Any error returned by seq is returned and no header is created.
+func NewSequencedFileHeader(
+	seq CreationNumberSequencer,
+	originatorID string,
+	creationDate *time.Time,
+	destinationDataCenterNo int64,
+	currencyCode string,
+	opts ...HeaderOpts) (*FileHeader, error) {
+	num, err := seq.Next(originatorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file creation number for originator %s: %w", originatorID, err)
+	}
+	return NewFileHeader(originatorID, num, creationDate, destinationDataCenterNo, currencyCode, opts...), nil
+}
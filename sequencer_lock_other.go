@@ -0,0 +1,35 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package cadeft
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// lockTimeout bounds the wait for a lock file left behind by another process
+const lockTimeout = 10 * time.Second
+
+// lockFile falls back to an exclusively created sibling lock file on platforms without file locks
+func lockFile(f *os.File) error {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		excl, err := os.OpenFile(f.Name()+".excl", os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			return excl.Close()
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s.excl is held by another process, remove it if no other process is running", f.Name())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func unlockFile(f *os.File) error {
+	return os.Remove(f.Name() + ".excl")
+}
@@ -0,0 +1,123 @@
+package cadeft
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreationNumberSequencer(t *testing.T) {
+	now := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	sequencers := map[string]func() CreationNumberSequencer{
+		"memory": func() CreationNumberSequencer {
+			return NewMemorySequencer(WithSequencerClock(clock))
+		},
+		"file": func() CreationNumberSequencer {
+			return NewFileSequencer(filepath.Join(t.TempDir(), "sequence.json"), WithSequencerClock(clock))
+		},
+	}
+	for name, newSeq := range sequencers {
+		t.Run(name, func(t *testing.T) {
+			t.Run("increments per originator", func(t *testing.T) {
+				r := require.New(t)
+				seq := newSeq()
+				num, err := seq.Next("0000000001")
+				r.NoError(err)
+				r.Equal(int64(1), num)
+				num, err = seq.Next("0000000001")
+				r.NoError(err)
+				r.Equal(int64(2), num)
+				num, err = seq.Next("0000000002")
+				r.NoError(err)
+				r.Equal(int64(1), num)
+			})
+
+			t.Run("wraps around", func(t *testing.T) {
+				r := require.New(t)
+				seq := newSeq()
+				r.NoError(seq.Use("0000000001", 9999))
+				num, err := seq.Next("0000000001")
+				r.NoError(err)
+				r.Equal(int64(1), num)
+			})
+
+			t.Run("detects reuse", func(t *testing.T) {
+				r := require.New(t)
+				seq := newSeq()
+				r.NoError(seq.Use("0000000001", 5))
+				r.ErrorIs(seq.Use("0000000001", 5), ErrFileCreationNumReused)
+				r.NoError(seq.Use("0000000002", 5))
+				r.ErrorIs(seq.Use("0000000001", 10000), ErrInvalidFileCreationNum)
+			})
+		})
+	}
+}
+
+func TestCreationNumberSequencerReuseWindow(t *testing.T) {
+	r := require.New(t)
+	now := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	seq := NewMemorySequencer(WithReuseWindow(24*time.Hour), WithSequencerClock(func() time.Time { return now }))
+	r.NoError(seq.Use("0000000001", 5))
+	now = now.Add(23 * time.Hour)
+	r.ErrorIs(seq.Use("0000000001", 5), ErrFileCreationNumReused)
+	now = now.Add(time.Hour)
+	r.NoError(seq.Use("0000000001", 5))
+}
+
+func TestFileSequencerPersists(t *testing.T) {
+	r := require.New(t)
+	path := filepath.Join(t.TempDir(), "sequence.json")
+	num, err := NewFileSequencer(path).Next("0000000001")
+	r.NoError(err)
+	r.Equal(int64(1), num)
+	num, err = NewFileSequencer(path).Next("0000000001")
+	r.NoError(err)
+	r.Equal(int64(2), num)
+
+	// several sequencers sharing the same path never hand out the same number
+	var mu sync.Mutex
+	seen := make(map[int64]struct{})
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			num, err := NewFileSequencer(path).Next("0000000001")
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			seen[num] = struct{}{}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	r.Len(seen, 10)
+}
+
+func TestNewSequencedFileHeader(t *testing.T) {
+	r := require.New(t)
+	date := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	seq := NewMemorySequencer()
+	r.NoError(seq.Use("0000000001", 41))
+
+	header, err := NewSequencedFileHeader(seq, "0000000001", &date, 12345, "CAD", WithDirectClearerCommunicationArea("AREA"))
+	r.NoError(err)
+	r.Equal(int64(42), header.FileCreationNum)
+	r.Equal("AREA", header.DirectClearerCommunicationArea)
+	r.NoError(header.Validate())
+
+	full := NewMemorySequencer()
+	r.NoError(full.Use("0000000001", 9999))
+	r.NoError(full.Use("0000000001", 1))
+	for i := int64(2); i < 9999; i++ {
+		r.NoError(full.Use("0000000001", i))
+	}
+	header, err = NewSequencedFileHeader(full, "0000000001", &date, 12345, "CAD")
+	r.ErrorIs(err, ErrFileCreationNumReused)
+	r.Nil(header)
+}
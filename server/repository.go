@@ -0,0 +1,73 @@
+package server
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/moov-io/cadeft/api"
+)
+
+var ErrFileNotFound = errors.New("file not found")
+
+// Repository keeps the files created and parsed through the server, Get and Delete return ErrFileNotFound for unknown IDs
+type Repository interface {
+	Save(f api.StoredFile) error
+	Get(id string) (api.StoredFile, error)
+	// List returns every file ordered by creation time
+	List() ([]api.StoredFile, error)
+	Delete(id string) error
+}
+
+type inMemoryRepository struct {
+	mu    sync.RWMutex
+	files map[string]api.StoredFile
+}
+
+// NewInMemoryRepository returns a Repository keeping files in memory, they are lost when the server stops
+func NewInMemoryRepository() Repository {
+	return &inMemoryRepository{files: map[string]api.StoredFile{}}
+}
+
+func (r *inMemoryRepository) Save(f api.StoredFile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.files[f.ID] = f
+	return nil
+}
+
+func (r *inMemoryRepository) Get(id string) (api.StoredFile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.files[id]
+	if !ok {
+		return api.StoredFile{}, ErrFileNotFound
+	}
+	return f, nil
+}
+
+func (r *inMemoryRepository) List() ([]api.StoredFile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	files := make([]api.StoredFile, 0, len(r.files))
+	for _, f := range r.files {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].CreatedAt.Equal(files[j].CreatedAt) {
+			return files[i].ID < files[j].ID
+		}
+		return files[i].CreatedAt.Before(files[j].CreatedAt)
+	})
+	return files, nil
+}
+
+func (r *inMemoryRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.files[id]; !ok {
+		return ErrFileNotFound
+	}
+	delete(r.files, id)
+	return nil
+}
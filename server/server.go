@@ -0,0 +1,281 @@
+// Package server serves cadeft over HTTP: files are created from JSON or parsed from CPA-005, validated, and kept in a Repository
+// from which they can be fetched, listed and deleted. The server only listens on loopback addresses.
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/moov-io/cadeft"
+	"github.com/moov-io/cadeft/api"
+)
+
+// DefaultAddr is the address the server listens on when none is given
+const DefaultAddr = "localhost:8088"
+
+// maxBodySize is the largest request body read, CPA-005 files are at most a few megabytes
+const maxBodySize = 32 << 20
+
+var ErrNotLoopback = errors.New("address is not a loopback address")
+
+type Server struct {
+	repo Repository
+	now  func() time.Time
+}
+
+type Opt func(*Server)
+
+// WithRepository sets the Repository files are kept in, an in-memory repository by default
+func WithRepository(repo Repository) Opt {
+	return func(s *Server) {
+		s.repo = repo
+	}
+}
+
+func NewServer(opts ...Opt) *Server {
+	s := &Server{now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.repo == nil {
+		s.repo = NewInMemoryRepository()
+	}
+	return s
+}
+
+// Handler returns the routes of the server:
+//
+//	GET    /health               health check
+//	POST   /files                create a file from JSON
+//	POST   /files/parse          parse a CPA-005 file sent as body or as the file field of a multipart form
+//	POST   /files/validate       validate a file sent as JSON without storing it
+//	GET    /files                list stored files
+//	GET    /files/{id}           fetch a stored file as JSON
+//	GET    /files/{id}/contents  fetch a stored file as CPA-005
+//	GET    /files/{id}/validate  validate a stored file
+//	DELETE /files/{id}           delete a stored file
+//
+// Validation accepts a profile query parameter naming a registered validation profile.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", s.health)
+	mux.HandleFunc("POST /files", s.createFile)
+	mux.HandleFunc("POST /files/parse", s.parseFile)
+	mux.HandleFunc("POST /files/validate", s.validateFile)
+	mux.HandleFunc("GET /files", s.listFiles)
+	mux.HandleFunc("GET /files/{id}", s.getFile)
+	mux.HandleFunc("GET /files/{id}/contents", s.getFileContents)
+	mux.HandleFunc("GET /files/{id}/validate", s.validateStoredFile)
+	mux.HandleFunc("DELETE /files/{id}", s.deleteFile)
+	return mux
+}
+
+// Listen listens on addr, which must be a loopback address such as localhost:8088 or 127.0.0.1:8088
+func Listen(addr string) (net.Listener, error) {
+	if err := checkLoopback(addr); err != nil {
+		return nil, err
+	}
+	return net.Listen("tcp", addr)
+}
+
+func checkLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrNotLoopback, addr)
+}
+
+func (s *Server) health(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) createFile(w http.ResponseWriter, r *http.Request) {
+	var f cadeft.File
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&f); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid file JSON: %w", err))
+		return
+	}
+	if f.Header == nil {
+		writeError(w, http.StatusBadRequest, cadeft.ErrMissingHeader)
+		return
+	}
+	s.store(w, f)
+}
+
+func (s *Server) parseFile(w http.ResponseWriter, r *http.Request) {
+	in, err := uploadedFile(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	defer in.Close()
+	f, err := cadeft.NewReader(in).ReadFile()
+	if err == nil && f.Header == nil {
+		err = cadeft.ErrMissingHeader
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("failed to parse file: %w", err))
+		return
+	}
+	s.store(w, f)
+}
+
+// uploadedFile returns the file field of a multipart form or the request body
+func uploadedFile(w http.ResponseWriter, r *http.Request) (io.ReadCloser, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.Body, nil
+	}
+	in, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the file field of the form: %w", err)
+	}
+	return in, nil
+}
+
+func (s *Server) store(w http.ResponseWriter, f cadeft.File) {
+	id, err := newID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	stored := api.StoredFile{ID: id, CreatedAt: s.now().UTC(), File: f}
+	if err := s.repo.Save(stored); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Location", "/files/"+id)
+	writeJSON(w, http.StatusCreated, stored)
+}
+
+func (s *Server) validateFile(w http.ResponseWriter, r *http.Request) {
+	var f cadeft.File
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&f); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid file JSON: %w", err))
+		return
+	}
+	validate(w, r, f)
+}
+
+func (s *Server) validateStoredFile(w http.ResponseWriter, r *http.Request) {
+	stored, ok := s.get(w, r)
+	if !ok {
+		return
+	}
+	validate(w, r, stored.File)
+}
+
+// validate writes the api.ValidationResult of f, errors other than validation errors such as an unknown profile fail the request
+func validate(w http.ResponseWriter, r *http.Request, f cadeft.File) {
+	var opts []cadeft.ValidateOpt
+	if profile := r.URL.Query().Get("profile"); profile != "" {
+		opts = append(opts, cadeft.WithProfile(profile))
+	}
+	err := f.Validate(opts...)
+	var validationErrs cadeft.ValidationErrors
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, api.ValidationResult{Valid: true})
+	case errors.As(err, &validationErrs):
+		result := api.ValidationResult{Errors: make([]api.ValidationError, 0, len(validationErrs))}
+		for _, v := range validationErrs {
+			result.Errors = append(result.Errors, api.NewValidationError(v))
+		}
+		writeJSON(w, http.StatusOK, result)
+	default:
+		writeError(w, http.StatusBadRequest, err)
+	}
+}
+
+func (s *Server) listFiles(w http.ResponseWriter, _ *http.Request) {
+	files, err := s.repo.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	summaries := make([]api.FileSummary, 0, len(files))
+	for _, f := range files {
+		summaries = append(summaries, api.NewFileSummary(f))
+	}
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+func (s *Server) getFile(w http.ResponseWriter, r *http.Request) {
+	if stored, ok := s.get(w, r); ok {
+		writeJSON(w, http.StatusOK, stored)
+	}
+}
+
+func (s *Server) getFileContents(w http.ResponseWriter, r *http.Request) {
+	stored, ok := s.get(w, r)
+	if !ok {
+		return
+	}
+	contents, err := stored.File.Create()
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Errorf("failed to write file: %w", err))
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, contents)
+}
+
+func (s *Server) deleteFile(w http.ResponseWriter, r *http.Request) {
+	err := s.repo.Delete(r.PathValue("id"))
+	switch {
+	case errors.Is(err, ErrFileNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// get returns the file named by the id path parameter, a failed lookup is written as the response
+func (s *Server) get(w http.ResponseWriter, r *http.Request) (api.StoredFile, bool) {
+	stored, err := s.repo.Get(r.PathValue("id"))
+	switch {
+	case errors.Is(err, ErrFileNotFound):
+		writeError(w, http.StatusNotFound, err)
+		return stored, false
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+		return stored, false
+	}
+	return stored, true
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate file ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, api.ErrorResponse{Error: err.Error()})
+}
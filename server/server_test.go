@@ -0,0 +1,167 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moov-io/cadeft"
+	"github.com/moov-io/cadeft/api"
+	"github.com/stretchr/testify/require"
+)
+
+func testFile() cadeft.File {
+	date := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	return cadeft.NewFile(cadeft.NewFileHeader("0000000001", 1, &date, 12345, "CAD"), []cadeft.Transaction{
+		cadeft.Ptr(cadeft.NewCredit("450", 1000, &date, "123456789", "12345", "12313213", "short name", "payee name", "someone", "1231", "12345")),
+		cadeft.Ptr(cadeft.NewDebit("450", 2500, &date, "123456789", "54321", "12313214", "short name", "payor name", "someone", "1231", "12345")),
+	})
+}
+
+func do(t *testing.T, h http.Handler, method, target string, body io.Reader, contentType string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, body)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &v), rec.Body.String())
+	return v
+}
+
+func TestHealth(t *testing.T) {
+	rec := do(t, NewServer().Handler(), http.MethodGet, "/health", nil, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
+
+func TestFiles(t *testing.T) {
+	r := require.New(t)
+	h := NewServer().Handler()
+	body, err := json.Marshal(testFile())
+	r.NoError(err)
+
+	rec := do(t, h, http.MethodPost, "/files", bytes.NewReader(body), "application/json")
+	r.Equal(http.StatusCreated, rec.Code, rec.Body.String())
+	created := decode[api.StoredFile](t, rec)
+	r.NotEmpty(created.ID)
+	r.Equal("/files/"+created.ID, rec.Header().Get("Location"))
+	r.Len(created.File.Txns, 2)
+
+	rec = do(t, h, http.MethodGet, "/files/"+created.ID, nil, "")
+	r.Equal(http.StatusOK, rec.Code)
+	fetched := decode[api.StoredFile](t, rec)
+	r.Equal(created.ID, fetched.ID)
+	r.Equal("0000000001", fetched.File.Header.OriginatorID)
+	r.Equal(int64(2500), fetched.File.Txns[1].GetAmount())
+
+	rec = do(t, h, http.MethodGet, "/files/"+created.ID+"/contents", nil, "")
+	r.Equal(http.StatusOK, rec.Code)
+	r.Equal("A0000000000000000001", rec.Body.String()[:20])
+
+	rec = do(t, h, http.MethodGet, "/files/"+created.ID+"/validate", nil, "")
+	r.Equal(http.StatusOK, rec.Code)
+	r.Equal(api.ValidationResult{Valid: true}, decode[api.ValidationResult](t, rec))
+
+	sample, err := os.Open("../sample_files/CO14821.txt")
+	r.NoError(err)
+	defer sample.Close()
+	rec = do(t, h, http.MethodPost, "/files/parse", sample, "text/plain")
+	r.Equal(http.StatusCreated, rec.Code, rec.Body.String())
+	parsed := decode[api.StoredFile](t, rec)
+	r.NotEmpty(parsed.File.Txns)
+
+	rec = do(t, h, http.MethodGet, "/files", nil, "")
+	r.Equal(http.StatusOK, rec.Code)
+	summaries := decode[[]api.FileSummary](t, rec)
+	r.Len(summaries, 2)
+	r.Equal(created.ID, summaries[0].ID)
+	r.Equal(2, summaries[0].TxnCount)
+	r.Equal(parsed.ID, summaries[1].ID)
+
+	rec = do(t, h, http.MethodDelete, "/files/"+created.ID, nil, "")
+	r.Equal(http.StatusNoContent, rec.Code)
+	for _, target := range []string{"/files/" + created.ID, "/files/" + created.ID + "/contents", "/files/" + created.ID + "/validate"} {
+		rec = do(t, h, http.MethodGet, target, nil, "")
+		r.Equal(http.StatusNotFound, rec.Code, target)
+		r.Equal(ErrFileNotFound.Error(), decode[api.ErrorResponse](t, rec).Error)
+	}
+	rec = do(t, h, http.MethodDelete, "/files/"+created.ID, nil, "")
+	r.Equal(http.StatusNotFound, rec.Code)
+}
+
+func TestParseMultipart(t *testing.T) {
+	r := require.New(t)
+	sample, err := os.ReadFile("../sample_files/CO14821.txt")
+	r.NoError(err)
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "CO14821.txt")
+	r.NoError(err)
+	_, err = part.Write(sample)
+	r.NoError(err)
+	r.NoError(form.Close())
+
+	h := NewServer().Handler()
+	rec := do(t, h, http.MethodPost, "/files/parse", &body, form.FormDataContentType())
+	r.Equal(http.StatusCreated, rec.Code, rec.Body.String())
+	r.Equal("0000000610", decode[api.StoredFile](t, rec).File.Header.OriginatorID)
+
+	rec = do(t, h, http.MethodPost, "/files/parse", strings.NewReader("not a file"), "text/plain")
+	r.Equal(http.StatusBadRequest, rec.Code)
+	r.Contains(decode[api.ErrorResponse](t, rec).Error, "failed to parse file")
+}
+
+func TestValidate(t *testing.T) {
+	r := require.New(t)
+	h := NewServer().Handler()
+	f := testFile()
+	f.Txns[1].(*cadeft.Debit).PayorAccountNo = "ACCOUNT"
+	body, err := json.Marshal(f)
+	r.NoError(err)
+
+	rec := do(t, h, http.MethodPost, "/files/validate", bytes.NewReader(body), "application/json")
+	r.Equal(http.StatusOK, rec.Code)
+	result := decode[api.ValidationResult](t, rec)
+	r.False(result.Valid)
+	r.Len(result.Errors, 1)
+	r.Equal(1, *result.Errors[0].TxnIndex)
+	r.Equal("payor_account_no", result.Errors[0].JSONField)
+	r.Equal("numeric", result.Errors[0].Rule)
+	r.NotEmpty(result.Errors[0].Message)
+
+	rec = do(t, h, http.MethodPost, "/files/validate?profile=unknown", bytes.NewReader(body), "application/json")
+	r.Equal(http.StatusBadRequest, rec.Code)
+	rec = do(t, h, http.MethodPost, "/files/validate", strings.NewReader("{"), "application/json")
+	r.Equal(http.StatusBadRequest, rec.Code)
+	rec = do(t, h, http.MethodPost, "/files", strings.NewReader("{}"), "application/json")
+	r.Equal(http.StatusBadRequest, rec.Code)
+}
+
+func TestListen(t *testing.T) {
+	for _, addr := range []string{"localhost:0", "127.0.0.1:0", "[::1]:0"} {
+		err := checkLoopback(addr)
+		require.NoError(t, err, addr)
+	}
+	for _, addr := range []string{":8088", "0.0.0.0:8088", "192.168.1.10:8088", "example.com:80", "localhost"} {
+		_, err := Listen(addr)
+		require.Error(t, err, addr)
+	}
+	l, err := Listen("127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+}
@@ -0,0 +1,116 @@
+package cadeft
+
+// SplitLimits bounds the content of every file returned by SplitFile, zero values mean no limit
+type SplitLimits struct {
+	// MaxItems is the maximum number of transactions per file
+	MaxItems int `json:"max_items,omitempty" yaml:"max_items,omitempty"`
+	// MaxValue is the maximum total amount in cents of the transactions of a file
+	MaxValue int64 `json:"max_value,omitempty" yaml:"max_value,omitempty"`
+	// ByRecordType keeps every record type in files of its own
+	ByRecordType bool `json:"by_record_type,omitempty" yaml:"by_record_type,omitempty"`
+	// Sequencer, when set, hands out the FileCreationNum of every file after the first one,
+	// otherwise they are incremented from the FileCreationNum of the original file wrapping from 9999 to 1
+	Sequencer CreationNumberSequencer `json:"-" yaml:"-"`
+}
+
+// SplitFile partitions the transactions of f into as many files as needed to respect limits, keeping the order of the transactions.
+// The first file keeps the FileCreationNum of f and every file gets its own copy of the header and a footer computed from its transactions.
+// Item trace numbers are kept as is, assign them after splitting when they have to embed the FileCreationNum.
+func SplitFile(f File, limits SplitLimits) ([]File, error) {
+	if f.Header == nil {
+		return nil, ErrMissingHeader
+	}
+	if limits.MaxItems < 0 || limits.MaxValue < 0 {
+		return nil, newError(msgNegativeSplitLimits)
+	}
+
+	groups := []Transactions{f.Txns}
+	if limits.ByRecordType {
+		groups = groupByRecordType(f.Txns)
+	}
+	batches := make([]Transactions, 0, len(groups))
+	for _, group := range groups {
+		split, err := splitTxns(group, limits)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, split...)
+	}
+	if len(batches) == 0 {
+		batches = append(batches, Transactions{})
+	}
+
+	files := make([]File, 0, len(batches))
+	fileCreationNum := f.Header.FileCreationNum
+	for i, batch := range batches {
+		if i > 0 {
+			next, err := nextSplitCreationNum(limits.Sequencer, f.Header.OriginatorID, fileCreationNum)
+			if err != nil {
+				return nil, newError(msgSplitCreationNum, i+1, err)
+			}
+			fileCreationNum = next
+		}
+		header := *f.Header
+		header.FileCreationNum = fileCreationNum
+		footer := NewFileFooter(RecordHeader{
+			RecordType:      FooterRecord,
+			OriginatorID:    header.OriginatorID,
+			FileCreationNum: fileCreationNum,
+		}, batch)
+		files = append(files, File{Header: &header, Txns: batch, Footer: footer})
+	}
+	return files, nil
+}
+
+// splitTxns greedily fills batches with txns until adding the next one would exceed limits
+func splitTxns(txns Transactions, limits SplitLimits) ([]Transactions, error) {
+	var batches []Transactions
+	var current Transactions
+	var value Cents
+	for i, t := range txns {
+		amount := Cents(t.GetAmount())
+		if limits.MaxValue > 0 && int64(amount) > limits.MaxValue {
+			return nil, newError(msgTxn, i, newError(msgSplitAmount, ErrSplitLimitExceeded, amount, Cents(limits.MaxValue)))
+		}
+		total, err := value.Add(amount)
+		full := len(current) > 0 &&
+			((limits.MaxItems > 0 && len(current) >= limits.MaxItems) ||
+				(limits.MaxValue > 0 && (err != nil || int64(total) > limits.MaxValue)))
+		if full {
+			batches = append(batches, current)
+			current, total = nil, amount
+		}
+		current = append(current, t)
+		value = total
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches, nil
+}
+
+// groupByRecordType returns the transactions of every record type in the order the record types first appear
+func groupByRecordType(txns Transactions) []Transactions {
+	index := make(map[RecordType]int)
+	var groups []Transactions
+	for _, t := range txns {
+		i, ok := index[t.GetType()]
+		if !ok {
+			i = len(groups)
+			index[t.GetType()] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], t)
+	}
+	return groups
+}
+
+func nextSplitCreationNum(seq CreationNumberSequencer, originatorID string, previous int64) (int64, error) {
+	if seq != nil {
+		return seq.Next(originatorID)
+	}
+	if previous >= maxFileCreationNum {
+		return 1, nil
+	}
+	return previous + 1, nil
+}
@@ -0,0 +1,99 @@
+package cadeft
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitFile(t *testing.T) {
+	date := time.Date(2023, time.October, 2, 0, 0, 0, 0, time.UTC)
+	credit := func(amount int64) Transaction {
+		return Ptr(NewCredit("450", amount, &date, "123456789", "123456789012", "", "SHORT", "PAYEE", "LONG", "123456789", "210987654321"))
+	}
+	debit := func(amount int64) Transaction {
+		return Ptr(NewDebit("450", amount, &date, "123456789", "123456789012", "", "SHORT", "PAYOR", "LONG", "123456789", "210987654321"))
+	}
+	newFile := func(fileCreationNum int64, txns ...Transaction) File {
+		return NewFile(NewFileHeader("0000000001", fileCreationNum, &date, 12345, "CAD"), txns)
+	}
+	amounts := func(f File) []int64 {
+		out := make([]int64, 0, len(f.Txns))
+		for _, t := range f.Txns {
+			out = append(out, t.GetAmount())
+		}
+		return out
+	}
+
+	t.Run("by item count", func(t *testing.T) {
+		r := require.New(t)
+		original := newFile(9998, credit(1), credit(2), debit(3), credit(4), debit(5))
+		files, err := SplitFile(original, SplitLimits{MaxItems: 2})
+		r.NoError(err)
+		r.Len(files, 3)
+		r.Equal([]int64{9998, 9999, 1}, []int64{files[0].Header.FileCreationNum, files[1].Header.FileCreationNum, files[2].Header.FileCreationNum})
+		r.Equal([]int64{1, 2}, amounts(files[0]))
+		r.Equal([]int64{3, 4}, amounts(files[1]))
+		r.Equal([]int64{5}, amounts(files[2]))
+		r.Equal(int64(4), files[1].Footer.TotalValueOfCredit)
+		r.Equal(int64(3), files[1].Footer.TotalValueOfDebit)
+		r.Equal(int64(9999), files[1].Footer.FileCreationNum)
+		// the original header is left untouched
+		r.Equal(int64(9998), original.Header.FileCreationNum)
+
+		for _, f := range files {
+			s, err := f.Create()
+			r.NoError(err)
+			parsed, err := NewReader(strings.NewReader(s)).ReadFile()
+			r.NoError(err)
+			r.Len(parsed.Txns, len(f.Txns))
+			r.Equal(f.Footer.TotalValueOfCredit, parsed.Footer.TotalValueOfCredit)
+		}
+	})
+
+	t.Run("by value and record type", func(t *testing.T) {
+		r := require.New(t)
+		files, err := SplitFile(newFile(1, credit(600), debit(100), credit(500), credit(300), debit(200)), SplitLimits{MaxValue: 1000, ByRecordType: true})
+		r.NoError(err)
+		r.Len(files, 3)
+		r.Equal([]int64{600}, amounts(files[0]))
+		r.Equal([]int64{500, 300}, amounts(files[1]))
+		r.Equal([]int64{100, 200}, amounts(files[2]))
+		r.Equal(int64(3), files[2].Header.FileCreationNum)
+	})
+
+	t.Run("with sequencer", func(t *testing.T) {
+		r := require.New(t)
+		seq := NewMemorySequencer()
+		r.NoError(seq.Use("0000000001", 41))
+		files, err := SplitFile(newFile(7, credit(1), credit(2)), SplitLimits{MaxItems: 1, Sequencer: seq})
+		r.NoError(err)
+		r.Equal(int64(7), files[0].Header.FileCreationNum)
+		r.Equal(int64(42), files[1].Header.FileCreationNum)
+	})
+
+	t.Run("no limits and empty files", func(t *testing.T) {
+		r := require.New(t)
+		files, err := SplitFile(newFile(1, credit(1), debit(2)), SplitLimits{})
+		r.NoError(err)
+		r.Len(files, 1)
+		r.Len(files[0].Txns, 2)
+
+		files, err = SplitFile(newFile(1), SplitLimits{MaxItems: 1})
+		r.NoError(err)
+		r.Len(files, 1)
+		r.Empty(files[0].Txns)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		r := require.New(t)
+		_, err := SplitFile(newFile(1, credit(2000)), SplitLimits{MaxValue: 1000})
+		r.ErrorIs(err, ErrSplitLimitExceeded)
+		_, err = SplitFile(File{}, SplitLimits{})
+		r.ErrorIs(err, ErrMissingHeader)
+		_, err = SplitFile(newFile(1), SplitLimits{MaxItems: -1})
+		r.Error(err)
+	})
+}
@@ -0,0 +1,615 @@
+package cadeft
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/fr"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	frTranslations "github.com/go-playground/validator/v10/translations/fr"
+	"github.com/hashicorp/go-multierror"
+)
+
+// Locale is the language errors are rendered in by Translate
+type Locale string
+
+const (
+	English Locale = "en"
+	French  Locale = "fr"
+)
+
+// catalog holds everything needed to render errors in one locale
+type catalog struct {
+	locale     Locale
+	translator ut.Translator
+	// separator is placed between a message and the error it wraps
+	separator string
+	// formats of the fixed parts of ValidationError and aggregated errors
+	fieldFormat        string
+	txnFieldFormat     string
+	ruleFormat         string
+	validationErrors   string
+	multipleErrors     string
+	singleErrorOccured string
+	mergeConflicts     string
+	traceNoConflict    string
+	headerConflict     string
+}
+
+var catalogs = map[Locale]*catalog{
+	English: {
+		locale:             English,
+		separator:          ": ",
+		fieldFormat:        "record %s field %02d %s",
+		txnFieldFormat:     "txn %d record %s field %02d %s",
+		ruleFormat:         "failed %q validation",
+		validationErrors:   "%d validation errors occurred",
+		multipleErrors:     "%d errors occurred",
+		singleErrorOccured: "1 error occurred",
+		mergeConflicts:     "%d merge conflicts",
+		traceNoConflict:    "file %d txn %d %s %s is used by file %d txn %d",
+		headerConflict:     "file %d %s %q does not match %q of file %d",
+	},
+	French: {
+		locale:             French,
+		separator:          " : ",
+		fieldFormat:        "enregistrement %s champ %02d %s",
+		txnFieldFormat:     "transaction %d enregistrement %s champ %02d %s",
+		ruleFormat:         "échec de la validation « %s »",
+		validationErrors:   "%d erreurs de validation sont survenues",
+		multipleErrors:     "%d erreurs sont survenues",
+		singleErrorOccured: "1 erreur est survenue",
+		mergeConflicts:     "%d conflits de fusion",
+		traceNoConflict:    "fichier %d transaction %d %s %s est utilisé par le fichier %d transaction %d",
+		headerConflict:     "fichier %d %s %q ne correspond pas à %q du fichier %d",
+	},
+}
+
+// customTagTranslations are the messages of the validation tags registered by cadeft
+var customTagTranslations = map[Locale]map[string]string{
+	English: {
+		eftAlphaKey:    "{0} must only contain letters, digits, spaces, underscores or hyphens",
+		eftNumericKey:  "{0} must only contain digits",
+		eftRecTypeKey:  "{0} must be a valid record type",
+		eftCurrencyKey: "{0} must be CAD or USD",
+	},
+	French: {
+		eftAlphaKey:    "{0} ne doit contenir que des lettres, des chiffres, des espaces, des traits de soulignement ou des traits d'union",
+		eftNumericKey:  "{0} ne doit contenir que des chiffres",
+		eftRecTypeKey:  "{0} doit être un type d'enregistrement valide",
+		eftCurrencyKey: "{0} doit être CAD ou USD",
+	},
+}
+
+const (
+	// sentinel errors
+	msgInvalidRecordLength                   MessageID = "invalid_record_length"
+	msgMissingHeader                         MessageID = "missing_header"
+	msgMissingOriginatorId                   MessageID = "missing_originator_id"
+	msgMissingCreationDate                   MessageID = "missing_creation_date"
+	msgInvalidRecordType                     MessageID = "invalid_record_type"
+	msgInvalidCurrencyCode                   MessageID = "invalid_currency_code"
+	msgInvalidOriginatorIdLength             MessageID = "invalid_originator_id_length"
+	msgInvalidOriginatorId                   MessageID = "invalid_originator_id"
+	msgInvalidFileCreationNum                MessageID = "invalid_file_creation_num"
+	msgFileCreationNumReused                 MessageID = "file_creation_num_reused"
+	msgNonBusinessDay                        MessageID = "non_business_day"
+	msgDateOutsideWindow                     MessageID = "date_outside_window"
+	msgInvalidDestinationDataCenterNo        MessageID = "invalid_destination_data_center_no"
+	msgInvalidDirectClearerCommunicationArea MessageID = "invalid_direct_clearer_communication_area"
+	msgInvalidField                          MessageID = "invalid_field"
+	msgInvalidTxnType                        MessageID = "invalid_txn_type"
+	msgInvalidAmount                         MessageID = "invalid_amount"
+	msgInvalidDate                           MessageID = "invalid_date"
+	msgInvalidInstitutionID                  MessageID = "invalid_institution_id"
+	msgInvalidAccountNo                      MessageID = "invalid_account_no"
+	msgInvalidItemTraceNo                    MessageID = "invalid_item_trace_no"
+	msgInvalidStoredTxnType                  MessageID = "invalid_stored_txn_type"
+	msgInvalidOriginatorShortName            MessageID = "invalid_originator_short_name"
+	msgInvalidName                           MessageID = "invalid_name"
+	msgInvalidOriginatorLongName             MessageID = "invalid_originator_long_name"
+	msgInvalidUserID                         MessageID = "invalid_user_id"
+	msgInvalidCrossRefNo                     MessageID = "invalid_cross_ref_no"
+	msgInvalidReturnInstitutionID            MessageID = "invalid_return_institution_id"
+	msgInvalidReturnAccountNo                MessageID = "invalid_return_account_no"
+	msgInvalidOriginalInstitutionID          MessageID = "invalid_original_institution_id"
+	msgInvalidOriginalAccountNo              MessageID = "invalid_original_account_no"
+	msgInvalidOriginalItemTraceNo            MessageID = "invalid_original_item_trace_no"
+	msgInvalidSundryInfo                     MessageID = "invalid_sundry_info"
+	msgInvalidSettlementCode                 MessageID = "invalid_settlement_code"
+	msgInvalidInvalidDataElementID           MessageID = "invalid_invalid_data_element_id"
+	msgTooManyItems                          MessageID = "too_many_items"
+	msgFieldOverflow                         MessageID = "field_overflow"
+	msgInvalidMoney                          MessageID = "invalid_money"
+	msgScanParseError                        MessageID = "scan_parse_error"
+	msgReversalWindowExceeded                MessageID = "reversal_window_exceeded"
+	msgNotReversible                         MessageID = "not_reversible"
+	msgItemTraceNoExhausted                  MessageID = "item_trace_no_exhausted"
+	msgSplitLimitExceeded                    MessageID = "split_limit_exceeded"
+	msgIncompatibleHeaders                   MessageID = "incompatible_headers"
+	msgDuplicateItemTraceNo                  MessageID = "duplicate_item_trace_no"
+	msgInvalidValidationProfile              MessageID = "invalid_validation_profile"
+	msgUnknownValidationProfile              MessageID = "unknown_validation_profile"
+	msgFillerNotBlank                        MessageID = "filler_not_blank"
+	msgFieldMissing                          MessageID = "field_missing"
+	msgLineNotFound                          MessageID = "line_not_found"
+	msgUnsupportedRecordType                 MessageID = "unsupported_record_type"
+
+	// wrapping messages
+	msgTxn       MessageID = "txn"
+	msgFile      MessageID = "file"
+	msgLine      MessageID = "line"
+	msgErrQuoted MessageID = "err_quoted"
+	msgErrNumber MessageID = "err_number"
+	msgErrGot    MessageID = "err_got"
+	// business days and date windows
+	msgNonBusinessDayWeekday     MessageID = "non_business_day_weekday"
+	msgNonBusinessDayHoliday     MessageID = "non_business_day_holiday"
+	msgDateBeforeWindow          MessageID = "date_before_window"
+	msgDateAfterWindow           MessageID = "date_after_window"
+	msgHeaderCreationDateMissing MessageID = "header_creation_date_missing"
+	// money, dates and fields
+	msgInvalidMoneyCause    MessageID = "invalid_money_cause"
+	msgSumOverflow          MessageID = "sum_overflow"
+	msgNotNDigits           MessageID = "not_n_digits"
+	msgDateLength           MessageID = "date_length"
+	msgDateNotNumeric       MessageID = "date_not_numeric"
+	msgConvertCentury       MessageID = "convert_century"
+	msgConvertYear          MessageID = "convert_year"
+	msgConvertDay           MessageID = "convert_day"
+	msgInvalidDayOfYear     MessageID = "invalid_day_of_year"
+	msgYearNotJulian        MessageID = "year_not_julian"
+	msgUnrecognizedRecType  MessageID = "unrecognized_record_type"
+	msgParseField           MessageID = "parse_field"
+	msgFormatField          MessageID = "format_field"
+	msgFieldMissingFromLine MessageID = "field_missing_from_line"
+	// reading files
+	msgReadLine                MessageID = "read_line"
+	msgParseHeader             MessageID = "parse_header"
+	msgParseTxn                MessageID = "parse_txn"
+	msgParseFooter             MessageID = "parse_footer"
+	msgHeaderLength            MessageID = "header_length"
+	msgParseFileHeader         MessageID = "parse_file_header"
+	msgTxnRecordShort          MessageID = "txn_record_short"
+	msgPartialTxn              MessageID = "partial_txn"
+	msgParseTransaction        MessageID = "parse_transaction"
+	msgParseDebit              MessageID = "parse_debit"
+	msgParseCredit             MessageID = "parse_credit"
+	msgParseDebitReturn        MessageID = "parse_debit_return"
+	msgParseCreditReturn       MessageID = "parse_credit_return"
+	msgParseCreditReverse      MessageID = "parse_credit_reverse"
+	msgParseDebitReverse       MessageID = "parse_debit_reverse"
+	msgUnexpectedRecord        MessageID = "unexpected_record"
+	msgFooterMinimum           MessageID = "footer_minimum"
+	msgParseFileFooter         MessageID = "parse_file_footer"
+	msgInvalidHeaderLength     MessageID = "invalid_header_length"
+	msgParseRecordHeader       MessageID = "parse_record_header"
+	msgFooterTooShort          MessageID = "footer_too_short"
+	msgParseFooterRecordHeader MessageID = "parse_footer_record_header"
+	msgRecordHeaderShort       MessageID = "record_header_short"
+	msgParseRecordType         MessageID = "parse_record_type"
+	msgParseRecordCount        MessageID = "parse_record_count"
+	msgParseFileCreationNum    MessageID = "parse_file_creation_num"
+	msgUnmarshalTxn            MessageID = "unmarshal_txn"
+	msgFileHeaderMissing       MessageID = "file_header_missing"
+	// streaming files
+	msgScanHeader            MessageID = "scan_header"
+	msgHeaderEmpty           MessageID = "header_empty"
+	msgHeaderNotFound        MessageID = "header_not_found"
+	msgFirstRecordNotHeader  MessageID = "first_record_not_header"
+	msgLineTooShort          MessageID = "line_too_short"
+	msgFooterNotFound        MessageID = "footer_not_found"
+	msgForwardToTxn          MessageID = "forward_to_txn"
+	msgFirstLineNotHeader    MessageID = "first_line_not_header"
+	msgReadTxns              MessageID = "read_txns"
+	msgReadTxnLine           MessageID = "read_txn_line"
+	msgTxnLineShort          MessageID = "txn_line_short"
+	msgTxnLineLength         MessageID = "txn_line_length"
+	msgUnrecognizedRecTypeAt MessageID = "unrecognized_record_type_at"
+	msgSegmentBounds         MessageID = "segment_bounds"
+	msgInvalidRecordTypeOf   MessageID = "invalid_record_type_of"
+	msgStreamParseError      MessageID = "stream_parse_error"
+	// explain
+	msgLineBlank          MessageID = "line_blank"
+	msgNoSegments         MessageID = "no_segments"
+	msgSegmentNotFound    MessageID = "segment_not_found"
+	msgUnsupportedRecType MessageID = "unsupported_record_type_of"
+	// profiles
+	msgReadProfiles        MessageID = "read_profiles"
+	msgDecodeProfiles      MessageID = "decode_profiles"
+	msgProfileNoName       MessageID = "profile_no_name"
+	msgProfileUnknownField MessageID = "profile_unknown_field"
+	msgProfileFieldError   MessageID = "profile_field_error"
+	msgProfileTooManyItems MessageID = "profile_too_many_items"
+	// reversals, split and merge
+	msgDated               MessageID = "dated"
+	msgCreationNumUsed     MessageID = "creation_num_used"
+	msgNegativeSplitLimits MessageID = "negative_split_limits"
+	msgSplitCreationNum    MessageID = "split_creation_num"
+	msgSplitAmount         MessageID = "split_amount"
+	msgNoFilesToMerge      MessageID = "no_files_to_merge"
+)
+
+// messages are the formats of every MessageID by locale, errors among the arguments are wrapped with %w
+var messages = map[MessageID]map[Locale]string{
+	msgInvalidRecordLength:                   {English: "transaction record is not 240 characters", French: "l'enregistrement de transaction ne fait pas 240 caractères"},
+	msgMissingHeader:                         {English: "missing file header", French: "en-tête de fichier manquant"},
+	msgMissingOriginatorId:                   {English: "missing originator ID", French: "identifiant de l'émetteur manquant"},
+	msgMissingCreationDate:                   {English: "missing creation date", French: "date de création manquante"},
+	msgInvalidRecordType:                     {English: "invalid record type", French: "type d'enregistrement invalide"},
+	msgInvalidCurrencyCode:                   {English: "invalid currency code", French: "code de devise invalide"},
+	msgInvalidOriginatorIdLength:             {English: "invalid originator ID length", French: "longueur de l'identifiant de l'émetteur invalide"},
+	msgInvalidOriginatorId:                   {English: "invalid originator ID not alpha numeric", French: "identifiant de l'émetteur invalide, il n'est pas alphanumérique"},
+	msgInvalidFileCreationNum:                {English: "invalid file creation number", French: "numéro de création de fichier invalide"},
+	msgFileCreationNumReused:                 {English: "file creation number reused within reuse window", French: "numéro de création de fichier réutilisé pendant la période de réutilisation"},
+	msgNonBusinessDay:                        {English: "date is not a business day", French: "la date n'est pas un jour ouvrable"},
+	msgDateOutsideWindow:                     {English: "date is outside of the allowed window", French: "la date est en dehors de la période permise"},
+	msgInvalidDestinationDataCenterNo:        {English: "invalid destination data center", French: "centre de données de destination invalide"},
+	msgInvalidDirectClearerCommunicationArea: {English: "invalid direct clearer communication area", French: "zone de communication de l'adhérent direct invalide"},
+	msgInvalidField:                          {English: "invalid field", French: "champ invalide"},
+	msgInvalidTxnType:                        {English: "invalid transaction type", French: "type de transaction invalide"},
+	msgInvalidAmount:                         {English: "invalid amount", French: "montant invalide"},
+	msgInvalidDate:                           {English: "invalid date", French: "date invalide"},
+	msgInvalidInstitutionID:                  {English: "invalid institution ID", French: "identifiant d'institution invalide"},
+	msgInvalidAccountNo:                      {English: "invalid account number", French: "numéro de compte invalide"},
+	msgInvalidItemTraceNo:                    {English: "invalid item trace number", French: "numéro de référence de l'effet invalide"},
+	msgInvalidStoredTxnType:                  {English: "invalid stored transaction type", French: "type de transaction enregistré invalide"},
+	msgInvalidOriginatorShortName:            {English: "invalid originator short name", French: "nom abrégé de l'émetteur invalide"},
+	msgInvalidName:                           {English: "invalid payor/payee name", French: "nom du payeur/bénéficiaire invalide"},
+	msgInvalidOriginatorLongName:             {English: "invalid originator long name", French: "nom complet de l'émetteur invalide"},
+	msgInvalidUserID:                         {English: "invalid originating direct clearer's user ID", French: "identifiant d'utilisateur de l'adhérent direct émetteur invalide"},
+	msgInvalidCrossRefNo:                     {English: "invalid cross reference number", French: "numéro de renvoi invalide"},
+	msgInvalidReturnInstitutionID:            {English: "invalid institution ID for returns", French: "identifiant d'institution pour les retours invalide"},
+	msgInvalidReturnAccountNo:                {English: "invalid account number for returns", French: "numéro de compte pour les retours invalide"},
+	msgInvalidOriginalInstitutionID:          {English: "invalid original institution ID", French: "identifiant d'institution d'origine invalide"},
+	msgInvalidOriginalAccountNo:              {English: "invalid original account number", French: "numéro de compte d'origine invalide"},
+	msgInvalidOriginalItemTraceNo:            {English: "invalid original item trace number", French: "numéro de référence de l'effet d'origine invalide"},
+	msgInvalidSundryInfo:                     {English: "invalid sundry information", French: "renseignements divers invalides"},
+	msgInvalidSettlementCode:                 {English: "invalid settlement code", French: "code de règlement invalide"},
+	msgInvalidInvalidDataElementID:           {English: "invalid invalid data element ID", French: "identifiant d'élément de données invalide invalide"},
+	msgTooManyItems:                          {English: "too many transactions in file", French: "trop de transactions dans le fichier"},
+	msgFieldOverflow:                         {English: "value does not fit in field", French: "la valeur ne tient pas dans le champ"},
+	msgInvalidMoney:                          {English: "invalid money amount", French: "montant d'argent invalide"},
+	msgScanParseError:                        {English: "failed to parse txn", French: "échec de l'analyse de la transaction"},
+	msgReversalWindowExceeded:                {English: "original item is outside of the reversal window", French: "l'effet d'origine est en dehors de la période d'annulation"},
+	msgNotReversible:                         {English: "only C and D records can be reversed", French: "seuls les enregistrements C et D peuvent être annulés"},
+	msgItemTraceNoExhausted:                  {English: "item trace number sequence exhausted", French: "séquence de numéros de référence des effets épuisée"},
+	msgSplitLimitExceeded:                    {English: "transaction exceeds split limits", French: "la transaction dépasse les limites de découpage"},
+	msgIncompatibleHeaders:                   {English: "incompatible file headers", French: "en-têtes de fichier incompatibles"},
+	msgDuplicateItemTraceNo:                  {English: "duplicate item trace number", French: "numéro de référence de l'effet en double"},
+	msgInvalidValidationProfile:              {English: "invalid validation profile", French: "profil de validation invalide"},
+	msgUnknownValidationProfile:              {English: "unknown validation profile", French: "profil de validation inconnu"},
+	msgFillerNotBlank:                        {English: "filler is not blank", French: "la zone de remplissage n'est pas vide"},
+	msgFieldMissing:                          {English: "field is missing from the line", French: "le champ est absent de la ligne"},
+	msgLineNotFound:                          {English: "line not found", French: "ligne introuvable"},
+	msgUnsupportedRecordType:                 {English: "unsupported record type", French: "type d'enregistrement non pris en charge"},
+	msgTxn:                                   {English: "txn %d: %w", French: "transaction %d : %w"},
+	msgFile:                                  {English: "file %d: %w", French: "fichier %d : %w"},
+	msgLine:                                  {English: "line %d: %w", French: "ligne %d : %w"},
+	msgErrQuoted:                             {English: "%w: %q", French: "%w : %q"},
+	msgErrNumber:                             {English: "%w: %d", French: "%w : %d"},
+	msgErrGot:                                {English: "%w: got %s", French: "%w : reçu %s"},
+	msgNonBusinessDayWeekday:                 {English: "%w: %s is a %s", French: "%w : %s est un %s"},
+	msgNonBusinessDayHoliday:                 {English: "%w: %s is %s", French: "%w : %s est %s"},
+	msgDateBeforeWindow:                      {English: "%w: %s is %d days before the file creation date, %s may be at most %d days before", French: "%w : %s est %d jours avant la date de création du fichier, %s peut être au plus %d jours avant"},
+	msgDateAfterWindow:                       {English: "%w: %s is %d days after the file creation date, %s may be at most %d days after", French: "%w : %s est %d jours après la date de création du fichier, %s peut être au plus %d jours après"},
+	msgHeaderCreationDateMissing:             {English: "file header creation date is missing", French: "la date de création de l'en-tête de fichier est manquante"},
+	msgInvalidMoneyCause:                     {English: "%w: %q: %w", French: "%w : %q : %w"},
+	msgSumOverflow:                           {English: "%w: %d + %d overflows", French: "%w : %d + %d dépasse la capacité"},
+	msgNotNDigits:                            {English: "%w: %d is not a %d digit number", French: "%w : %d n'est pas un nombre de %d chiffres"},
+	msgDateLength:                            {English: "date string is not valid length", French: "la date n'a pas une longueur valide"},
+	msgDateNotNumeric:                        {English: "date string %q is not numeric", French: "la date %q n'est pas numérique"},
+	msgConvertCentury:                        {English: "failed to convert century: %w", French: "échec de la conversion du siècle : %w"},
+	msgConvertYear:                           {English: "failed to convert year: %w", French: "échec de la conversion de l'année : %w"},
+	msgConvertDay:                            {English: "failed to convert days since jan 1st: %w", French: "échec de la conversion des jours depuis le 1er janvier : %w"},
+	msgInvalidDayOfYear:                      {English: "invalid day of year %03d for %d", French: "jour de l'année %03d invalide pour %d"},
+	msgYearNotJulian:                         {English: "year %d cannot be represented as a julian date", French: "l'année %d ne peut pas être représentée en date julienne"},
+	msgUnrecognizedRecType:                   {English: "unrecognized record type %s", French: "type d'enregistrement %s non reconnu"},
+	msgParseField:                            {English: "failed to parse %s", French: "échec de l'analyse du champ « %s »"},
+	msgFormatField:                           {English: "failed to format %s: %w", French: "échec du formatage du champ « %s » : %w"},
+	msgFieldMissingFromLine:                  {English: "%s is missing", French: "le champ « %s » est absent"},
+	msgReadLine:                              {English: "failed to read line: %w", French: "échec de la lecture de la ligne : %w"},
+	msgParseHeader:                           {English: "failed to parse header: %w", French: "échec de l'analyse de l'en-tête : %w"},
+	msgParseTxn:                              {English: "failed to parse txn: %w", French: "échec de l'analyse de la transaction : %w"},
+	msgParseFooter:                           {English: "failed to parse footer: %w", French: "échec de l'analyse de l'enregistrement de fin : %w"},
+	msgHeaderLength:                          {English: "record type A is not required length", French: "l'enregistrement de type A n'a pas la longueur requise"},
+	msgParseFileHeader:                       {English: "failed to parse file header: %w", French: "échec de l'analyse de l'en-tête de fichier : %w"},
+	msgTxnRecordShort:                        {English: "txn record shorter than common header length %d: got %d", French: "l'enregistrement de transaction est plus court que l'en-tête commun de %d : %d reçus"},
+	msgPartialTxn:                            {English: "record length is not valid multiple of %d, partial txn: %d", French: "la longueur de l'enregistrement n'est pas un multiple de %d, transaction partielle : %d"},
+	msgParseTransaction:                      {English: "failed to parse transaction: %w", French: "échec de l'analyse de la transaction : %w"},
+	msgParseDebit:                            {English: "failed to parse debit transaction: %w", French: "échec de l'analyse de la transaction de débit : %w"},
+	msgParseCredit:                           {English: "failed to parse credit transaction: %w", French: "échec de l'analyse de la transaction de crédit : %w"},
+	msgParseDebitReturn:                      {English: "failed to parse debit return transaction: %w", French: "échec de l'analyse du retour de débit : %w"},
+	msgParseCreditReturn:                     {English: "failed to parse credit return transaction: %w", French: "échec de l'analyse du retour de crédit : %w"},
+	msgParseCreditReverse:                    {English: "failed to parse credit reverse transaction: %w", French: "échec de l'analyse de l'annulation de crédit : %w"},
+	msgParseDebitReverse:                     {English: "failed to parse debit reverse transaction: %w", French: "échec de l'analyse de l'annulation de débit : %w"},
+	msgUnexpectedRecord:                      {English: "unexpected %s record", French: "enregistrement %s inattendu"},
+	msgFooterMinimum:                         {English: "z record does not contain minimum amount of data", French: "l'enregistrement Z ne contient pas le minimum de données"},
+	msgParseFileFooter:                       {English: "failed to parse file footer: %w", French: "échec de l'analyse de l'enregistrement de fin de fichier : %w"},
+	msgInvalidHeaderLength:                   {English: "invalid header record length", French: "longueur de l'enregistrement d'en-tête invalide"},
+	msgParseRecordHeader:                     {English: "failed to parse record header: %w", French: "échec de l'analyse de l'en-tête d'enregistrement : %w"},
+	msgFooterTooShort:                        {English: "footer is too short", French: "l'enregistrement de fin est trop court"},
+	msgParseFooterRecordHeader:               {English: "failed to parse record header for footer: %w", French: "échec de l'analyse de l'en-tête d'enregistrement de fin : %w"},
+	msgRecordHeaderShort:                     {English: "record header line too short", French: "la ligne d'en-tête d'enregistrement est trop courte"},
+	msgParseRecordType:                       {English: "faield to parse RecordHeader: %w", French: "échec de l'analyse du type d'enregistrement : %w"},
+	msgParseRecordCount:                      {English: "failed to parse RecordCount: %w", French: "échec de l'analyse du nombre d'enregistrements : %w"},
+	msgParseFileCreationNum:                  {English: "failed to parse FileCreationNum: %w", French: "échec de l'analyse du numéro de création de fichier : %w"},
+	msgUnmarshalTxn:                          {English: "failed to unmarshall transaction", French: "échec du décodage de la transaction"},
+	msgFileHeaderMissing:                     {English: "file header is missing", French: "l'en-tête de fichier est manquant"},
+	msgScanHeader:                            {English: "failed to scan for file header: %w", French: "échec de la recherche de l'en-tête de fichier : %w"},
+	msgHeaderEmpty:                           {English: "file header is empty", French: "l'en-tête de fichier est vide"},
+	msgHeaderNotFound:                        {English: "file header not found: %w", French: "en-tête de fichier introuvable : %w"},
+	msgFirstRecordNotHeader:                  {English: "first record in file is not a header record", French: "le premier enregistrement du fichier n'est pas un en-tête"},
+	msgLineTooShort:                          {English: "line too short to determine record type", French: "ligne trop courte pour déterminer le type d'enregistrement"},
+	msgFooterNotFound:                        {English: "failed to find footer record", French: "enregistrement de fin introuvable"},
+	msgForwardToTxn:                          {English: "failed forward reader to txn record: %w", French: "échec de l'avancement du lecteur jusqu'à l'enregistrement de transaction : %w"},
+	msgFirstLineNotHeader:                    {English: "first line in file is not a header record", French: "la première ligne du fichier n'est pas un en-tête"},
+	msgReadTxns:                              {English: "failed to read transactions: %w", French: "échec de la lecture des transactions : %w"},
+	msgReadTxnLine:                           {English: "failed to read transaction line: %w", French: "échec de la lecture de la ligne de transaction : %w"},
+	msgTxnLineShort:                          {English: "txn record at line %d shorter than common header length %d", French: "l'enregistrement de transaction à la ligne %d est plus court que l'en-tête commun de %d"},
+	msgTxnLineLength:                         {English: "txn record at line %d is not of correct length", French: "l'enregistrement de transaction à la ligne %d n'a pas la bonne longueur"},
+	msgUnrecognizedRecTypeAt:                 {English: "unrecognized record type at line %d: %w", French: "type d'enregistrement non reconnu à la ligne %d : %w"},
+	msgSegmentBounds:                         {English: "txn segment bounds out of range at line %d", French: "limites du segment de transaction hors plage à la ligne %d"},
+	msgInvalidRecordTypeOf:                   {English: "invalid record type: %v", French: "type d'enregistrement invalide : %v"},
+	msgStreamParseError:                      {English: "parse error for record %s number %d line %d", French: "erreur d'analyse de l'enregistrement %s numéro %d ligne %d"},
+	msgLineBlank:                             {English: "%w: line is blank", French: "%w : la ligne est vide"},
+	msgNoSegments:                            {English: "%s records have no segments", French: "les enregistrements %s n'ont pas de segments"},
+	msgSegmentNotFound:                       {English: "segment %d not found, the line has %d segments", French: "segment %d introuvable, la ligne a %d segments"},
+	msgUnsupportedRecType:                    {English: "%w %q", French: "%w %q"},
+	msgReadProfiles:                          {English: "failed to read validation profiles: %w", French: "échec de la lecture des profils de validation : %w"},
+	msgDecodeProfiles:                        {English: "failed to decode validation profiles: %w", French: "échec du décodage des profils de validation : %w"},
+	msgProfileNoName:                         {English: "%w: profile has no name", French: "%w : le profil n'a pas de nom"},
+	msgProfileUnknownField:                   {English: "%w: profile %q: unknown field %q", French: "%w : profil %q : champ %q inconnu"},
+	msgProfileFieldError:                     {English: "%w: profile %q: field %q: %w", French: "%w : profil %q : champ %q : %w"},
+	msgProfileTooManyItems:                   {English: "%w: %d transactions, profile %s allows %d", French: "%w : %d transactions, le profil %s en permet %d"},
+	msgDated:                                 {English: "%w: dated %s", French: "%w : daté du %s"},
+	msgCreationNumUsed:                       {English: "%w: %04d was used on %s", French: "%w : %04d a été utilisé le %s"},
+	msgNegativeSplitLimits:                   {English: "split limits cannot be negative", French: "les limites de découpage ne peuvent pas être négatives"},
+	msgSplitCreationNum:                      {English: "failed to get file creation number for file %d: %w", French: "échec de l'obtention du numéro de création pour le fichier %d : %w"},
+	msgSplitAmount:                           {English: "%w: amount %s is above the maximum value of a file %s", French: "%w : le montant %s dépasse la valeur maximale d'un fichier %s"},
+	msgNoFilesToMerge:                        {English: "no files to merge", French: "aucun fichier à fusionner"},
+}
+
+// fieldDescriptions are the descriptions of the layout fields by json name in the locales other than English,
+// which uses the Description of the FieldLayout
+var fieldDescriptions = map[Locale]map[string]string{
+	French: {
+		"type":                       "identifiant du type d'enregistrement logique",
+		"record_count":               "nombre d'enregistrements logiques",
+		"originator_id":              "identifiant de l'émetteur",
+		"file_creation_number":       "numéro de création de fichier",
+		"creation_date":              "date de création",
+		"destination_data_center":    "centre de données de destination",
+		"communication_area":         "zone de communication de l'adhérent direct",
+		"currency_code":              "code de devise",
+		"txn_type":                   "type de transaction",
+		"amount":                     "montant",
+		"date_funds_available":       "date de disponibilité des fonds",
+		"due_date":                   "date d'échéance",
+		"institution_id":             "identifiant d'institution",
+		"payee_account_no":           "numéro de compte du bénéficiaire",
+		"payor_account_no":           "numéro de compte du payeur",
+		"item_trace_no":              "numéro de référence de l'effet",
+		"stored_txn_type":            "type de transaction enregistré",
+		"short_name":                 "nom abrégé de l'émetteur",
+		"payee_name":                 "nom du bénéficiaire",
+		"payor_name":                 "nom du payeur",
+		"long_name":                  "nom complet de l'émetteur",
+		"user_id":                    "identifiant d'utilisateur",
+		"cross_ref_no":               "numéro de renvoi",
+		"return_institution_id":      "identifiant d'institution pour les retours",
+		"return_account_no":          "numéro de compte pour les retours",
+		"original_institution_id":    "identifiant d'institution d'origine",
+		"original_account_no":        "numéro de compte d'origine",
+		"sundry_info":                "renseignements divers",
+		"original_item_trace_no":     "numéro de référence de l'effet d'origine",
+		"settlement_code":            "code de règlement",
+		"invalid_data_element_id":    "identifiant d'élément de données invalide",
+		"total_value_debit":          "valeur totale des débits",
+		"total_count_debit":          "nombre total de débits",
+		"total_value_credit":         "valeur totale des crédits",
+		"total_count_credit":         "nombre total de crédits",
+		"total_value_reverse_debit":  "valeur totale des enregistrements E",
+		"total_count_reverse_debit":  "nombre total d'enregistrements E",
+		"total_value_reverse_credit": "valeur totale des enregistrements F",
+		"total_count_reverse_credit": "nombre total d'enregistrements F",
+	},
+}
+
+// fieldDescription is a message argument naming a layout field, it is rendered with the description of the field in the locale
+type fieldDescription FieldLayout
+
+func (f fieldDescription) String() string {
+	return f.Description
+}
+
+func init() {
+	enLocale := en.New()
+	uni := ut.New(enLocale, enLocale, fr.New())
+	registerDefaults := map[Locale]func(*validator.Validate, ut.Translator) error{
+		English: enTranslations.RegisterDefaultTranslations,
+		French:  frTranslations.RegisterDefaultTranslations,
+	}
+	for locale, cat := range catalogs {
+		trans, _ := uni.GetTranslator(string(locale))
+		c(registerDefaults[locale](eftValidator, trans))
+		for tag, text := range customTagTranslations[locale] {
+			c(registerTagTranslation(trans, tag, text))
+		}
+		cat.translator = trans
+	}
+}
+
+func registerTagTranslation(trans ut.Translator, tag, text string) error {
+	return eftValidator.RegisterTranslation(tag, trans, func(t ut.Translator) error {
+		return t.Add(tag, text, true)
+	}, func(t ut.Translator, fe validator.FieldError) string {
+		msg, err := t.T(fe.Tag(), fe.Field())
+		if err != nil {
+			return fe.Error()
+		}
+		return msg
+	})
+}
+
+// Translate renders err in the given locale. Validation errors, parse errors and the errors created by this package are rendered
+// from the message catalog by their MessageID, the text of any other error is left as is. Regional locales such as fr-CA use their
+// language and unknown locales fall back to English.
+func Translate(err error, locale Locale) string {
+	if err == nil {
+		return ""
+	}
+	lang, _, _ := strings.Cut(strings.ToLower(string(locale)), "_")
+	lang, _, _ = strings.Cut(lang, "-")
+	cat, ok := catalogs[Locale(lang)]
+	if !ok {
+		cat = catalogs[English]
+	}
+	return cat.translate(err)
+}
+
+func (cat *catalog) translate(err error) string {
+	switch e := err.(type) {
+	case *messageError:
+		return cat.render(e.id, e.args)
+	case ValidationErrors:
+		if len(e) == 1 {
+			return cat.translate(e[0])
+		}
+		msgs := make([]string, 0, len(e))
+		for _, v := range e {
+			msgs = append(msgs, cat.translate(v))
+		}
+		return fmt.Sprintf(cat.validationErrors, len(e)) + cat.separator + strings.Join(msgs, "; ")
+	case *ValidationError:
+		return cat.validationError(e, cat.translate, true)
+	case *ParseError:
+		msg := e.Msg
+		if e.ID != "" {
+			msg = cat.render(e.ID, e.args)
+		}
+		if e.Err == nil {
+			return msg
+		}
+		if msg == "" {
+			return cat.translate(e.Err)
+		}
+		return msg + cat.separator + cat.translate(e.Err)
+	case MergeConflicts:
+		msgs := make([]string, 0, len(e))
+		for _, c := range e {
+			msgs = append(msgs, cat.translate(c))
+		}
+		return fmt.Sprintf(cat.mergeConflicts, len(e)) + cat.separator + strings.Join(msgs, "; ")
+	case MergeConflict:
+		var msg string
+		if e.TxnIndex != nil && e.OtherTxnIndex != nil {
+			msg = fmt.Sprintf(cat.traceNoConflict, e.FileIndex, *e.TxnIndex, e.Field, e.Value, e.OtherFileIndex, *e.OtherTxnIndex)
+		} else {
+			msg = fmt.Sprintf(cat.headerConflict, e.FileIndex, e.Field, e.Value, e.OtherValue, e.OtherFileIndex)
+		}
+		if e.Err == nil {
+			return msg
+		}
+		return msg + cat.separator + cat.translate(e.Err)
+	case *multierror.Error:
+		header := fmt.Sprintf(cat.multipleErrors, len(e.Errors))
+		if len(e.Errors) == 1 {
+			header = cat.singleErrorOccured
+		}
+		lines := make([]string, 0, len(e.Errors))
+		for _, inner := range e.Errors {
+			lines = append(lines, "\t* "+cat.translate(inner))
+		}
+		return header + strings.TrimRight(cat.separator, " ") + "\n" + strings.Join(lines, "\n")
+	}
+
+	// errors of other packages keep their own text, an error they wrap at the end of that text is still translated
+	msg := err.Error()
+	inner := errors.Unwrap(err)
+	if inner == nil {
+		return msg
+	}
+	if before, ok := strings.CutSuffix(msg, inner.Error()); ok {
+		return before + cat.translate(inner)
+	}
+	return msg
+}
+
+// validationError renders v with the formats of cat, text renders the error v wraps. The failed rule is described by the
+// validator translation of the locale when translateRule is set and by its tag otherwise.
+func (cat *catalog) validationError(v *ValidationError, text func(error) string, translateRule bool) string {
+	if v.Field == "" {
+		if v.Err == nil {
+			return ""
+		}
+		return text(v.Err)
+	}
+	var msg string
+	if v.TxnIndex != nil {
+		msg = fmt.Sprintf(cat.txnFieldFormat, *v.TxnIndex, v.RecordType, v.FieldNumber, v.Field)
+	} else {
+		msg = fmt.Sprintf(cat.fieldFormat, v.RecordType, v.FieldNumber, v.Field)
+	}
+	if v.Err != nil {
+		msg += cat.separator + text(v.Err)
+	}
+	if translateRule && v.fieldErr != nil {
+		return msg + cat.separator + v.fieldErr.Translate(cat.translator)
+	}
+	if v.Rule != "" {
+		rule := v.Rule
+		if v.Param != "" {
+			rule += "=" + v.Param
+		}
+		msg += cat.separator + fmt.Sprintf(cat.ruleFormat, rule)
+	}
+	return msg
+}
+
+// format fills the message id with args as they are, this is how the English text of an error is built
+func (cat *catalog) format(id MessageID, args []any) string {
+	return fmt.Errorf(cat.messageFormat(id), args...).Error()
+}
+
+// render fills the message id with args rendered in the locale of cat
+func (cat *catalog) render(id MessageID, args []any) string {
+	localized := make([]any, 0, len(args))
+	for _, arg := range args {
+		switch a := arg.(type) {
+		case error:
+			localized = append(localized, renderedError(cat.translate(a)))
+		case fieldDescription:
+			if desc, ok := fieldDescriptions[cat.locale][a.Name]; ok {
+				localized = append(localized, desc)
+			} else {
+				localized = append(localized, a.Description)
+			}
+		default:
+			localized = append(localized, arg)
+		}
+	}
+	return cat.format(id, localized)
+}
+
+// messageFormat returns the format of id in the locale of cat, falling back to English
+func (cat *catalog) messageFormat(id MessageID) string {
+	if format, ok := messages[id][cat.locale]; ok {
+		return format
+	}
+	if format, ok := messages[id][English]; ok {
+		return format
+	}
+	return string(id)
+}
+
+// renderedError is an error argument already rendered in the locale of a message
+type renderedError string
+
+func (e renderedError) Error() string {
+	return string(e)
+}
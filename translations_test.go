@@ -0,0 +1,124 @@
+package cadeft
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslate(t *testing.T) {
+	date := time.Date(2023, time.October, 2, 0, 0, 0, 0, time.UTC)
+	credit := NewCredit("450", 99999999999, &date, "123456789", "123456789012", "", "SHORT-NAME", "RECEIVER NAME", "LONG-NAME", "123456789", "210987654321")
+	header := NewFileHeader("0000000001", 1, &date, 12345, "EUR")
+
+	type testCase struct {
+		err      error
+		locale   Locale
+		expected string
+	}
+	cases := map[string]testCase{
+		"nil error": {
+			err:      nil,
+			locale:   French,
+			expected: "",
+		},
+		"built in rule in english": {
+			err:      credit.Validate(),
+			locale:   English,
+			expected: "record C field 05 Amount: invalid amount: Amount must be 9,999,999,999 or less",
+		},
+		"built in rule in french": {
+			err:      credit.Validate(),
+			locale:   French,
+			expected: "enregistrement C champ 05 Amount : montant invalide : Amount doit être égal à 9\u202f999\u202f999\u202f999 ou moins",
+		},
+		"custom rule in french": {
+			err:      header.Validate(),
+			locale:   French,
+			expected: "enregistrement A champ 08 CurrencyCode : code de devise invalide : CurrencyCode doit être CAD ou USD",
+		},
+		"regional locale": {
+			err:      header.Validate(),
+			locale:   "fr-CA",
+			expected: "enregistrement A champ 08 CurrencyCode : code de devise invalide : CurrencyCode doit être CAD ou USD",
+		},
+		"unknown locale falls back to english": {
+			err:      header.Validate(),
+			locale:   "de",
+			expected: "record A field 08 CurrencyCode: invalid currency code: CurrencyCode must be CAD or USD",
+		},
+		"file validation in french": {
+			err:      NewFile(nil, Transactions{&credit}).Validate(),
+			locale:   French,
+			expected: "2 erreurs de validation sont survenues : enregistrement A champ 00 Header : en-tête de fichier manquant : échec de la validation « required »; transaction 0 enregistrement C champ 05 Amount : montant invalide : Amount doit être égal à 9\u202f999\u202f999\u202f999 ou moins",
+		},
+		"wrapped parse error": {
+			err:      newError(msgParseTxn, newError(msgParseCredit, newParseError(errors.New("boom"), msgParseField, fieldDescription(recordLayouts[CreditRecord].Fields[1])))),
+			locale:   French,
+			expected: "échec de l'analyse de la transaction : échec de l'analyse de la transaction de crédit : échec de l'analyse du champ « montant » : boom",
+		},
+		"parse error with arguments": {
+			err:      newError(msgTxnLineLength, 4),
+			locale:   French,
+			expected: "l'enregistrement de transaction à la ligne 4 n'a pas la bonne longueur",
+		},
+		"sentinel wrapped in the middle": {
+			err:      multierror.Append(nil, newError(msgTxn, 3, newError(msgNonBusinessDayWeekday, ErrNonBusinessDay, "2023-04-08", time.Saturday))),
+			locale:   French,
+			expected: "1 erreur est survenue :\n\t* transaction 3 : la date n'est pas un jour ouvrable : 2023-04-08 est un Saturday",
+		},
+		"other packages keep their text": {
+			err:      fmt.Errorf("failed to open file: %w", ErrMissingHeader),
+			locale:   French,
+			expected: "failed to open file: en-tête de fichier manquant",
+		},
+		"unknown message is kept": {
+			err:      errors.New("txn record is fine"),
+			locale:   French,
+			expected: "txn record is fine",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, Translate(tc.err, tc.locale))
+		})
+	}
+}
+
+func TestReadFileErrorInFrench(t *testing.T) {
+	r := require.New(t)
+	_, err := NewReader(strings.NewReader("A123")).ReadFile()
+	r.Error(err)
+	r.Equal("échec de l'analyse de l'en-tête : l'enregistrement de type A n'a pas la longueur requise", Translate(err, French))
+}
+
+func TestMessages(t *testing.T) {
+	verbs := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z]`)
+	for id, formats := range messages {
+		english, ok := formats[English]
+		require.True(t, ok, "%s has no English message", id)
+		for locale, format := range formats {
+			require.Equal(t, verbs.FindAllString(english, -1), verbs.FindAllString(format, -1), "%s in %s", id, locale)
+		}
+		require.Contains(t, formats, French, "%s has no French message", id)
+	}
+}
+
+func TestErrorRenderedFromCatalog(t *testing.T) {
+	r := require.New(t)
+	err := newError(msgTxn, 2, newError(msgErrQuoted, ErrInvalidMoney, "1.2.3"))
+	r.Equal("txn 2: invalid money amount: \"1.2.3\"", err.Error())
+	r.ErrorIs(err, ErrInvalidMoney)
+	r.Equal("transaction 2 : montant d'argent invalide : \"1.2.3\"", Translate(err, French))
+
+	_, err = parseDate("026000")
+	r.Equal("invalid day of year 000 for 2026", err.Error())
+	r.Equal("jour de l'année 000 invalide pour 2026", Translate(err, French))
+}
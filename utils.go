@@ -1,13 +1,15 @@
 package cadeft
 
 import (
-	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 )
 
+// julian dates are written as CYYDDD with the century digit C counted from 2000
+const julianBaseYear = 2000
+
 func Ptr[T any](v T) *T {
 	return &v
 }
@@ -32,24 +34,41 @@ func convertRecordType(recType string) (RecordType, error) {
 	case "Z":
 		return FooterRecord, nil
 	default:
-		return "", fmt.Errorf("unrecognized record type %s", recType)
+		return "", newError(msgUnrecognizedRecType, recType)
 	}
 }
 
+// parseDate parses a julian date of the form CYYDDD where C is the century counted from 2000, YY the year within the century and DDD the day of the year.
+// For example 023138 is May 18th 2023 and 100001 is January 1st 2100. The date is returned at midnight UTC.
 func parseDate(date string) (time.Time, error) {
+	return parseDateInLocation(date, time.UTC)
+}
+
+// parseDateInLocation parses a julian date like parseDate but returns midnight in loc
+func parseDateInLocation(date string, loc *time.Location) (time.Time, error) {
 	if len(date) != 6 {
-		return time.Time{}, errors.New("date string is not valid length")
+		return time.Time{}, newError(msgDateLength)
+	}
+	if !numericRegex.MatchString(date) {
+		return time.Time{}, newError(msgDateNotNumeric, date)
 	}
-	year, err := strconv.Atoi("20" + date[1:3])
+	century, err := strconv.Atoi(date[:1])
 	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to convert year: %w", err)
+		return time.Time{}, newError(msgConvertCentury, err)
 	}
-	daysSinceJan1, err := strconv.Atoi(date[3:])
+	yearOfCentury, err := strconv.Atoi(date[1:3])
 	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to convert days since jan 1st: %w", err)
+		return time.Time{}, newError(msgConvertYear, err)
 	}
-	return time.Date(year, time.January, 0, 0, 0, 0, 0, time.UTC).AddDate(0, 0, daysSinceJan1), nil
-
+	dayOfYear, err := strconv.Atoi(date[3:])
+	if err != nil {
+		return time.Time{}, newError(msgConvertDay, err)
+	}
+	year := julianBaseYear + century*100 + yearOfCentury
+	if dayOfYear < 1 || dayOfYear > daysInYear(year) {
+		return time.Time{}, newError(msgInvalidDayOfYear, dayOfYear, year)
+	}
+	return time.Date(year, time.January, dayOfYear, 0, 0, 0, 0, loc), nil
 }
 
 // convert an interger to a numeric string with 0's padded to the left. eg 12 would be "00012" if required length is 5
@@ -57,12 +76,34 @@ func convertNumToZeroPaddedString(in int64, requiredLength int) string {
 	return fmt.Sprintf("%0*d", requiredLength, in)
 }
 
-// default length of date string in eft format is 6 0yyddd
-func convertTimestampToEftDate(in time.Time) string {
-	year := in.Year() % 100
-	day := in.YearDay()
+// formatNumField zero pads in to width digits, unlike convertNumToZeroPaddedString it returns ErrFieldOverflow instead of writing
+// a value that is negative or longer than the field and would shift every following field of the record
+func formatNumField(in int64, width int) (string, error) {
+	s := convertNumToZeroPaddedString(in, width)
+	if in < 0 || len(s) > width {
+		return "", newError(msgNotNDigits, ErrFieldOverflow, in, width)
+	}
+	return s, nil
+}
+
+// convertTimestampToEftDate formats in as a CYYDDD julian date, see parseDate. The date is taken in the location of in.
+// Only years 2000 through 2999 can be represented.
+func convertTimestampToEftDate(in time.Time) (string, error) {
+	year := in.Year()
+	if year < julianBaseYear || year >= julianBaseYear+1000 {
+		return "", newError(msgYearNotJulian, year)
+	}
+	century := (year - julianBaseYear) / 100
+	return fmt.Sprintf("%d%02d%03d", century, year%100, in.YearDay()), nil
+}
+
+func daysInYear(year int) int {
+	return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
 
-	return fmt.Sprintf("%03d%03d", year, day)
+// inLocation returns midnight in loc of the calendar date of t
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
 }
 
 func isFillerString(s string) bool {
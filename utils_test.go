@@ -0,0 +1,75 @@
+package cadeft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDate(t *testing.T) {
+	type testCase struct {
+		in        string
+		expected  time.Time
+		expectErr bool
+	}
+	cases := map[string]testCase{
+		"2023":                     {in: "023138", expected: time.Date(2023, time.May, 18, 0, 0, 0, 0, time.UTC)},
+		"leap day":                 {in: "024060", expected: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		"last day of leap year":    {in: "024366", expected: time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)},
+		"next century":             {in: "100001", expected: time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		"day 000":                  {in: "023000", expectErr: true},
+		"day 366 in non leap year": {in: "023366", expectErr: true},
+		"day 367":                  {in: "024367", expectErr: true},
+		"not numeric":              {in: "0a3001", expectErr: true},
+		"negative":                 {in: "-23001", expectErr: true},
+		"too short":                {in: "02300", expectErr: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			d, err := parseDate(tc.in)
+			if tc.expectErr {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tc.expected, d)
+		})
+	}
+}
+
+func TestConvertTimestampToEftDate(t *testing.T) {
+	r := require.New(t)
+	toronto, err := time.LoadLocation("America/Toronto")
+	r.NoError(err)
+
+	for _, d := range []time.Time{
+		time.Date(2023, time.May, 18, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2999, time.December, 31, 0, 0, 0, 0, time.UTC),
+	} {
+		s, err := convertTimestampToEftDate(d)
+		r.NoError(err)
+		parsed, err := parseDate(s)
+		r.NoError(err)
+		r.Equal(d, parsed)
+	}
+
+	s, err := convertTimestampToEftDate(time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC))
+	r.NoError(err)
+	r.Equal("100001", s)
+
+	// the date is taken in the location of the timestamp
+	s, err = convertTimestampToEftDate(time.Date(2023, time.October, 2, 0, 0, 0, 0, toronto))
+	r.NoError(err)
+	r.Equal("023275", s)
+
+	_, err = convertTimestampToEftDate(time.Date(1999, time.December, 31, 0, 0, 0, 0, time.UTC))
+	r.Error(err)
+
+	parsed, err := parseDateInLocation("023275", toronto)
+	r.NoError(err)
+	r.Equal(time.Date(2023, time.October, 2, 0, 0, 0, 0, toronto), parsed)
+}
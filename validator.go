@@ -1,8 +1,12 @@
 package cadeft
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -53,6 +57,88 @@ func eftCurrency(fl validator.FieldLevel) bool {
 	}
 }
 
+// fieldSentinels maps a struct field to the sentinel error reported when it fails validation
+var fieldSentinels = map[string]error{
+	"RecordType":                     ErrInvalidRecordType,
+	"FileCreationNum":                ErrInvalidFileCreationNum,
+	"CreationDate":                   ErrMissingCreationDate,
+	"DestinationDataCenterNo":        ErrInvalidDestinationDataCenterNo,
+	"DirectClearerCommunicationArea": ErrInvalidDirectClearerCommunicationArea,
+	"CurrencyCode":                   ErrInvalidCurrencyCode,
+	"TxnType":                        ErrInvalidTxnType,
+	"Amount":                         ErrInvalidAmount,
+	"DueDate":                        ErrInvalidDate,
+	"DateFundsAvailable":             ErrInvalidDate,
+	"InstitutionID":                  ErrInvalidInstitutionID,
+	"PayorAccountNo":                 ErrInvalidAccountNo,
+	"PayeeAccountNo":                 ErrInvalidAccountNo,
+	"ItemTraceNo":                    ErrInvalidItemTraceNo,
+	"StoredTransactionType":          ErrInvalidStoredTxnType,
+	"OriginatorShortName":            ErrInvalidOriginatorShortName,
+	"PayorName":                      ErrInvalidName,
+	"PayeeName":                      ErrInvalidName,
+	"OriginatorLongName":             ErrInvalidOriginatorLongName,
+	"UserID":                         ErrInvalidUserID,
+	"CrossRefNo":                     ErrInvalidCrossRefNo,
+	"ReturnInstitutionID":            ErrInvalidReturnInstitutionID,
+	"ReturnAccountNo":                ErrInvalidReturnAccountNo,
+	"OriginalInstitutionID":          ErrInvalidOriginalInstitutionID,
+	"OriginalAccountNo":              ErrInvalidOriginalAccountNo,
+	"OriginalItemTraceNo":            ErrInvalidOriginalItemTraceNo,
+	"SundryInfo":                     ErrInvalidSundryInfo,
+	"SettlementCode":                 ErrInvalidSettlementCode,
+	"InvalidDataElementID":           ErrInvalidInvalidDataElementID,
+}
+
+func fieldSentinel(field, rule string) error {
+	if field == "OriginatorID" {
+		switch rule {
+		case "required":
+			return ErrMissingOriginatorId
+		case "len":
+			return ErrInvalidOriginatorIdLength
+		default:
+			return ErrInvalidOriginatorId
+		}
+	}
+	if err, ok := fieldSentinels[field]; ok {
+		return err
+	}
+	return ErrInvalidField
+}
+
+// validateStruct runs eftValidator against s, a pointer to a record struct, and converts every failed field into a ValidationError
+func validateStruct(s any, recordType RecordType) error {
+	err := eftValidator.Struct(s)
+	if err == nil {
+		return nil
+	}
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+	structType := reflect.Indirect(reflect.ValueOf(s)).Type()
+	errs := make(ValidationErrors, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		errs = append(errs, newFieldValidationError(structType, recordType, fe))
+	}
+	return errs
+}
+
+func newFieldValidationError(structType reflect.Type, recordType RecordType, fe validator.FieldError) *ValidationError {
+	v := &ValidationError{
+		RecordType: recordType,
+		Field:      fe.StructField(),
+		Rule:       fe.Tag(),
+		Param:      fe.Param(),
+		Value:      fe.Value(),
+		Err:        fieldSentinel(fe.StructField(), fe.Tag()),
+		fieldErr:   fe,
+	}
+	v.JSONField, v.FieldNumber = specField(structType, fe.StructField())
+	return v
+}
+
 func c(e error) {
 	if e != nil {
 		panic(fmt.Sprintf("failed to register validators: %s", e)) //nolint:forbidigo
@@ -65,3 +151,14 @@ func init() {
 	c(eftValidator.RegisterValidation(eftRecTypeKey, eftRecType))
 	c(eftValidator.RegisterValidation(eftCurrencyKey, eftCurrency))
 }
+
+// specField returns the json name and spec field number of the named field of a record struct
+func specField(structType reflect.Type, field string) (string, int) {
+	sf, ok := structType.FieldByName(field)
+	if !ok {
+		return "", 0
+	}
+	jsonName, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	num, _ := strconv.Atoi(sf.Tag.Get("spec"))
+	return jsonName, num
+}
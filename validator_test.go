@@ -0,0 +1,89 @@
+package cadeft
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationErrors(t *testing.T) {
+	date := time.Date(2023, time.October, 2, 0, 0, 0, 0, time.UTC)
+	valid := NewCredit("450", 100, &date, "123456789", "123456789012", "", "SHORT-NAME", "RECEIVER NAME", "LONG-NAME", "123456789", "210987654321")
+
+	t.Run("txn fields", func(t *testing.T) {
+		r := require.New(t)
+		c := valid
+		c.Amount = 99999999999
+		err := c.Validate()
+		r.ErrorIs(err, ErrInvalidAmount)
+		r.NotErrorIs(err, ErrInvalidTxnType)
+
+		var vErr *ValidationError
+		r.True(errors.As(err, &vErr))
+		r.Nil(vErr.TxnIndex)
+		r.Equal(CreditRecord, vErr.RecordType)
+		r.Equal("Amount", vErr.Field)
+		r.Equal("amount", vErr.JSONField)
+		r.Equal(5, vErr.FieldNumber)
+		r.Equal("max", vErr.Rule)
+		r.Equal("9999999999", vErr.Param)
+		r.Equal(int64(99999999999), vErr.Value)
+		r.Equal(`record C field 05 Amount: invalid amount: failed "max=9999999999" validation`, err.Error())
+	})
+
+	t.Run("header fields", func(t *testing.T) {
+		r := require.New(t)
+		header := NewFileHeader("123", 1, &date, 12345, "CAD")
+		err := header.Validate()
+		r.ErrorIs(err, ErrInvalidOriginatorIdLength)
+		var vErr *ValidationError
+		r.True(errors.As(err, &vErr))
+		r.Equal(HeaderRecord, vErr.RecordType)
+		r.Equal("originator_id", vErr.JSONField)
+		r.Equal(3, vErr.FieldNumber)
+	})
+
+	t.Run("file aggregates with txn index", func(t *testing.T) {
+		r := require.New(t)
+		invalid := valid
+		invalid.TxnType = "4X0"
+		invalid.PayeeAccountNo = ""
+		file := NewFile(NewFileHeader("0000000001", 1, &date, 12345, "CAD"), Transactions{&valid, &invalid})
+		err := file.Validate()
+		r.ErrorIs(err, ErrInvalidTxnType)
+		r.ErrorIs(err, ErrInvalidAccountNo)
+
+		var vErrs ValidationErrors
+		r.True(errors.As(err, &vErrs))
+		r.Len(vErrs, 2)
+		for _, v := range vErrs {
+			r.NotNil(v.TxnIndex)
+			r.Equal(1, *v.TxnIndex)
+		}
+		// the transaction keeps reporting errors without an index
+		var txnErr *ValidationError
+		r.True(errors.As(invalid.Validate(), &txnErr))
+		r.Nil(txnErr.TxnIndex)
+
+		out, err := json.Marshal(vErrs[0])
+		r.NoError(err)
+		var decoded map[string]any
+		r.NoError(json.Unmarshal(out, &decoded))
+		r.Equal(float64(1), decoded["txn_index"])
+		r.Equal("C", decoded["record_type"])
+		r.Equal("txn_type", decoded["json_field"])
+		r.Equal(float64(4), decoded["field_number"])
+		r.Equal("numeric", decoded["rule"])
+		r.Contains(decoded["message"], "invalid transaction type")
+	})
+
+	t.Run("missing header", func(t *testing.T) {
+		r := require.New(t)
+		err := NewFile(nil, Transactions{&valid}).Validate()
+		r.ErrorIs(err, ErrMissingHeader)
+		r.NoError(NewFile(NewFileHeader("0000000001", 1, &date, 12345, "CAD"), Transactions{&valid}).Validate())
+	})
+}